@@ -5,119 +5,809 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/shouni/go-cli-base"
+	"github.com/shouni/go-notifier/pkg/config"
 	"github.com/shouni/go-notifier/pkg/notifier"
 	"github.com/spf13/cobra"
 )
 
 // Backlog 固有の設定フラグ変数
 var (
-	projectIDStr string
-	issueID      string
+	projectIDStr  string
+	issueID       string
+	updateComment string
+	issueTypeFlag string
+	priorityFlag  string
+	emojiFlag     string
 )
 
-// getBacklogNotifier は環境変数チェックを行い、Backlog Notifierを生成します。
+// 課題登録の任意項目のフラグ変数
+var (
+	issueAssignee       string
+	issueStartDate      string
+	issueDueDate        string
+	issueEstimatedHours float64
+	issueCategories     []string
+	issueMilestones     []string
+	issueVersions       []string
+	issueParent         string
+	issueCustomFields   []string
+	issueAttachments    []string
+	commentNotify       []string
+)
+
+// 重複登録の防止 (--dedup) のフラグ変数
+var (
+	issueDedup            bool
+	issueFingerprint      string
+	issueFingerprintField string
+	issueReopen           bool
+)
+
+// 課題の状態変更のフラグ変数
+var (
+	issueStatus     string
+	issueResolution string
+)
+
+// getBacklogNotifier は、設定ファイルの通知先・フラグ・環境変数から Backlog Notifierを生成します。
+// 優先順位は、明示的なフラグ > 設定ファイル (--target / --profile) > 環境変数 です。
 // sharedClient は PersistentPreRunE で初期化済みのため、そのまま使用します。
-func getBacklogNotifier() (*notifier.BacklogNotifier, error) {
-	backlogSpaceURL := os.Getenv("BACKLOG_SPACE_URL")
-	backlogAPIKey := os.Getenv("BACKLOG_API_KEY")
+func getBacklogNotifier(cmd *cobra.Command) (*notifier.BacklogNotifier, error) {
+	var target config.BacklogTarget
+	name, err := resolveSingleTarget(config.KindBacklog)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		if target, err = appConfig.BacklogTarget(name); err != nil {
+			return nil, err
+		}
+	}
+
+	backlogSpaceURL := envOrConfig("BACKLOG_SPACE_URL", target.SpaceURL)
+	backlogAPIKey := envOrConfig("BACKLOG_API_KEY", target.APIKey)
 	if backlogSpaceURL == "" || backlogAPIKey == "" {
-		return nil, fmt.Errorf("BACKLOG_SPACE_URL または BACKLOG_API_KEY 環境変数が設定されていません")
+		return nil, fmt.Errorf("BACKLOG_SPACE_URL または BACKLOG_API_KEY 環境変数 (または設定ファイルの space_url / api_key) が設定されていません")
 	}
 
 	// Notifierの初期化に sharedClient を使用
-	return notifier.NewBacklogNotifier(*sharedClient, backlogSpaceURL, backlogAPIKey)
+	backlogNotifier, err := notifier.NewBacklogNotifier(*sharedClient, backlogSpaceURL, backlogAPIKey)
+	if err != nil {
+		return nil, err
+	}
+	backlogNotifier.ProjectKey = flagOrConfig(cmd, "project-id", projectIDStr, target.Project)
+	backlogNotifier.IssueType = flagOrConfig(cmd, "issue-type", issueTypeFlag, target.IssueType)
+	backlogNotifier.Priority = flagOrConfig(cmd, "priority", priorityFlag, target.Priority)
+	if backlogNotifier.EmojiPolicy, err = notifier.ParseEmojiPolicy(flagOrConfig(cmd, "emoji", emojiFlag, target.Emoji)); err != nil {
+		return nil, err
+	}
+	backlogNotifier.EmojiASCII = target.EmojiASCII
+	configureBacklogCache(backlogNotifier)
+
+	return backlogNotifier, nil
+}
+
+// configureBacklogCache は、--no-cache フラグに従って Backlog のメタデータのキャッシュを設定します。
+// CLI は実行ごとに終了するため、キャッシュはユーザーのキャッシュディレクトリ配下のファイルに保存し、実行をまたいで再利用します。
+// ファイルのキャッシュを使用できない場合は、メモリ上のキャッシュのまま続行します。
+func configureBacklogCache(bn *notifier.BacklogNotifier) {
+	if Flags.NoCache {
+		bn.Cache = nil
+		return
+	}
+	if err := bn.UseFileCache(notifier.DefaultBacklogCacheTTL); err != nil {
+		if clibase.Flags.Verbose {
+			log.Printf("⚠️ キャッシュファイルを使用できないため、メモリ上のキャッシュを使用します: %v", err)
+		}
+		bn.Cache = notifier.NewBacklogMetadataCache(notifier.DefaultBacklogCacheTTL)
+	}
 }
 
 // --- サブコマンド: backlog (課題登録) ---
 
 // backlogCmd は Cobra の Backlog 課題登録用サブコマンドです
+// 子コマンドなしで実行した場合は、後方互換のため backlog issue create と同じく課題を登録します。
 var backlogCmd = &cobra.Command{
 	Use:   "backlog",
 	Short: "Backlogへの課題登録またはコメント投稿を管理します",
-	Long:  `環境変数 BACKLOG_SPACE_URL と BACKLOG_API_KEY が設定されている必要があります。`,
+	Long:  `環境変数 BACKLOG_SPACE_URL と BACKLOG_API_KEY、または設定ファイルの通知先 (--target / --profile) が必要です。`,
+	Run:   runIssueCreate,
+}
+
+// --- サブコマンド: issue (backlogの子) ---
+
+// backlogIssueCmd は Backlog 課題の操作をまとめるサブコマンドです
+var backlogIssueCmd = &cobra.Command{
+	Use:   "issue",
+	Short: "Backlog の課題を登録・取得・更新します",
+}
+
+// backlogIssueCreateCmd は Backlog に課題を登録するサブコマンドです
+var backlogIssueCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "課題を登録します (-t: 件名, -m: 詳細)",
+	Run:   runIssueCreate,
+}
+
+// runIssueCreate は、-t / -m フラグの内容で --project-id のプロジェクトに課題を登録します。
+func runIssueCreate(cmd *cobra.Command, args []string) {
+	backlogNotifier := mustBacklogNotifier(cmd)
+
+	// プロジェクトIDの取得とチェック
+	projectID, err := backlogNotifier.GetProjectID(context.Background(), backlogNotifier.ProjectKey)
+	if err != nil {
+		log.Fatalf("🚨 致命的なエラー: プロジェクトIDの取得に失敗しました: %v", err)
+	}
+
+	if Flags.Title == "" {
+		log.Fatal("🚨 致命的なエラー: 課題のタイトルがありません。-t フラグでタイトルを指定してください。")
+	}
+
+	if Flags.Message == "" {
+		log.Fatal("🚨 致命的なエラー: 課題のメッセージがありません。-m フラグでメッセージを指定してください。")
+	}
+
+	customFields, err := parseCustomFields(issueCustomFields)
+	if err != nil {
+		log.Fatalf("🚨 致命的なエラー: %v", err)
+	}
+
+	attachments, closeAttachments, err := openAttachments(issueAttachments)
+	if err != nil {
+		log.Fatalf("🚨 致命的なエラー: %v", err)
+	}
+	defer closeAttachments()
+
+	opts := notifier.BacklogIssueOptions{
+		Assignee:       issueAssignee,
+		StartDate:      issueStartDate,
+		DueDate:        issueDueDate,
+		EstimatedHours: issueEstimatedHours,
+		Categories:     issueCategories,
+		Milestones:     issueMilestones,
+		Versions:       issueVersions,
+		ParentIssue:    issueParent,
+		CustomFields:   customFields,
+		Attachments:    attachments,
+	}
+
+	// 重複登録の防止が指定された場合は、同じ事象の既存の課題へのコメントに切り替える
+	if issueDedup || issueFingerprint != "" || issueFingerprintField != "" {
+		ensureIssue(backlogNotifier, projectID, opts)
+		return
+	}
+
+	// 2. 投稿実行（SendIssueを使用）
+	issue, err := backlogNotifier.SendIssue(
+		context.Background(),
+		Flags.Title,   // Backlogの課題サマリーとして使用
+		Flags.Message, // Backlogの課題説明として使用
+		projectID,
+		opts,
+	)
+	if err != nil {
+		log.Fatalf("🚨 Backlogへの投稿に失敗しました: %v", err)
+	}
+
+	log.Printf("✅ Backlogへの課題登録が完了しました (%s)。", issue.IssueKey)
+	if len(issue.Truncations) > 0 {
+		log.Printf("⚠️ 上限を超えたため一部を切り詰めました (%v)", issue.Truncations)
+	}
+	rows := [][]string{
+		{"キー", issue.IssueKey},
+		{"ID", strconv.Itoa(issue.ID)},
+		{"URL", issue.URL},
+		{"作成日時", issue.Created},
+	}
+	if err := printResult(issue, []string{"項目", "値"}, rows); err != nil {
+		log.Fatalf("🚨 致命的なエラー: %v", err)
+	}
+}
+
+// ensureIssue は、同じ事象の課題があればコメントを追加し、なければ課題を登録します (--dedup)。
+func ensureIssue(backlogNotifier *notifier.BacklogNotifier, projectID int, opts notifier.BacklogIssueOptions) {
+	result, err := backlogNotifier.EnsureIssue(context.Background(), Flags.Title, Flags.Message, projectID, notifier.BacklogEnsureOptions{
+		FingerprintField: issueFingerprintField,
+		Fingerprint:      issueFingerprint,
+		Reopen:           issueReopen,
+		Issue:            opts,
+	})
+	if err != nil {
+		log.Fatalf("🚨 Backlogへの投稿に失敗しました: %v", err)
+	}
+
+	switch {
+	case result.Created:
+		log.Printf("✅ Backlogへの課題登録が完了しました (%s)。", result.Issue.IssueKey)
+		if len(result.Issue.Truncations) > 0 {
+			log.Printf("⚠️ 上限を超えたため一部を切り詰めました (%v)", result.Issue.Truncations)
+		}
+	case result.Reopened:
+		log.Printf("✅ 完了済みの課題 (%s) を未対応に戻し、再発をコメントしました (発生回数: %d)。", result.Issue.IssueKey, result.Occurrences)
+	default:
+		log.Printf("✅ 既存の課題 (%s) に再発をコメントしました (発生回数: %d)。", result.Issue.IssueKey, result.Occurrences)
+	}
+	rows := [][]string{
+		{"キー", result.Issue.IssueKey},
+		{"ID", strconv.Itoa(result.Issue.ID)},
+		{"URL", result.Issue.URL},
+		{"発生回数", strconv.Itoa(result.Occurrences)},
+	}
+	if result.Fingerprint != "" {
+		rows = append(rows, []string{"フィンガープリント", result.Fingerprint})
+	}
+	if err := printResult(result, []string{"項目", "値"}, rows); err != nil {
+		log.Fatalf("🚨 致命的なエラー: %v", err)
+	}
+}
+
+// backlogIssueGetCmd は Backlog の課題を取得して表示するサブコマンドです
+var backlogIssueGetCmd = &cobra.Command{
+	Use:   "get [課題キー]",
+	Short: "課題を取得して表示します",
+	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		backlogNotifier, err := getBacklogNotifier()
+		key := issueKeyArg(args)
+		backlogNotifier := mustBacklogNotifier(cmd)
+
+		issue, err := backlogNotifier.GetIssue(context.Background(), key)
 		if err != nil {
-			log.Fatalf("🚨 Backlog Notifierの初期化に失敗しました: %v", err)
+			log.Fatalf("🚨 Backlog課題の取得に失敗しました: %v", err)
+		}
+
+		if err := printIssue(backlogNotifier, issue); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
 		}
+	},
+}
+
+// backlogIssueUpdateCmd は Backlog の課題を更新するサブコマンドです
+var backlogIssueUpdateCmd = &cobra.Command{
+	Use:   "update [課題キー]",
+	Short: "課題の件名 (-t)・詳細 (-m)・状態・担当者などを更新します (--comment で同時にコメントを追加)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key := issueKeyArg(args)
+		backlogNotifier := mustBacklogNotifier(cmd)
 
-		// プロジェクトIDの取得とチェック
-		projectID, err := backlogNotifier.GetProjectID(context.Background(), projectIDStr)
+		customFields, err := parseCustomFields(issueCustomFields)
 		if err != nil {
-			log.Fatalf("🚨 致命的なエラー: プロジェクトIDの取得に失敗しました: %v", err)
+			log.Fatalf("🚨 致命的なエラー: %v", err)
 		}
 
-		// 🚨 修正点1: 課題サマリーのチェックで Flags.Header を使用
-		if Flags.Title == "" {
-			log.Fatal("🚨 致命的なエラー: 課題のタイトルがありません。-t フラグでタイトルを指定してください。")
+		attachments, closeAttachments, err := openAttachments(issueAttachments)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
 		}
+		defer closeAttachments()
 
-		if Flags.Message == "" {
-			log.Fatal("🚨 致命的なエラー: 課題のメッセージがありません。-m フラグでメッセージを指定してください。")
+		issue, err := backlogNotifier.UpdateIssue(context.Background(), key, notifier.BacklogIssueUpdate{
+			Summary:      Flags.Title,
+			Description:  Flags.Message,
+			Status:       issueStatus,
+			Resolution:   issueResolution,
+			Assignee:     issueAssignee,
+			Comment:      updateComment,
+			CustomFields: customFields,
+			Attachments:  attachments,
+		})
+		if err != nil {
+			log.Fatalf("🚨 Backlog課題の更新に失敗しました: %v", err)
 		}
 
-		// 2. 投稿実行（SendIssueを使用）
-		if err := backlogNotifier.SendIssue(
-			context.Background(),
-			Flags.Title,   // Backlogの課題サマリーとして使用
-			Flags.Message, // Backlogの課題説明として使用
-			projectID,
-		); err != nil {
-			log.Fatalf("🚨 Backlogへの投稿に失敗しました: %v", err)
+		log.Printf("✅ Backlog課題 (%s) の更新が完了しました。", issue.IssueKey)
+		if err := printIssue(backlogNotifier, issue); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
 		}
+	},
+}
+
+// backlogIssueStatusCmd は課題の状態を変更するサブコマンドです
+var backlogIssueStatusCmd = &cobra.Command{
+	Use:   "status <課題キー> <状態>",
+	Short: "課題の状態を ID または名前 (例: 処理中) で変更します",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		backlogNotifier := mustBacklogNotifier(cmd)
 
-		log.Println("✅ Backlogへの課題登録が完了しました。")
+		issue, err := backlogNotifier.ChangeStatus(context.Background(), args[0], args[1], updateComment)
+		if err != nil {
+			log.Fatalf("🚨 Backlog課題の状態の変更に失敗しました: %v", err)
+		}
+
+		log.Printf("✅ Backlog課題 (%s) の状態を変更しました。", issue.IssueKey)
+		if err := printIssue(backlogNotifier, issue); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+	},
+}
+
+// backlogIssueResolveCmd は課題の完了理由を設定するサブコマンドです
+var backlogIssueResolveCmd = &cobra.Command{
+	Use:   "resolve <課題キー> <完了理由>",
+	Short: "課題の完了理由を ID または名前 (例: 対応済み) で設定します",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		backlogNotifier := mustBacklogNotifier(cmd)
+
+		issue, err := backlogNotifier.SetResolution(context.Background(), args[0], args[1], updateComment)
+		if err != nil {
+			log.Fatalf("🚨 Backlog課題の完了理由の設定に失敗しました: %v", err)
+		}
+
+		log.Printf("✅ Backlog課題 (%s) の完了理由を設定しました。", issue.IssueKey)
+		if err := printIssue(backlogNotifier, issue); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+	},
+}
+
+// backlogIssueCloseCmd は課題を完了にするサブコマンドです
+var backlogIssueCloseCmd = &cobra.Command{
+	Use:   "close [課題キー]",
+	Short: "課題の状態を「完了」にします (--resolution で完了理由、--comment でコメントを追加)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key := issueKeyArg(args)
+		backlogNotifier := mustBacklogNotifier(cmd)
+
+		issue, err := backlogNotifier.CloseIssue(context.Background(), key, issueResolution, updateComment)
+		if err != nil {
+			log.Fatalf("🚨 Backlog課題の完了に失敗しました: %v", err)
+		}
+
+		log.Printf("✅ Backlog課題 (%s) を完了にしました。", issue.IssueKey)
+		if err := printIssue(backlogNotifier, issue); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
 	},
 }
 
 // --- サブコマンド: comment (backlogの子) ---
 
 // commentCmd は Backlog 既存課題へのコメント投稿用サブコマンドです
+// 子コマンドなしで実行した場合は、後方互換のため comment add と同じくコメントを投稿します。
 var commentCmd = &cobra.Command{
 	Use:   "comment",
 	Short: "既存の課題にコメントを追記します",
+	Run:   runCommentAdd,
+}
+
+// commentAddCmd は Backlog 既存課題へコメントを追加するサブコマンドです
+var commentAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "既存の課題にコメントを追加します (-m: コメント本文)",
+	Run:   runCommentAdd,
+}
+
+// runCommentAdd は、-m フラグの内容を --issue-id の課題にコメントとして投稿します。
+func runCommentAdd(cmd *cobra.Command, args []string) {
+	if Flags.Message == "" {
+		log.Fatal("🚨 致命的なエラー: 投稿メッセージがありません。-m フラグでメッセージを指定してください。")
+	}
+
+	// 課題キー (PROJECT-123) と課題ID (数字) のどちらも指定できる
+	if issueID == "" {
+		log.Fatal("🚨 致命的なエラー: --issue-id フラグでコメント対象の課題キーまたは課題IDを指定してください。")
+	}
+
+	backlogNotifier := mustBacklogNotifier(cmd)
+
+	attachments, closeAttachments, err := openAttachments(issueAttachments)
+	if err != nil {
+		log.Fatalf("🚨 致命的なエラー: %v", err)
+	}
+	defer closeAttachments()
+
+	// 投稿実行 (PostComment に課題キーまたは課題IDとメッセージを渡す)
+	comment, err := backlogNotifier.PostComment(
+		context.Background(),
+		issueID,
+		Flags.Message,
+		notifier.BacklogCommentOptions{Attachments: attachments, NotifiedUsers: commentNotify},
+	)
+	if err != nil {
+		log.Fatalf("🚨 Backlogへのコメント投稿に失敗しました: %v", err)
+	}
+
+	log.Printf("✅ Backlog課題 (%s) へのコメント投稿が完了しました。", issueID)
+	rows := [][]string{
+		{"コメントID", strconv.Itoa(comment.ID)},
+		{"課題", comment.IssueKey},
+		{"URL", comment.URL},
+		{"作成日時", comment.Created},
+	}
+	if err := printResult(comment, []string{"項目", "値"}, rows); err != nil {
+		log.Fatalf("🚨 致命的なエラー: %v", err)
+	}
+}
+
+// --- サブコマンド: projects / issue-types / priorities などのメタデータ (backlogの子) ---
+
+// backlogProjectsCmd は Backlog のプロジェクトを扱うサブコマンドです
+var backlogProjectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "Backlog のプロジェクトを表示します",
+}
+
+// backlogProjectsListCmd は参加しているプロジェクトの一覧を表示するサブコマンドです
+var backlogProjectsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "参加しているプロジェクトの一覧を表示します",
+	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		// 🚨 修正点2: 投稿メッセージのチェックで Flags.Message を使用
-		if Flags.Message == "" {
-			log.Fatal("🚨 致命的なエラー: 投稿メッセージがありません。-m フラグでメッセージを指定してください。")
+		backlogNotifier := mustBacklogNotifier(cmd)
+
+		projects, err := backlogNotifier.ListProjects(context.Background())
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		rows := make([][]string, 0, len(projects))
+		for _, p := range projects {
+			rows = append(rows, []string{strconv.Itoa(p.ID), p.Key, p.Name})
+		}
+		if err := printResult(projects, []string{"ID", "KEY", "NAME"}, rows); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+	},
+}
+
+// backlogIssueTypesCmd は Backlog の課題種別を扱うサブコマンドです
+var backlogIssueTypesCmd = &cobra.Command{
+	Use:   "issue-types",
+	Short: "プロジェクトの課題種別を表示します",
+}
+
+// backlogIssueTypesListCmd は --project-id のプロジェクトの課題種別の一覧を表示するサブコマンドです
+var backlogIssueTypesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "プロジェクト (--project-id) の課題種別の一覧を表示します",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		backlogNotifier := mustBacklogNotifier(cmd)
+
+		projectID, err := backlogNotifier.GetProjectID(context.Background(), backlogNotifier.ProjectKey)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: プロジェクトIDの取得に失敗しました: %v", err)
+		}
+
+		issueTypes, err := backlogNotifier.ListIssueTypes(context.Background(), projectID)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		rows := make([][]string, 0, len(issueTypes))
+		for _, it := range issueTypes {
+			rows = append(rows, []string{strconv.Itoa(it.ID), it.Name})
+		}
+		if err := printResult(issueTypes, []string{"ID", "NAME"}, rows); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+	},
+}
+
+// backlogCustomFieldsCmd は Backlog のカスタム属性を扱うサブコマンドです
+var backlogCustomFieldsCmd = &cobra.Command{
+	Use:   "custom-fields",
+	Short: "プロジェクトのカスタム属性を表示します",
+}
+
+// backlogCustomFieldsListCmd は --project-id のプロジェクトのカスタム属性の一覧を表示するサブコマンドです
+var backlogCustomFieldsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "プロジェクト (--project-id) のカスタム属性の一覧を表示します",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		backlogNotifier := mustBacklogNotifier(cmd)
+
+		projectID, err := backlogNotifier.GetProjectID(context.Background(), backlogNotifier.ProjectKey)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: プロジェクトIDの取得に失敗しました: %v", err)
 		}
 
-		if issueID == "" {
-			log.Fatal("🚨 致命的なエラー: --issue-id フラグでコメント対象の課題キーを指定してください。")
+		fields, err := backlogNotifier.ListCustomFields(context.Background(), projectID)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
 		}
 
-		if !strings.Contains(issueID, "-") {
-			log.Fatalf("🚨 致命的なエラー: --issue-id の値が不正な形式です。例: PROJECT-123 (含まれているハイフンがありません)")
+		rows := make([][]string, 0, len(fields))
+		for _, f := range fields {
+			required := ""
+			if f.Required {
+				required = "必須"
+			}
+			items := make([]string, 0, len(f.Items))
+			for _, item := range f.Items {
+				items = append(items, item.Name)
+			}
+			rows = append(rows, []string{strconv.Itoa(f.ID), f.Name, f.TypeID.String(), required, strings.Join(items, ", ")})
+		}
+		if err := printResult(fields, []string{"ID", "NAME", "TYPE", "REQUIRED", "ITEMS"}, rows); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
 		}
+	},
+}
+
+// backlogPrioritiesCmd は Backlog の優先度を扱うサブコマンドです
+var backlogPrioritiesCmd = &cobra.Command{
+	Use:   "priorities",
+	Short: "優先度を表示します",
+}
 
-		backlogNotifier, err := getBacklogNotifier()
+// backlogPrioritiesListCmd は優先度の一覧を表示するサブコマンドです
+var backlogPrioritiesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "優先度の一覧を表示します",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		backlogNotifier := mustBacklogNotifier(cmd)
+
+		priorities, err := backlogNotifier.ListPriorities(context.Background())
 		if err != nil {
-			log.Fatalf("🚨 Backlog Notifierの初期化に失敗しました: %v", err)
+			log.Fatalf("🚨 致命的なエラー: %v", err)
 		}
 
-		// 投稿実行（SendCommentを使用 - 課題キーとメッセージを渡す）
-		// 🚨 修正点3: 投稿メッセージに Flags.Message を使用
-		if err := backlogNotifier.PostComment(
-			context.Background(),
-			issueID,
-			Flags.Message,
-		); err != nil {
-			log.Fatalf("🚨 Backlogへのコメント投稿に失敗しました: %v", err)
+		rows := make([][]string, 0, len(priorities))
+		for _, p := range priorities {
+			rows = append(rows, []string{strconv.Itoa(p.ID), p.Name})
 		}
+		if err := printResult(priorities, []string{"ID", "NAME"}, rows); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+	},
+}
+
+// backlogCacheCmd は Backlog のメタデータのキャッシュを扱うサブコマンドです
+var backlogCacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "メタデータのキャッシュを管理します",
+}
 
-		log.Printf("✅ Backlog課題 (%s) へのコメント投稿が完了しました。", issueID)
+// backlogCacheClearCmd はメタデータのキャッシュを破棄するサブコマンドです
+var backlogCacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "プロジェクト・課題種別・優先度などのメタデータのキャッシュを破棄します",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		backlogNotifier := mustBacklogNotifier(cmd)
+
+		// --no-cache の指定にかかわらず、キャッシュファイルを破棄する
+		if err := backlogNotifier.UseFileCache(notifier.DefaultBacklogCacheTTL); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+		if err := backlogNotifier.InvalidateCache(); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+		log.Println("✅ Backlog のメタデータのキャッシュを破棄しました。")
 	},
 }
 
+// backlogStatusesCmd は Backlog の課題の状態を扱うサブコマンドです
+var backlogStatusesCmd = &cobra.Command{
+	Use:   "statuses",
+	Short: "課題の状態を表示します",
+}
+
+// backlogStatusesListCmd はプロジェクトの課題の状態の一覧を表示するサブコマンドです
+var backlogStatusesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "プロジェクトの課題の状態の一覧を表示します (-p: プロジェクトキー)",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		backlogNotifier := mustBacklogNotifier(cmd)
+
+		projectID, err := backlogNotifier.GetProjectID(context.Background(), backlogNotifier.ProjectKey)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: プロジェクトIDの取得に失敗しました: %v", err)
+		}
+
+		statuses, err := backlogNotifier.ListStatuses(context.Background(), projectID)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		rows := make([][]string, 0, len(statuses))
+		for _, s := range statuses {
+			rows = append(rows, []string{strconv.Itoa(s.ID), s.Name})
+		}
+		if err := printResult(statuses, []string{"ID", "NAME"}, rows); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+	},
+}
+
+// backlogResolutionsCmd は Backlog の完了理由を扱うサブコマンドです
+var backlogResolutionsCmd = &cobra.Command{
+	Use:   "resolutions",
+	Short: "完了理由を表示します",
+}
+
+// backlogResolutionsListCmd は完了理由の一覧を表示するサブコマンドです
+var backlogResolutionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "完了理由の一覧を表示します",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		backlogNotifier := mustBacklogNotifier(cmd)
+
+		resolutions, err := backlogNotifier.ListResolutions(context.Background())
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		rows := make([][]string, 0, len(resolutions))
+		for _, r := range resolutions {
+			rows = append(rows, []string{strconv.Itoa(r.ID), r.Name})
+		}
+		if err := printResult(resolutions, []string{"ID", "NAME"}, rows); err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+	},
+}
+
+// mustBacklogNotifier は、出力形式を検証したうえで Backlog Notifier を生成します。失敗した場合は終了します。
+func mustBacklogNotifier(cmd *cobra.Command) *notifier.BacklogNotifier {
+	if err := validateOutputFormat(); err != nil {
+		log.Fatalf("🚨 致命的なエラー: %v", err)
+	}
+
+	backlogNotifier, err := getBacklogNotifier(cmd)
+	if err != nil {
+		log.Fatalf("🚨 Backlog Notifierの初期化に失敗しました: %v", err)
+	}
+	return backlogNotifier
+}
+
+// parseCustomFields は、--custom-field フラグの name=value 形式の値をカスタム属性の値に変換します。
+func parseCustomFields(values []string) (*notifier.BacklogCustomFields, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	fields := &notifier.BacklogCustomFields{}
+	for _, v := range values {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("--custom-field の値が不正な形式です: %q (name=value 形式で指定してください)", v)
+		}
+		fields.Set(strings.TrimSpace(name), value)
+	}
+	return fields, nil
+}
+
+// openAttachments は、--attach フラグで指定されたファイルを開き、添付ファイルに変換します。
+// 戻り値の関数で、開いたファイルを閉じます。
+func openAttachments(paths []string) ([]notifier.BacklogAttachment, func(), error) {
+	var files []*os.File
+	closeAll := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+
+	attachments := make([]notifier.BacklogAttachment, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("添付ファイルを開けません: %w", err)
+		}
+		files = append(files, f)
+		attachments = append(attachments, notifier.BacklogAttachment{Name: filepath.Base(path), Reader: f})
+	}
+	return attachments, closeAll, nil
+}
+
+// issueKeyArg は、引数または --issue-id フラグから対象の課題キーを決定します。
+func issueKeyArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	if issueID == "" {
+		log.Fatal("🚨 致命的なエラー: 課題キーを引数または --issue-id フラグで指定してください。")
+	}
+	return issueID
+}
+
+// printIssue は、課題を --output フラグに従って表示します。
+func printIssue(backlogNotifier *notifier.BacklogNotifier, issue *notifier.BacklogIssueResponse) error {
+	rows := [][]string{
+		{"キー", issue.IssueKey},
+		{"件名", issue.Summary},
+	}
+	if issue.IssueType != nil {
+		rows = append(rows, []string{"種別", issue.IssueType.Name})
+	}
+	if issue.Priority != nil {
+		rows = append(rows, []string{"優先度", issue.Priority.Name})
+	}
+	if issue.Status != nil {
+		rows = append(rows, []string{"状態", issue.Status.Name})
+	}
+	if issue.Resolution != nil {
+		rows = append(rows, []string{"完了理由", issue.Resolution.Name})
+	}
+	if issue.Assignee != nil {
+		rows = append(rows, []string{"担当者", issue.Assignee.Name})
+	}
+	for _, row := range [][]string{
+		{"開始日", issue.StartDate},
+		{"期限日", issue.DueDate},
+		{"作成日時", issue.Created},
+		{"更新日時", issue.Updated},
+	} {
+		if row[1] != "" {
+			rows = append(rows, row)
+		}
+	}
+	rows = append(rows, []string{"URL", backlogNotifier.IssueURL(issue.IssueKey)})
+	return printResult(issue, []string{"項目", "値"}, rows)
+}
+
 func init() {
 	// init() 内での projectIDStr の環境変数からの初期設定はフラグ定義に統合する
 
-	// backlogCmd のフラグ定義
+	// backlogCmd のフラグ定義 (子コマンドでも使用するため永続フラグとする)
 	projectIDEnv := os.Getenv("BACKLOG_PROJECT_ID")
-	backlogCmd.Flags().StringVarP(&projectIDStr, "project-id", "p", projectIDEnv, "【必須】課題を登録する Backlog のプロジェクトID (ENV: BACKLOG_PROJECT_ID)")
+	backlogCmd.PersistentFlags().StringVarP(&projectIDStr, "project-id", "p", projectIDEnv, "【必須】課題を登録する Backlog のプロジェクトID (ENV: BACKLOG_PROJECT_ID)")
+	backlogCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", outputTable, "出力形式 (table, json)")
+	backlogCmd.PersistentFlags().StringVar(&emojiFlag, "emoji", "", "課題やコメントの絵文字の変換方法 (shortcode, ascii, strip, keep。省略時は shortcode)")
+
+	// 課題登録 (backlog / backlog issue create) のフラグ定義
+	for _, c := range []*cobra.Command{backlogCmd, backlogIssueCreateCmd} {
+		c.Flags().StringVar(&issueTypeFlag, "issue-type", "", "課題種別の ID または名前 (大文字小文字を区別しない。省略時はプロジェクトの先頭の課題種別)")
+		c.Flags().StringVar(&priorityFlag, "priority", "", "優先度の ID または名前 (大文字小文字を区別しない。省略時は「中」)")
+		c.Flags().StringVar(&issueAssignee, "assignee", "", "担当者のユーザー ID、ログイン名または表示名")
+		c.Flags().StringVar(&issueStartDate, "start-date", "", "開始日 (yyyy-MM-dd)")
+		c.Flags().StringVar(&issueDueDate, "due-date", "", "期限日 (yyyy-MM-dd)")
+		c.Flags().Float64Var(&issueEstimatedHours, "estimated-hours", 0, "予定時間")
+		c.Flags().StringSliceVar(&issueCategories, "category", nil, "カテゴリーの ID または名前 (複数指定可)")
+		c.Flags().StringSliceVar(&issueMilestones, "milestone", nil, "マイルストーンの ID または名前 (複数指定可)")
+		c.Flags().StringSliceVar(&issueVersions, "affected-version", nil, "発生バージョンの ID または名前 (複数指定可)")
+		c.Flags().StringVar(&issueParent, "parent-issue", "", "親課題の課題キー (例: PROJECT-123) または課題ID")
+		c.Flags().StringArrayVar(&issueCustomFields, "custom-field", nil, "カスタム属性の値 (name=value 形式、複数指定可。リストはカンマ区切りで複数選択)")
+		c.Flags().StringArrayVar(&issueAttachments, "attach", nil, "課題に添付するファイルのパス (複数指定可)")
+		c.Flags().BoolVar(&issueDedup, "dedup", false, "同じ件名の未完了の課題があれば、新しい課題を登録せずに再発をコメントする")
+		c.Flags().StringVar(&issueFingerprintField, "fingerprint-field", "", "フィンガープリントを保持する文字列のカスタム属性の名前または ID (指定すると --dedup が有効になり、件名ではなくフィンガープリントで照合)")
+		c.Flags().StringVar(&issueFingerprint, "fingerprint", "", "重複判定に使用するフィンガープリント (指定すると --dedup が有効になり、件名ではなくフィンガープリントで照合。省略時は件名から計算)")
+		c.Flags().BoolVar(&issueReopen, "reopen", false, "--dedup で一致した課題が完了済みの場合に、未対応に戻す (省略時は新しい課題を登録)")
+	}
 
 	// commentCmd のフラグ定義
-	commentCmd.Flags().StringVarP(&issueID, "issue-id", "i", "", "【必須】コメントを投稿する Backlog 課題 ID (例: PROJECT-123)")
+	commentCmd.PersistentFlags().StringVarP(&issueID, "issue-id", "i", "", "【必須】コメントを投稿する Backlog 課題キーまたは課題ID (例: PROJECT-123, 12345)")
+	commentCmd.PersistentFlags().StringArrayVar(&issueAttachments, "attach", nil, "コメントに添付するファイルのパス (複数指定可)")
+	commentCmd.PersistentFlags().StringSliceVar(&commentNotify, "notify", nil, "お知らせを送るユーザーの ID、ログイン名または表示名 (複数指定可。本文中の @ログイン名 も対象)")
+	commentCmd.AddCommand(commentAddCmd)
+
+	// issue サブコマンドのフラグ定義
+	backlogIssueGetCmd.Flags().StringVarP(&issueID, "issue-id", "i", "", "取得する Backlog 課題キー (例: PROJECT-123)")
+	backlogIssueUpdateCmd.Flags().StringVarP(&issueID, "issue-id", "i", "", "更新する Backlog 課題キー (例: PROJECT-123)")
+	backlogIssueUpdateCmd.Flags().StringVar(&updateComment, "comment", "", "更新と同時に追加するコメント")
+	backlogIssueUpdateCmd.Flags().StringArrayVar(&issueCustomFields, "custom-field", nil, "更新するカスタム属性の値 (name=value 形式、複数指定可)")
+	backlogIssueUpdateCmd.Flags().StringArrayVar(&issueAttachments, "attach", nil, "添付するファイルのパス (複数指定可。--comment を指定した場合はコメントに添付)")
+	backlogIssueUpdateCmd.Flags().StringVar(&issueStatus, "status", "", "変更後の状態の ID または名前 (例: 処理中)")
+	backlogIssueUpdateCmd.Flags().StringVar(&issueResolution, "resolution", "", "完了理由の ID または名前 (例: 対応済み)")
+	backlogIssueUpdateCmd.Flags().StringVar(&issueAssignee, "assignee", "", "変更後の担当者のユーザー ID、ログイン名または表示名")
+	for _, c := range []*cobra.Command{backlogIssueStatusCmd, backlogIssueResolveCmd, backlogIssueCloseCmd} {
+		c.Flags().StringVar(&updateComment, "comment", "", "変更と同時に追加するコメント")
+	}
+	backlogIssueCloseCmd.Flags().StringVarP(&issueID, "issue-id", "i", "", "完了にする Backlog 課題キー (例: PROJECT-123)")
+	backlogIssueCloseCmd.Flags().StringVar(&issueResolution, "resolution", "", "完了理由の ID または名前 (例: 対応済み)")
+	backlogIssueCmd.AddCommand(backlogIssueCreateCmd, backlogIssueGetCmd, backlogIssueUpdateCmd, backlogIssueStatusCmd, backlogIssueResolveCmd, backlogIssueCloseCmd)
+
+	backlogProjectsCmd.AddCommand(backlogProjectsListCmd)
+	backlogIssueTypesCmd.AddCommand(backlogIssueTypesListCmd)
+	backlogPrioritiesCmd.AddCommand(backlogPrioritiesListCmd)
+	backlogCustomFieldsCmd.AddCommand(backlogCustomFieldsListCmd)
+	backlogStatusesCmd.AddCommand(backlogStatusesListCmd)
+	backlogResolutionsCmd.AddCommand(backlogResolutionsListCmd)
+	backlogCacheCmd.AddCommand(backlogCacheClearCmd)
+
+	backlogCmd.AddCommand(backlogIssueCmd, commentCmd, backlogProjectsCmd, backlogIssueTypesCmd, backlogPrioritiesCmd, backlogCustomFieldsCmd, backlogStatusesCmd, backlogResolutionsCmd, backlogCacheCmd)
 }
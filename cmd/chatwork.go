@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shouni/go-notifier/pkg/config"
+	"github.com/shouni/go-notifier/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+// Chatwork 固有の設定フラグ変数
+var (
+	chatworkRoomID    string
+	chatworkTo        []int
+	chatworkAssignees []int
+	chatworkDue       string
+)
+
+var chatworkCmd = &cobra.Command{
+	Use:   "chatwork",
+	Short: "Chatworkのルームに [info] 記法でメッセージを投稿します",
+	Long: `環境変数 CHATWORK_API_TOKEN と CHATWORK_ROOM_ID、もしくは設定ファイルの通知先 (--target / --profile) が必要です。
+タイトルは [info][title] のタイトル、メッセージは本文に変換され、--to で指定したアカウントを宛先 ([To:accountId]) に指定します。
+投稿したメッセージの ID を標準出力に出力します。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if Flags.Message == "" {
+			log.Fatal("🚨 致命的なエラー: 投稿メッセージがありません。-m フラグでメッセージを指定してください。")
+		}
+
+		msg, err := buildMessage()
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		chatworkNotifier, err := getChatworkNotifier(cmd)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		result, err := chatworkNotifier.Send(context.Background(), msg)
+		if err != nil {
+			log.Fatalf("🚨 Chatworkへの投稿に失敗しました: %v", err)
+		}
+
+		log.Println("✅ Chatworkへの投稿が完了しました。")
+		if result.ID != "" {
+			log.Printf("メッセージのURL: %s", result.URL)
+			fmt.Println(result.ID)
+		}
+	},
+}
+
+// --- サブコマンド: task (chatworkの子) ---
+
+// chatworkTaskCmd はルームにタスクを追加するサブコマンドです
+var chatworkTaskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Chatworkのルームに担当者と期限を指定してタスクを追加します",
+	Long: `-m で指定したメッセージをタスクの内容として、--assignee で指定したアカウントごとにタスクを追加します。
+--due には期限を yyyy-MM-dd (日付) または "yyyy-MM-dd HH:mm" (時刻) 形式で指定します。追加したタスクの ID を標準出力に出力します。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if Flags.Message == "" {
+			log.Fatal("🚨 致命的なエラー: タスクの内容がありません。-m フラグでメッセージを指定してください。")
+		}
+		if len(chatworkAssignees) == 0 {
+			log.Fatal("🚨 致命的なエラー: --assignee フラグでタスクの担当者のアカウント ID を指定してください。")
+		}
+
+		opts := notifier.ChatworkTaskOptions{AssigneeIDs: chatworkAssignees}
+		if chatworkDue != "" {
+			due, hasTime, err := parseChatworkDue(chatworkDue)
+			if err != nil {
+				log.Fatalf("🚨 致命的なエラー: %v", err)
+			}
+			opts.Due = due
+			opts.DueHasTime = hasTime
+		}
+
+		chatworkNotifier, err := getChatworkNotifier(cmd)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		resp, err := chatworkNotifier.CreateTask(context.Background(), Flags.Message, opts)
+		if err != nil {
+			log.Fatalf("🚨 Chatworkへのタスクの追加に失敗しました: %v", err)
+		}
+
+		log.Printf("✅ Chatworkへのタスクの追加が完了しました。(%d件)", len(resp.TaskIDs))
+		for _, id := range resp.TaskIDs {
+			fmt.Println(id)
+		}
+	},
+}
+
+// parseChatworkDue は、--due フラグの値をローカル時刻として解釈し、時刻の指定があるかどうかとともに返します。
+func parseChatworkDue(s string) (time.Time, bool, error) {
+	if due, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local); err == nil {
+		return due, true, nil
+	}
+	due, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("期限の形式が不正です: %q (yyyy-MM-dd または \"yyyy-MM-dd HH:mm\" 形式で指定してください)", s)
+	}
+	return due, false, nil
+}
+
+// getChatworkNotifier は、設定ファイルの通知先・フラグ・環境変数から Chatwork Notifierを生成します。
+// 優先順位は、明示的なフラグ > 設定ファイル (--target / --profile) > 環境変数 です。
+func getChatworkNotifier(cmd *cobra.Command) (*notifier.ChatworkNotifier, error) {
+	var target config.ChatworkTarget
+	name, err := resolveSingleTarget(config.KindChatwork)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		if target, err = appConfig.ChatworkTarget(name); err != nil {
+			return nil, err
+		}
+	}
+
+	apiToken := envOrConfig("CHATWORK_API_TOKEN", target.APIToken)
+	roomID := strings.TrimSpace(flagOrConfig(cmd, "room-id", chatworkRoomID, target.RoomID))
+	if apiToken == "" || roomID == "" {
+		return nil, fmt.Errorf("CHATWORK_API_TOKEN / CHATWORK_ROOM_ID 環境変数 (または --room-id フラグ)、または設定ファイルの api_token / room_id が設定されていません")
+	}
+
+	// sharedClient は PersistentPreRunE で初期化済みのためそのまま利用
+	chatworkNotifier, err := notifier.NewChatworkNotifier(*sharedClient, apiToken, roomID)
+	if err != nil {
+		return nil, err
+	}
+	chatworkNotifier.To = target.To
+	if cmd.Flags().Changed("to") {
+		chatworkNotifier.To = chatworkTo
+	}
+	return chatworkNotifier, nil
+}
+
+func init() {
+	// task サブコマンドでも使用するため、永続フラグとして定義する
+	chatworkCmd.PersistentFlags().StringVarP(&chatworkRoomID, "room-id", "r", os.Getenv("CHATWORK_ROOM_ID"), "Chatwork の投稿先のルーム ID (ENV: CHATWORK_ROOM_ID)")
+
+	addMessageFlags(chatworkCmd)
+	chatworkCmd.Flags().IntSliceVar(&chatworkTo, "to", nil, "宛先 ([To:accountId]) に指定するアカウント ID (複数指定可)")
+
+	// task のフラグ定義
+	chatworkTaskCmd.Flags().IntSliceVar(&chatworkAssignees, "assignee", nil, "【必須】タスクの担当者のアカウント ID (複数指定可)")
+	chatworkTaskCmd.Flags().StringVar(&chatworkDue, "due", "", "タスクの期限 (yyyy-MM-dd または \"yyyy-MM-dd HH:mm\")")
+
+	chatworkCmd.AddCommand(chatworkTaskCmd)
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/shouni/go-cli-base"
+	"github.com/shouni/go-notifier/pkg/config"
+	"github.com/shouni/go-notifier/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+// appConfig は読み込まれた設定ファイルの内容です。設定ファイルがない場合は nil です。
+var appConfig *config.Config
+
+// loadAppConfig は、--config フラグ (clibase 共通) または既定のパスから設定ファイルを読み込みます。
+// 設定ファイルが見つからない場合は何もせず、従来どおり環境変数とフラグのみで動作します。
+func loadAppConfig() error {
+	path := clibase.Flags.ConfigFile
+	if path == "" {
+		path = config.DefaultPath()
+	}
+	if path == "" {
+		return nil
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	appConfig = cfg
+
+	if clibase.Flags.Verbose {
+		log.Printf("設定ファイルを読み込みました (%s)。", path)
+	}
+	return nil
+}
+
+// resolveTargets は、--profile / --target フラグから使用する通知先名の一覧を決定します。
+// 設定ファイルがなく、いずれのフラグも指定されていない場合は空の一覧を返します。
+func resolveTargets(targets []string) ([]string, error) {
+	if appConfig == nil {
+		if Flags.Profile != "" || len(targets) > 0 {
+			return nil, errors.New("--profile / --target を使用するには設定ファイル (--config または NOTIFIER_CONFIG) が必要です")
+		}
+		return nil, nil
+	}
+	return appConfig.ResolveTargets(Flags.Profile, targets)
+}
+
+// resolveSingleTarget は、指定された種別の通知先名を1つだけ決定します。
+// --target では種別を省略した名前 (例: slack サブコマンドでの "alerts") も指定できます。
+// 該当する通知先がない場合は空文字列を返し、呼び出し元は環境変数による設定にフォールバックします。
+func resolveSingleTarget(kind string) (string, error) {
+	targets := make([]string, 0, len(Flags.Targets))
+	for _, t := range Flags.Targets {
+		if !strings.Contains(t, ".") {
+			t = kind + "." + t
+		}
+		targets = append(targets, t)
+	}
+
+	names, err := resolveTargets(targets)
+	if err != nil {
+		return "", err
+	}
+
+	var matched []string
+	for _, name := range names {
+		if strings.HasPrefix(name, kind+".") {
+			matched = append(matched, name)
+		}
+	}
+
+	switch {
+	case len(matched) > 1:
+		return "", fmt.Errorf("%s の通知先が複数指定されています (%s)。複数の通知先へ送信するには send サブコマンドを使用してください", kind, strings.Join(matched, ", "))
+	case len(matched) == 0 && (Flags.Profile != "" || len(Flags.Targets) > 0):
+		return "", fmt.Errorf("指定された --profile / --target に %s の通知先が含まれていません", kind)
+	case len(matched) == 0:
+		return "", nil
+	}
+	return matched[0], nil
+}
+
+// flagOrConfig は、フラグが明示的に指定されていればフラグの値を、そうでなければ設定ファイルの値を返します。
+// 設定ファイルの値が空の場合は、フラグの値 (環境変数によるデフォルト値を含む) にフォールバックします。
+func flagOrConfig(cmd *cobra.Command, name, flagValue, configValue string) string {
+	if cmd.Flags().Changed(name) || configValue == "" {
+		return flagValue
+	}
+	return configValue
+}
+
+// flagOrConfigList は、flagOrConfig のリスト版です。
+func flagOrConfigList(cmd *cobra.Command, name string, flagValue, configValue []string) []string {
+	if cmd.Flags().Changed(name) || len(configValue) == 0 {
+		return flagValue
+	}
+	return configValue
+}
+
+// envOrConfig は、設定ファイルの値が空でなければそれを、そうでなければ環境変数の値を返します。
+func envOrConfig(envName, configValue string) string {
+	if configValue != "" {
+		return configValue
+	}
+	return os.Getenv(envName)
+}
+
+// buildNotifier は、設定ファイルの通知先名から Notifier を生成します。
+func buildNotifier(name string) (notifier.Notifier, error) {
+	kind, _, err := config.SplitTargetName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case config.KindSlack:
+		t, err := appConfig.SlackTarget(name)
+		if err != nil {
+			return nil, err
+		}
+		if t.BotToken != "" {
+			sn, err := notifier.NewSlackBotNotifier(*sharedClient, t.BotToken, t.Channel)
+			if err != nil {
+				return nil, fmt.Errorf("通知先 %s: %w", name, err)
+			}
+			sn.Username = t.Username
+			sn.IconEmoji = t.IconEmoji
+			return sn, nil
+		}
+		if t.WebhookURL == "" {
+			return nil, fmt.Errorf("通知先 %s の webhook_url または bot_token が設定されていません", name)
+		}
+		return notifier.NewSlackNotifier(*sharedClient, t.WebhookURL, t.Username, t.IconEmoji, t.Channel), nil
+	case config.KindBacklog:
+		t, err := appConfig.BacklogTarget(name)
+		if err != nil {
+			return nil, err
+		}
+		bn, err := notifier.NewBacklogNotifier(*sharedClient, t.SpaceURL, t.APIKey)
+		if err != nil {
+			return nil, fmt.Errorf("通知先 %s: %w", name, err)
+		}
+		bn.ProjectKey = t.Project
+		bn.IssueType = t.IssueType
+		bn.Priority = t.Priority
+		if bn.EmojiPolicy, err = notifier.ParseEmojiPolicy(t.Emoji); err != nil {
+			return nil, fmt.Errorf("通知先 %s: %w", name, err)
+		}
+		bn.EmojiASCII = t.EmojiASCII
+		configureBacklogCache(bn)
+		return bn, nil
+	case config.KindTeams:
+		t, err := appConfig.TeamsTarget(name)
+		if err != nil {
+			return nil, err
+		}
+		tn, err := notifier.NewTeamsNotifier(*sharedClient, t.WebhookURL)
+		if err != nil {
+			return nil, fmt.Errorf("通知先 %s: %w", name, err)
+		}
+		return tn, nil
+	case config.KindDiscord:
+		t, err := appConfig.DiscordTarget(name)
+		if err != nil {
+			return nil, err
+		}
+		dn, err := notifier.NewDiscordNotifier(*sharedClient, t.WebhookURL)
+		if err != nil {
+			return nil, fmt.Errorf("通知先 %s: %w", name, err)
+		}
+		dn.Username = t.Username
+		dn.AvatarURL = t.AvatarURL
+		return dn, nil
+	case config.KindGChat:
+		t, err := appConfig.GChatTarget(name)
+		if err != nil {
+			return nil, err
+		}
+		gn, err := notifier.NewGoogleChatNotifier(*sharedClient, t.WebhookURL)
+		if err != nil {
+			return nil, fmt.Errorf("通知先 %s: %w", name, err)
+		}
+		gn.ThreadKey = t.ThreadKey
+		return gn, nil
+	case config.KindChatwork:
+		t, err := appConfig.ChatworkTarget(name)
+		if err != nil {
+			return nil, err
+		}
+		cn, err := notifier.NewChatworkNotifier(*sharedClient, t.APIToken, t.RoomID)
+		if err != nil {
+			return nil, fmt.Errorf("通知先 %s: %w", name, err)
+		}
+		cn.To = t.To
+		return cn, nil
+	case config.KindEmail:
+		t, err := appConfig.EmailTarget(name)
+		if err != nil {
+			return nil, err
+		}
+		en, err := newEmailNotifier(t.Host, smtpPortString(t.Port), t.TLS, t.Auth, t.Username, t.Password, t.From, t.To)
+		if err != nil {
+			return nil, fmt.Errorf("通知先 %s: %w", name, err)
+		}
+		en.Cc = t.Cc
+		en.Bcc = t.Bcc
+		en.ReplyTo = t.ReplyTo
+		en.Headers = t.Headers
+		return en, nil
+	}
+
+	return nil, fmt.Errorf("不明な通知先の種別です: %s", kind)
+}
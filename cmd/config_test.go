@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// newPrecedenceCommand は、サブコマンドと同じく環境変数をデフォルト値とするフラグを持つコマンドを作成し、args を解析します。
+func newPrecedenceCommand(t *testing.T, args ...string) (cmd *cobra.Command, webhookURL *string, to *[]string) {
+	t.Helper()
+	cmd = &cobra.Command{Use: "test"}
+	webhookURL = cmd.Flags().String("webhook-url", os.Getenv("TEST_WEBHOOK_URL"), "")
+	to = cmd.Flags().StringSlice("to", envList("TEST_EMAIL_TO"), "")
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd, webhookURL, to
+}
+
+// TestFlagOrConfig は、フラグ > 設定ファイル > 環境変数の優先順位を確認します。
+func TestFlagOrConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    string
+		args   []string
+		config string
+		want   string
+	}{
+		{"フラグが最優先", "https://env", []string{"--webhook-url", "https://flag"}, "https://config", "https://flag"},
+		{"フラグがなければ設定ファイル", "https://env", nil, "https://config", "https://config"},
+		{"設定ファイルが空なら環境変数", "https://env", nil, "", "https://env"},
+		{"いずれもなければ空", "", nil, "", ""},
+		{"明示的な空のフラグも設定ファイルより優先", "", []string{"--webhook-url="}, "https://config", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_WEBHOOK_URL", tt.env)
+			cmd, webhookURL, _ := newPrecedenceCommand(t, tt.args...)
+			if got := flagOrConfig(cmd, "webhook-url", *webhookURL, tt.config); got != tt.want {
+				t.Errorf("flagOrConfig() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagOrConfigList(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    string
+		args   []string
+		config []string
+		want   []string
+	}{
+		{"フラグが最優先", "env@example.com", []string{"--to", "a@example.com", "--to", "b@example.com"}, []string{"config@example.com"}, []string{"a@example.com", "b@example.com"}},
+		{"フラグがなければ設定ファイル", "env@example.com", nil, []string{"config@example.com"}, []string{"config@example.com"}},
+		{"設定ファイルが空なら環境変数", "env1@example.com, env2@example.com", nil, nil, []string{"env1@example.com", "env2@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_EMAIL_TO", tt.env)
+			cmd, _, to := newPrecedenceCommand(t, tt.args...)
+			if got := flagOrConfigList(cmd, "to", *to, tt.config); !slices.Equal(got, tt.want) {
+				t.Errorf("flagOrConfigList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEnvOrConfig は、フラグを持たない認証情報で、設定ファイルが環境変数より優先されることを確認します。
+func TestEnvOrConfig(t *testing.T) {
+	t.Setenv("TEST_API_KEY", "env-key")
+	if got := envOrConfig("TEST_API_KEY", "config-key"); got != "config-key" {
+		t.Errorf("envOrConfig() = %q, want the config value", got)
+	}
+	if got := envOrConfig("TEST_API_KEY", ""); got != "env-key" {
+		t.Errorf("envOrConfig() = %q, want the environment value", got)
+	}
+}
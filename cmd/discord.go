@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/shouni/go-notifier/pkg/config"
+	"github.com/shouni/go-notifier/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+// Discord 固有の設定フラグ変数
+var (
+	discordWebhookURL string
+	discordUsername   string
+	discordAvatarURL  string
+)
+
+var discordCmd = &cobra.Command{
+	Use:   "discord",
+	Short: "Discordに埋め込み (embed) 形式で投稿します",
+	Long: `環境変数 DISCORD_WEBHOOK_URL、もしくは設定ファイルの通知先 (--target / --profile) が必要です。
+タイトルは埋め込みのタイトル、メッセージは説明、--severity は色、--field はフィールドに変換され、Discord の文字数制限が適用されます。
+投稿したメッセージの ID を標準出力に出力します。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if Flags.Message == "" {
+			log.Fatal("🚨 致命的なエラー: 投稿メッセージがありません。-m フラグでメッセージを指定してください。")
+		}
+
+		msg, err := buildMessage()
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		discordNotifier, err := getDiscordNotifier(cmd)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		result, err := discordNotifier.Send(context.Background(), msg)
+		if err != nil {
+			log.Fatalf("🚨 Discordへの投稿に失敗しました: %v", err)
+		}
+
+		if len(result.Truncations) > 0 {
+			log.Printf("⚠️ 上限を超えたため一部を切り詰めました (%v)", result.Truncations)
+		}
+		log.Println("✅ Discordへの投稿が完了しました。")
+		if result.ID != "" {
+			fmt.Println(result.ID)
+		}
+	},
+}
+
+// getDiscordNotifier は、設定ファイルの通知先・フラグ・環境変数から Discord Notifierを生成します。
+// 優先順位は、明示的なフラグ > 設定ファイル (--target / --profile) > 環境変数 です。
+func getDiscordNotifier(cmd *cobra.Command) (*notifier.DiscordNotifier, error) {
+	var target config.DiscordTarget
+	name, err := resolveSingleTarget(config.KindDiscord)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		if target, err = appConfig.DiscordTarget(name); err != nil {
+			return nil, err
+		}
+	}
+
+	webhookURL := flagOrConfig(cmd, "webhook-url", discordWebhookURL, target.WebhookURL)
+	if webhookURL == "" {
+		return nil, fmt.Errorf("DISCORD_WEBHOOK_URL 環境変数、--webhook-url フラグ、または設定ファイルの webhook_url が設定されていません")
+	}
+
+	// sharedClient は PersistentPreRunE で初期化済みのためそのまま利用
+	discordNotifier, err := notifier.NewDiscordNotifier(*sharedClient, webhookURL)
+	if err != nil {
+		return nil, err
+	}
+	discordNotifier.Username = flagOrConfig(cmd, "username", discordUsername, target.Username)
+	discordNotifier.AvatarURL = flagOrConfig(cmd, "avatar-url", discordAvatarURL, target.AvatarURL)
+	return discordNotifier, nil
+}
+
+func init() {
+	addMessageFlags(discordCmd)
+	discordCmd.Flags().StringVar(&discordWebhookURL, "webhook-url", os.Getenv("DISCORD_WEBHOOK_URL"), "Discord の Webhook URL (ENV: DISCORD_WEBHOOK_URL)")
+	discordCmd.Flags().StringVarP(&discordUsername, "username", "u", os.Getenv("DISCORD_USERNAME"), "Discord投稿時のユーザー名 (ENV: DISCORD_USERNAME)")
+	discordCmd.Flags().StringVar(&discordAvatarURL, "avatar-url", os.Getenv("DISCORD_AVATAR_URL"), "Discord投稿時のアバター画像の URL (ENV: DISCORD_AVATAR_URL)")
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shouni/go-notifier/pkg/config"
+	"github.com/shouni/go-notifier/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+// メール固有の設定フラグ変数
+var (
+	emailHost        string
+	emailPort        string
+	emailTLS         string
+	emailAuth        string
+	emailFrom        string
+	emailTo          []string
+	emailCc          []string
+	emailBcc         []string
+	emailReplyTo     []string
+	emailHeaders     []string
+	emailAttachments []string
+)
+
+var emailCmd = &cobra.Command{
+	Use:   "email",
+	Short: "SMTPサーバー経由でメールを送信します",
+	Long: `環境変数 SMTP_HOST と EMAIL_FROM / EMAIL_TO (認証する場合は SMTP_USERNAME / SMTP_PASSWORD)、もしくは設定ファイルの通知先 (--target / --profile) が必要です。
+タイトルは件名 (日本語は RFC 2047 でエンコード)、メッセージは Markdown として HTML とテキストの両方に変換され、multipart/alternative として送信されます。
+--attach で指定したファイルは添付ファイルとして送信されます。送信したメールの Message-ID を標準出力に出力します。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if Flags.Message == "" {
+			log.Fatal("🚨 致命的なエラー: 投稿メッセージがありません。-m フラグでメッセージを指定してください。")
+		}
+
+		msg, err := buildMessage()
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		emailNotifier, err := getEmailNotifier(cmd)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		attachments, closeAttachments, err := openMessageAttachments(emailAttachments)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+		defer closeAttachments()
+		msg.Attachments = attachments
+
+		result, err := emailNotifier.Send(context.Background(), msg)
+		if err != nil {
+			log.Fatalf("🚨 メールの送信に失敗しました: %v", err)
+		}
+
+		log.Println("✅ メールの送信が完了しました。")
+		fmt.Println(result.ID)
+	},
+}
+
+// getEmailNotifier は、設定ファイルの通知先・フラグ・環境変数からメール Notifierを生成します。
+// 優先順位は、明示的なフラグ > 設定ファイル (--target / --profile) > 環境変数 です。
+func getEmailNotifier(cmd *cobra.Command) (*notifier.EmailNotifier, error) {
+	var target config.EmailTarget
+	name, err := resolveSingleTarget(config.KindEmail)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		if target, err = appConfig.EmailTarget(name); err != nil {
+			return nil, err
+		}
+	}
+
+	host := flagOrConfig(cmd, "smtp-host", emailHost, target.Host)
+	if host == "" {
+		return nil, fmt.Errorf("SMTP_HOST 環境変数、--smtp-host フラグ、または設定ファイルの host が設定されていません")
+	}
+
+	emailNotifier, err := newEmailNotifier(
+		host,
+		flagOrConfig(cmd, "smtp-port", emailPort, smtpPortString(target.Port)),
+		flagOrConfig(cmd, "tls", emailTLS, target.TLS),
+		flagOrConfig(cmd, "auth", emailAuth, target.Auth),
+		envOrConfig("SMTP_USERNAME", target.Username),
+		envOrConfig("SMTP_PASSWORD", target.Password),
+		flagOrConfig(cmd, "from", emailFrom, target.From),
+		flagOrConfigList(cmd, "to", emailTo, target.To),
+	)
+	if err != nil {
+		return nil, err
+	}
+	emailNotifier.Cc = flagOrConfigList(cmd, "cc", emailCc, target.Cc)
+	emailNotifier.Bcc = flagOrConfigList(cmd, "bcc", emailBcc, target.Bcc)
+	emailNotifier.ReplyTo = flagOrConfigList(cmd, "reply-to", emailReplyTo, target.ReplyTo)
+
+	// 設定ファイルのヘッダーに、--header で指定したヘッダーを追加 (同じ名前の場合は上書き) する
+	emailNotifier.Headers = make(map[string]string, len(target.Headers)+len(emailHeaders))
+	for k, v := range target.Headers {
+		emailNotifier.Headers[k] = v
+	}
+	for _, h := range emailHeaders {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("--header は「名前: 値」の形式で指定してください: %q", h)
+		}
+		emailNotifier.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return emailNotifier, nil
+}
+
+// newEmailNotifier は、SMTP サーバーの設定からメール Notifierを生成します。
+// port が空の場合は、暗号化方法に応じて 465 (tls) または 587 (starttls, none) を使用します。
+func newEmailNotifier(host, port, tlsMode, authMethod, username, password, from string, to []string) (*notifier.EmailNotifier, error) {
+	mode, err := notifier.ParseEmailTLSMode(tlsMode)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := notifier.ParseEmailAuthMethod(authMethod)
+	if err != nil {
+		return nil, err
+	}
+	if from == "" || len(to) == 0 {
+		return nil, fmt.Errorf("EMAIL_FROM / EMAIL_TO 環境変数、--from / --to フラグ、または設定ファイルの from / to が設定されていません")
+	}
+
+	if port == "" {
+		port = "587"
+		if mode == notifier.EmailImplicitTLS {
+			port = "465"
+		}
+	}
+
+	emailNotifier, err := notifier.NewEmailNotifier(net.JoinHostPort(host, port), username, password, from, to)
+	if err != nil {
+		return nil, err
+	}
+	emailNotifier.TLSMode = mode
+	emailNotifier.AuthMethod = auth
+	// HTTP クライアントと同じタイムアウトを、接続から送信完了までに適用する
+	emailNotifier.Timeout = time.Duration(Flags.TimeoutSec) * time.Second
+	return emailNotifier, nil
+}
+
+// smtpPortString は、設定ファイルのポート番号を文字列に変換します。0 (未設定) の場合は空文字列を返します。
+func smtpPortString(port int) string {
+	if port == 0 {
+		return ""
+	}
+	return strconv.Itoa(port)
+}
+
+// openMessageAttachments は、--attach フラグで指定されたファイルを開き、メッセージの添付ファイルに変換します。
+// 戻り値の関数で、開いたファイルを閉じます。
+func openMessageAttachments(paths []string) ([]notifier.Attachment, func(), error) {
+	var files []*os.File
+	closeAll := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+
+	attachments := make([]notifier.Attachment, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("添付ファイルを開けません: %w", err)
+		}
+		files = append(files, f)
+		attachments = append(attachments, notifier.Attachment{Filename: filepath.Base(path), Content: f})
+	}
+	return attachments, closeAll, nil
+}
+
+// envList は、カンマ区切りの環境変数の値をリストに変換します。
+func envList(name string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func init() {
+	addMessageFlags(emailCmd)
+	emailCmd.Flags().StringVar(&emailHost, "smtp-host", os.Getenv("SMTP_HOST"), "SMTP サーバーのホスト名 (ENV: SMTP_HOST)")
+	emailCmd.Flags().StringVar(&emailPort, "smtp-port", os.Getenv("SMTP_PORT"), "SMTP サーバーのポート (ENV: SMTP_PORT、省略時は --tls に応じて 587 または 465)")
+	emailCmd.Flags().StringVar(&emailTLS, "tls", os.Getenv("SMTP_TLS"), "接続の暗号化方法 (starttls, tls, none) (ENV: SMTP_TLS、省略時は starttls)")
+	emailCmd.Flags().StringVar(&emailAuth, "auth", os.Getenv("SMTP_AUTH"), "認証方式 (plain, login) (ENV: SMTP_AUTH、省略時は plain)")
+	emailCmd.Flags().StringVar(&emailFrom, "from", os.Getenv("EMAIL_FROM"), "送信者のアドレス (例: \"通知 <notifier@example.com>\") (ENV: EMAIL_FROM)")
+	emailCmd.Flags().StringSliceVar(&emailTo, "to", envList("EMAIL_TO"), "宛先のアドレス (複数指定可) (ENV: EMAIL_TO、カンマ区切り)")
+	emailCmd.Flags().StringSliceVar(&emailCc, "cc", nil, "Cc のアドレス (複数指定可)")
+	emailCmd.Flags().StringSliceVar(&emailBcc, "bcc", nil, "Bcc のアドレス (複数指定可)")
+	emailCmd.Flags().StringSliceVar(&emailReplyTo, "reply-to", nil, "返信先のアドレス (複数指定可)")
+	emailCmd.Flags().StringArrayVar(&emailHeaders, "header", nil, "追加するヘッダー (例: \"X-Mailer: go-notifier\", 複数指定可)")
+	emailCmd.Flags().StringArrayVar(&emailAttachments, "attach", nil, "添付するファイルのパス (複数指定可)")
+}
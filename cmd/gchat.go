@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/shouni/go-notifier/pkg/config"
+	"github.com/shouni/go-notifier/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+// Google Chat 固有の設定フラグ変数
+var (
+	gchatWebhookURL string
+	gchatThreadKey  string
+)
+
+var gchatCmd = &cobra.Command{
+	Use:   "gchat",
+	Short: "Google Chatのスペースにカード (Cards v2) 形式で投稿します",
+	Long: `環境変数 GOOGLE_CHAT_WEBHOOK_URL、もしくは設定ファイルの通知先 (--target / --profile) が必要です。
+タイトルはカードのヘッダー、メッセージは本文、--field はラベル付きのテキスト、--link はボタンに変換されます。
+--thread-key を指定すると、同じキーのメッセージは1つのスレッドにまとめられます。投稿したメッセージのリソース名を標準出力に出力します。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if Flags.Message == "" {
+			log.Fatal("🚨 致命的なエラー: 投稿メッセージがありません。-m フラグでメッセージを指定してください。")
+		}
+
+		msg, err := buildMessage()
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		gchatNotifier, err := getGChatNotifier(cmd)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		result, err := gchatNotifier.Send(context.Background(), msg)
+		if err != nil {
+			log.Fatalf("🚨 Google Chatへの投稿に失敗しました: %v", err)
+		}
+
+		if len(result.Truncations) > 0 {
+			log.Printf("⚠️ 上限を超えたため一部を切り詰めました (%v)", result.Truncations)
+		}
+		log.Println("✅ Google Chatへの投稿が完了しました。")
+		if result.ID != "" {
+			log.Printf("スレッド: %s", result.Channel)
+			fmt.Println(result.ID)
+		}
+	},
+}
+
+// getGChatNotifier は、設定ファイルの通知先・フラグ・環境変数から Google Chat Notifierを生成します。
+// 優先順位は、明示的なフラグ > 設定ファイル (--target / --profile) > 環境変数 です。
+func getGChatNotifier(cmd *cobra.Command) (*notifier.GoogleChatNotifier, error) {
+	var target config.GChatTarget
+	name, err := resolveSingleTarget(config.KindGChat)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		if target, err = appConfig.GChatTarget(name); err != nil {
+			return nil, err
+		}
+	}
+
+	webhookURL := flagOrConfig(cmd, "webhook-url", gchatWebhookURL, target.WebhookURL)
+	if webhookURL == "" {
+		return nil, fmt.Errorf("GOOGLE_CHAT_WEBHOOK_URL 環境変数、--webhook-url フラグ、または設定ファイルの webhook_url が設定されていません")
+	}
+
+	// sharedClient は PersistentPreRunE で初期化済みのためそのまま利用
+	gchatNotifier, err := notifier.NewGoogleChatNotifier(*sharedClient, webhookURL)
+	if err != nil {
+		return nil, err
+	}
+	gchatNotifier.ThreadKey = flagOrConfig(cmd, "thread-key", gchatThreadKey, target.ThreadKey)
+	return gchatNotifier, nil
+}
+
+func init() {
+	addMessageFlags(gchatCmd)
+	gchatCmd.Flags().StringVar(&gchatWebhookURL, "webhook-url", os.Getenv("GOOGLE_CHAT_WEBHOOK_URL"), "Google Chat のスペースの Webhook URL (ENV: GOOGLE_CHAT_WEBHOOK_URL)")
+	gchatCmd.Flags().StringVar(&gchatThreadKey, "thread-key", "", "同じキーのメッセージを1つのスレッドにまとめるためのキー")
+}
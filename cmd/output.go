@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// 出力形式 (--output フラグ)
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// outputFormat は --output フラグの値です
+var outputFormat string
+
+// validateOutputFormat は --output フラグの値を検証します。
+func validateOutputFormat() error {
+	switch outputFormat {
+	case outputTable, outputJSON:
+		return nil
+	default:
+		return fmt.Errorf("不明な出力形式です: %q (table, json のいずれかを指定してください)", outputFormat)
+	}
+}
+
+// printResult は、--output フラグに従って、v を JSON として、または headers と rows を表として標準出力に出力します。
+func printResult(v any, headers []string, rows [][]string) error {
+	if outputFormat == outputJSON {
+		return printJSON(v)
+	}
+	printTable(headers, rows)
+	return nil
+}
+
+// printJSON は、v をインデント付きの JSON として標準出力に出力します。
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("JSON の出力に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// printTable は、headers と rows を列を揃えた表として標準出力に出力します。
+// 日本語などの全角文字を含む列も揃うように、列幅は表示幅で計算します。
+func printTable(headers []string, rows [][]string) {
+	widths := make([]int, len(headers))
+	for _, row := range append([][]string{headers}, rows...) {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], uniseg.StringWidth(cell))
+			}
+		}
+	}
+
+	for _, row := range append([][]string{headers}, rows...) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell
+			if i < len(row)-1 && i < len(widths) {
+				cells[i] += strings.Repeat(" ", widths[i]-uniseg.StringWidth(cell))
+			}
+		}
+		fmt.Println(strings.Join(cells, "  "))
+	}
+}
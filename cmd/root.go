@@ -21,6 +21,11 @@ type AppFlags struct {
 	Title      string // -H 投稿タイトル
 	Message    string // -m 投稿メッセージ
 	TimeoutSec int    // --timeout タイムアウト
+
+	Profile string   // --profile 設定ファイルのプロファイル名
+	Targets []string // --target 設定ファイルの通知先名 (複数指定可)
+
+	NoCache bool // --no-cache Backlog のメタデータをキャッシュしない
 }
 
 var Flags AppFlags // アプリケーション固有フラグにアクセスするためのグローバル変数
@@ -35,6 +40,9 @@ func addAppPersistentFlags(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().StringVarP(&Flags.Title, "title", "t", "", "投稿タイトル")
 	rootCmd.PersistentFlags().StringVarP(&Flags.Message, "message", "m", "", "投稿メッセージ")
 	rootCmd.PersistentFlags().IntVar(&Flags.TimeoutSec, "timeout", defaultTimeoutSec, "HTTPリクエストのタイムアウト時間（秒）")
+	rootCmd.PersistentFlags().StringVar(&Flags.Profile, "profile", "", "設定ファイルのプロファイル名")
+	rootCmd.PersistentFlags().StringSliceVar(&Flags.Targets, "target", nil, "設定ファイルの通知先名 (例: slack.alerts, 複数指定可)")
+	rootCmd.PersistentFlags().BoolVar(&Flags.NoCache, "no-cache", false, "Backlog のプロジェクト・課題種別・優先度などのメタデータをキャッシュせず、毎回取得する")
 }
 
 // initAppPreRunE は、clibase共通処理の後に実行される、アプリケーション固有のPersistentPreRunEです。
@@ -56,6 +64,11 @@ func initAppPreRunE(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("timeout must be greater than 0")
 	}
 
+	// 設定ファイル (--config または既定のパス) の読み込み
+	if err := loadAppConfig(); err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+
 	return nil
 }
 
@@ -71,5 +84,12 @@ func Execute() {
 		initAppPreRunE,
 		slackCmd,   // 既存のサブコマンド
 		backlogCmd, // 既存のサブコマンド
+		teamsCmd,
+		discordCmd,
+		gchatCmd,
+		chatworkCmd,
+		emailCmd,
+		sendCmd,
+		routeCmd,
 	)
 }
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/shouni/go-notifier/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+// routeCmd は、設定ファイルのルーティングルールを扱うサブコマンドです
+var routeCmd = &cobra.Command{
+	Use:   "route",
+	Short: "設定ファイルのルーティングルールを扱います",
+}
+
+// routeTestCmd は、サンプルメッセージがどの通知先に送信されるかを表示するサブコマンドです
+var routeTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "サンプルメッセージがどのルート・通知先にマッチするかを表示します (送信は行いません)",
+	Run: func(cmd *cobra.Command, args []string) {
+		if appConfig == nil {
+			log.Fatal("🚨 致命的なエラー: 設定ファイルが見つかりません。--config フラグまたは NOTIFIER_CONFIG 環境変数で指定してください。")
+		}
+
+		msg, err := buildMessage()
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		router, err := newRouter()
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		decision := router.Route(msg)
+		if decision.Default {
+			fmt.Println("マッチしたルート: (なし — default_targets を使用)")
+		} else {
+			fmt.Printf("マッチしたルート: %s\n", strings.Join(decision.Routes, ", "))
+		}
+		if len(decision.Targets) == 0 {
+			fmt.Println("通知先: (なし)")
+			return
+		}
+		fmt.Println("通知先:")
+		for _, target := range decision.Targets {
+			fmt.Printf("  - %s\n", target)
+		}
+	},
+}
+
+// useRouting は、send サブコマンドでルーティングルールを使用するかを判定します。
+// --profile / --target が明示された場合は、ルーティングルールより優先されます。
+func useRouting() bool {
+	if appConfig == nil || Flags.Profile != "" || len(Flags.Targets) > 0 {
+		return false
+	}
+	return len(appConfig.Routing.Routes) > 0 || len(appConfig.Routing.DefaultTargets) > 0
+}
+
+// newRouter は、設定ファイルのルーティングルールから Router を生成します。
+// 通知先は、マッチした時点で buildNotifier により生成されます。
+func newRouter() (*notifier.Router, error) {
+	routes := make([]notifier.Route, 0, len(appConfig.Routing.Routes))
+	for i, r := range appConfig.Routing.Routes {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+
+		matcher := notifier.RouteMatcher{
+			Tags:   r.Match.Tags,
+			Labels: r.Match.Labels,
+		}
+		for _, s := range r.Match.Severity {
+			severity, err := notifier.ParseSeverity(s)
+			if err != nil {
+				return nil, fmt.Errorf("ルート %s: %w", name, err)
+			}
+			matcher.Severities = append(matcher.Severities, severity)
+		}
+		if r.Match.MinSeverity != "" {
+			severity, err := notifier.ParseSeverity(r.Match.MinSeverity)
+			if err != nil {
+				return nil, fmt.Errorf("ルート %s: %w", name, err)
+			}
+			matcher.MinSeverity = severity
+		}
+		if r.Match.Title != "" {
+			re, err := regexp.Compile(r.Match.Title)
+			if err != nil {
+				return nil, fmt.Errorf("ルート %s: title の正規表現が不正です: %w", name, err)
+			}
+			matcher.Title = re
+		}
+
+		routes = append(routes, notifier.Route{
+			Name:     name,
+			Match:    matcher,
+			Targets:  r.Targets,
+			Continue: r.Continue,
+		})
+	}
+
+	router := notifier.NewRouter(buildNotifier, routes...)
+	router.DefaultTargets = appConfig.Routing.DefaultTargets
+	return router, nil
+}
+
+func init() {
+	addMessageFlags(routeTestCmd)
+	routeCmd.AddCommand(routeTestCmd)
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/shouni/go-cli-base"
+	"github.com/shouni/go-notifier/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+// send 固有の設定フラグ変数
+var (
+	sendPolicy   string
+	sendRequired int
+)
+
+// メッセージの付加情報のフラグ変数 (send / route test で共有)
+var (
+	msgSeverity string
+	msgTags     []string
+	msgLabels   map[string]string
+	msgFields   []string
+	msgLinks    []string
+)
+
+// sendCmd は、設定ファイルの複数の通知先へ同じメッセージを送信するサブコマンドです
+var sendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "設定ファイルの複数の通知先へ同じメッセージを送信します",
+	Long: `設定ファイル (--config または NOTIFIER_CONFIG) が必要です。--profile / --target で指定された通知先へ並行して送信します。
+どちらも指定されていない場合は、設定ファイルのルーティングルール (routing) で通知先を決定します。ルーティングルールがない場合は default_profile を使用します。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if Flags.Title == "" {
+			log.Fatal("🚨 致命的なエラー: タイトルがありません。-t フラグでタイトルを指定してください。")
+		}
+
+		if appConfig == nil {
+			log.Fatal("🚨 致命的なエラー: 設定ファイルが見つかりません。--config フラグまたは NOTIFIER_CONFIG 環境変数で指定してください。")
+		}
+
+		msg, err := buildMessage()
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		policy, err := parseMultiPolicy(sendPolicy)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		result, err := dispatch(msg, policy)
+		if result != nil {
+			for _, tr := range result.Results {
+				if tr.Err != nil {
+					log.Printf("❌ %s: %v", tr.Name, tr.Err)
+				} else {
+					log.Printf("✅ %s: 送信しました。", tr.Name)
+					if tr.Result != nil && len(tr.Result.Truncations) > 0 {
+						log.Printf("⚠️ %s: 上限を超えたため一部を切り詰めました (%v)", tr.Name, tr.Result.Truncations)
+					}
+				}
+			}
+		}
+		if err != nil {
+			log.Fatalf("🚨 送信に失敗しました: %v", err)
+		}
+
+		log.Printf("✅ 送信が完了しました (%d/%d 件成功)。", result.Successes(), len(result.Results))
+	},
+}
+
+// dispatch は、--profile / --target またはルーティングルールで決定した通知先へメッセージを送信します。
+func dispatch(msg notifier.Message, policy notifier.MultiPolicy) (*notifier.MultiResult, error) {
+	if useRouting() {
+		router, err := newRouter()
+		if err != nil {
+			return nil, err
+		}
+		router.Policy = policy
+		router.RequiredSuccesses = sendRequired
+
+		if clibase.Flags.Verbose {
+			decision := router.Route(msg)
+			log.Printf("ルーティング結果: ルート=%v, 通知先=%v", decision.Routes, decision.Targets)
+		}
+		return router.SendAll(context.Background(), msg)
+	}
+
+	names, err := resolveTargets(Flags.Targets)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errors.New("送信先がありません。--profile または --target で通知先を指定してください")
+	}
+
+	targets := make([]notifier.NamedNotifier, 0, len(names))
+	for _, name := range names {
+		n, err := buildNotifier(name)
+		if err != nil {
+			return nil, fmt.Errorf("通知先 %s の初期化に失敗しました: %w", name, err)
+		}
+		targets = append(targets, notifier.NamedNotifier{Name: name, Notifier: n})
+	}
+
+	multi, err := notifier.NewMultiNotifier(policy, targets...)
+	if err != nil {
+		return nil, err
+	}
+	multi.RequiredSuccesses = sendRequired
+	return multi.SendAll(context.Background(), msg)
+}
+
+// buildMessage は、グローバルフラグとメッセージ用フラグから Message を構築します。
+func buildMessage() (notifier.Message, error) {
+	severity, err := notifier.ParseSeverity(msgSeverity)
+	if err != nil {
+		return notifier.Message{}, err
+	}
+
+	msg := notifier.Message{
+		Title:    Flags.Title,
+		Body:     Flags.Message,
+		Severity: severity,
+		Tags:     msgTags,
+		Labels:   msgLabels,
+	}
+	for _, f := range msgFields {
+		name, value, ok := strings.Cut(f, "=")
+		if !ok || name == "" {
+			return notifier.Message{}, fmt.Errorf("--field は「名前=値」の形式で指定してください: %q", f)
+		}
+		msg.Fields = append(msg.Fields, notifier.Field{Name: name, Value: value})
+	}
+	for _, l := range msgLinks {
+		// "テキスト=URL" の形式 (テキストを省略した場合は URL をそのまま表示)
+		link := notifier.Link{URL: l}
+		if text, u, ok := strings.Cut(l, "="); ok && !strings.Contains(text, "://") {
+			link = notifier.Link{Text: text, URL: u}
+		}
+		if link.URL == "" {
+			return notifier.Message{}, fmt.Errorf("--link は「テキスト=URL」または URL の形式で指定してください: %q", l)
+		}
+		msg.Links = append(msg.Links, link)
+	}
+	return msg, nil
+}
+
+// addMessageFlags は、メッセージの付加情報 (重要度・タグ・ラベル・フィールド・リンク) のフラグを追加します。
+func addMessageFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&msgSeverity, "severity", "s", "info", "重要度 (info, success, warning, error, critical)")
+	cmd.Flags().StringSliceVar(&msgTags, "tag", nil, "メッセージに付与するタグ (複数指定可)")
+	cmd.Flags().StringToStringVar(&msgLabels, "label", nil, "メッセージに付与するラベル (例: service=api, 複数指定可)")
+	cmd.Flags().StringArrayVar(&msgFields, "field", nil, "メッセージに付与するフィールド (例: 環境=production, 複数指定可)")
+	cmd.Flags().StringArrayVar(&msgLinks, "link", nil, "メッセージに付与するリンク (例: リリースノート=https://..., 複数指定可)")
+}
+
+// parseMultiPolicy は --policy フラグの値を MultiPolicy に変換します。
+func parseMultiPolicy(s string) (notifier.MultiPolicy, error) {
+	switch s {
+	case "best-effort":
+		return notifier.PolicyBestEffort, nil
+	case "fail-fast":
+		return notifier.PolicyFailFast, nil
+	case "require-n":
+		return notifier.PolicyRequireN, nil
+	default:
+		return 0, fmt.Errorf("不明な送信ポリシーです: %q (best-effort, fail-fast, require-n のいずれかを指定してください)", s)
+	}
+}
+
+func init() {
+	addMessageFlags(sendCmd)
+	sendCmd.Flags().StringVar(&sendPolicy, "policy", "best-effort", "送信ポリシー (best-effort, fail-fast, require-n)")
+	sendCmd.Flags().IntVar(&sendRequired, "required", 0, "require-n ポリシーで要求する成功数 (0 の場合はすべての通知先。通知先の数を超える場合はエラー)")
+}
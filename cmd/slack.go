@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 
+	"github.com/shouni/go-notifier/pkg/config"
 	"github.com/shouni/go-notifier/pkg/notifier"
 	"github.com/spf13/cobra"
 )
@@ -14,46 +16,155 @@ var (
 	slackUsername  string
 	slackIconEmoji string
 	slackChannel   string
+	slackThreadTS  string
+	slackTS        string
 )
 
 var slackCmd = &cobra.Command{
 	Use:   "slack",
 	Short: "Slackにプレーンテキストを投稿します",
-	Long:  `環境変数 SLACK_WEBHOOK_URL が設定されている必要があります。投稿テキストは Block Kit 形式に変換され、文字数制限が適用されます。`,
+	Long: `環境変数 SLACK_WEBHOOK_URL (Incoming Webhook) または SLACK_BOT_TOKEN (Web API)、もしくは設定ファイルの通知先 (--target / --profile) が必要です。
+投稿テキストは Block Kit 形式に変換され、文字数制限が適用されます。
+ボットトークンを使用した場合は投稿したメッセージの ts を標準出力に出力し、--thread-ts でそのスレッドに返信できます。`,
 	Run: func(cmd *cobra.Command, args []string) {
-
-		// 🚨 修正点1: ルートコマンドの共通フラグ（Header, Message）をアクセス
 		if Flags.Message == "" {
 			log.Fatal("🚨 致命的なエラー: 投稿メッセージがありません。-m フラグでメッセージを指定してください。")
 		}
 
-		slackWebhookURL := os.Getenv("SLACK_WEBHOOK_URL")
-		if slackWebhookURL == "" {
-			log.Fatal("🚨 致命的なエラー: SLACK_WEBHOOK_URL 環境変数が設定されていません。")
+		slackNotifier, err := getSlackNotifier(cmd)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
 		}
 
-		// 🚨 修正点2: sharedClient は PersistentPreRunE で初期化済みのためそのまま利用
-		// Notifierの初期化
-		slackNotifier := notifier.NewSlackNotifier(
-			*sharedClient,
-			slackWebhookURL,
-			slackUsername,
-			slackIconEmoji,
-			slackChannel,
-		)
-
 		// 投稿実行
-		// 🚨 修正点3: ルートコマンドの共通フラグ（Header, Message）をアクセス
-		if err := slackNotifier.SendTextWithHeader(context.Background(), Flags.Title, Flags.Message); err != nil {
+		ref, err := slackNotifier.PostMessage(context.Background(), notifier.Message{Title: Flags.Title, Body: Flags.Message})
+		if err != nil {
 			log.Fatalf("🚨 Slackへの投稿に失敗しました: %v", err)
 		}
 
 		log.Println("✅ Slackへの投稿が完了しました。")
+		if ref.TS != "" {
+			// 後続のスレッド返信 (--thread-ts) で使用できるよう、ts のみを標準出力に出力する
+			log.Printf("チャンネル: %s, ts: %s", ref.Channel, ref.TS)
+			fmt.Println(ref.TS)
+		}
 	},
 }
 
+// --- サブコマンド: update / delete (slackの子) ---
+
+// slackUpdateCmd は投稿済みメッセージを更新するサブコマンドです
+var slackUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "投稿済みのメッセージを新しいタイトル・メッセージで置き換えます (ボットトークンモードのみ)",
+	Run: func(cmd *cobra.Command, args []string) {
+		if Flags.Message == "" && Flags.Title == "" {
+			log.Fatal("🚨 致命的なエラー: 更新内容がありません。-t / -m フラグでタイトルまたはメッセージを指定してください。")
+		}
+
+		slackNotifier := getSlackBotNotifier(cmd)
+
+		ref, err := slackNotifier.UpdateMessage(
+			context.Background(),
+			notifier.SlackMessageRef{TS: slackTS},
+			notifier.Message{Title: Flags.Title, Body: Flags.Message},
+		)
+		if err != nil {
+			log.Fatalf("🚨 Slackメッセージの更新に失敗しました: %v", err)
+		}
+
+		log.Printf("✅ Slackメッセージ (ts: %s) の更新が完了しました。", ref.TS)
+	},
+}
+
+// slackDeleteCmd は投稿済みメッセージを削除するサブコマンドです
+var slackDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "投稿済みのメッセージを削除します (ボットトークンモードのみ)",
+	Run: func(cmd *cobra.Command, args []string) {
+		slackNotifier := getSlackBotNotifier(cmd)
+
+		if err := slackNotifier.DeleteMessage(context.Background(), notifier.SlackMessageRef{TS: slackTS}); err != nil {
+			log.Fatalf("🚨 Slackメッセージの削除に失敗しました: %v", err)
+		}
+
+		log.Printf("✅ Slackメッセージ (ts: %s) の削除が完了しました。", slackTS)
+	},
+}
+
+// getSlackBotNotifier は、update / delete 用にボットトークンモードの Slack Notifierを生成します。
+func getSlackBotNotifier(cmd *cobra.Command) *notifier.SlackNotifier {
+	if slackTS == "" {
+		log.Fatal("🚨 致命的なエラー: --ts フラグで対象メッセージの ts を指定してください。")
+	}
+
+	slackNotifier, err := getSlackNotifier(cmd)
+	if err != nil {
+		log.Fatalf("🚨 致命的なエラー: %v", err)
+	}
+	if !slackNotifier.IsBotMode() {
+		log.Fatal("🚨 致命的なエラー: メッセージの更新・削除には SLACK_BOT_TOKEN 環境変数または設定ファイルの bot_token が必要です。")
+	}
+	if slackNotifier.Channel == "" {
+		log.Fatal("🚨 致命的なエラー: -c フラグで対象メッセージのチャンネルIDを指定してください。")
+	}
+
+	return slackNotifier
+}
+
+// getSlackNotifier は、設定ファイルの通知先・フラグ・環境変数から Slack Notifierを生成します。
+// 優先順位は、明示的なフラグ > 設定ファイル (--target / --profile) > 環境変数 です。
+func getSlackNotifier(cmd *cobra.Command) (*notifier.SlackNotifier, error) {
+	var target config.SlackTarget
+	name, err := resolveSingleTarget(config.KindSlack)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		if target, err = appConfig.SlackTarget(name); err != nil {
+			return nil, err
+		}
+	}
+
+	username := flagOrConfig(cmd, "username", slackUsername, target.Username)
+	iconEmoji := flagOrConfig(cmd, "icon-emoji", slackIconEmoji, target.IconEmoji)
+	channel := flagOrConfig(cmd, "channel", slackChannel, target.Channel)
+
+	// ボットトークンが設定されている場合は Web API (chat.postMessage) を使用する
+	if botToken := envOrConfig("SLACK_BOT_TOKEN", target.BotToken); botToken != "" {
+		slackNotifier, err := notifier.NewSlackBotNotifier(*sharedClient, botToken, channel)
+		if err != nil {
+			return nil, err
+		}
+		slackNotifier.Username = username
+		slackNotifier.IconEmoji = iconEmoji
+		slackNotifier.ThreadTS = slackThreadTS
+		return slackNotifier, nil
+	}
+
+	if slackThreadTS != "" {
+		return nil, fmt.Errorf("--thread-ts を使用するには SLACK_BOT_TOKEN 環境変数または設定ファイルの bot_token が必要です")
+	}
+
+	slackWebhookURL := envOrConfig("SLACK_WEBHOOK_URL", target.WebhookURL)
+	if slackWebhookURL == "" {
+		return nil, fmt.Errorf("SLACK_WEBHOOK_URL / SLACK_BOT_TOKEN 環境変数、または設定ファイルの webhook_url / bot_token が設定されていません")
+	}
+
+	// sharedClient は PersistentPreRunE で初期化済みのためそのまま利用
+	return notifier.NewSlackNotifier(*sharedClient, slackWebhookURL, username, iconEmoji, channel), nil
+}
+
 func init() {
-	slackCmd.Flags().StringVarP(&slackUsername, "username", "u", os.Getenv("SLACK_USERNAME"), "Slack投稿時のユーザー名 (ENV: SLACK_USERNAME)")
-	slackCmd.Flags().StringVarP(&slackIconEmoji, "icon-emoji", "e", os.Getenv("SLACK_ICON_EMOJI"), "Slack投稿時の絵文字アイコン (ENV: SLACK_ICON_EMOJI)")
-	slackCmd.Flags().StringVarP(&slackChannel, "channel", "c", os.Getenv("SLACK_CHANNEL"), "Slack投稿先のチャンネル（例: #general）(ENV: SLACK_CHANNEL)")
+	// update / delete サブコマンドでも使用するため、永続フラグとして定義する
+	slackCmd.PersistentFlags().StringVarP(&slackUsername, "username", "u", os.Getenv("SLACK_USERNAME"), "Slack投稿時のユーザー名 (ENV: SLACK_USERNAME)")
+	slackCmd.PersistentFlags().StringVarP(&slackIconEmoji, "icon-emoji", "e", os.Getenv("SLACK_ICON_EMOJI"), "Slack投稿時の絵文字アイコン (ENV: SLACK_ICON_EMOJI)")
+	slackCmd.PersistentFlags().StringVarP(&slackChannel, "channel", "c", os.Getenv("SLACK_CHANNEL"), "Slack投稿先のチャンネル（例: #general）(ENV: SLACK_CHANNEL)")
+	slackCmd.Flags().StringVar(&slackThreadTS, "thread-ts", "", "返信先のメッセージの ts (ボットトークンモードのみ)")
+
+	// update / delete のフラグ定義
+	slackUpdateCmd.Flags().StringVar(&slackTS, "ts", "", "【必須】更新するメッセージの ts")
+	slackDeleteCmd.Flags().StringVar(&slackTS, "ts", "", "【必須】削除するメッセージの ts")
+
+	slackCmd.AddCommand(slackUpdateCmd, slackDeleteCmd)
 }
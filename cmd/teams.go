@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/shouni/go-notifier/pkg/config"
+	"github.com/shouni/go-notifier/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+// Teams 固有の設定フラグ変数
+var teamsWebhookURL string
+
+var teamsCmd = &cobra.Command{
+	Use:   "teams",
+	Short: "Microsoft Teamsに Adaptive Card 形式で投稿します",
+	Long: `環境変数 TEAMS_WEBHOOK_URL (Incoming Webhook または Workflows の Webhook URL)、もしくは設定ファイルの通知先 (--target / --profile) が必要です。
+タイトルは見出し、メッセージは本文、--field はファクト、--link はボタンとして Adaptive Card に変換されます。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if Flags.Message == "" {
+			log.Fatal("🚨 致命的なエラー: 投稿メッセージがありません。-m フラグでメッセージを指定してください。")
+		}
+
+		msg, err := buildMessage()
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		teamsNotifier, err := getTeamsNotifier(cmd)
+		if err != nil {
+			log.Fatalf("🚨 致命的なエラー: %v", err)
+		}
+
+		result, err := teamsNotifier.Send(context.Background(), msg)
+		if err != nil {
+			log.Fatalf("🚨 Teamsへの投稿に失敗しました: %v", err)
+		}
+
+		if len(result.Truncations) > 0 {
+			log.Printf("⚠️ 上限を超えたため一部を切り詰めました (%v)", result.Truncations)
+		}
+		log.Println("✅ Teamsへの投稿が完了しました。")
+	},
+}
+
+// getTeamsNotifier は、設定ファイルの通知先・フラグ・環境変数から Teams Notifierを生成します。
+// 優先順位は、明示的なフラグ > 設定ファイル (--target / --profile) > 環境変数 です。
+func getTeamsNotifier(cmd *cobra.Command) (*notifier.TeamsNotifier, error) {
+	var target config.TeamsTarget
+	name, err := resolveSingleTarget(config.KindTeams)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		if target, err = appConfig.TeamsTarget(name); err != nil {
+			return nil, err
+		}
+	}
+
+	webhookURL := flagOrConfig(cmd, "webhook-url", teamsWebhookURL, target.WebhookURL)
+	if webhookURL == "" {
+		return nil, fmt.Errorf("TEAMS_WEBHOOK_URL 環境変数、--webhook-url フラグ、または設定ファイルの webhook_url が設定されていません")
+	}
+
+	// sharedClient は PersistentPreRunE で初期化済みのためそのまま利用
+	return notifier.NewTeamsNotifier(*sharedClient, webhookURL)
+}
+
+func init() {
+	addMessageFlags(teamsCmd)
+	teamsCmd.Flags().StringVar(&teamsWebhookURL, "webhook-url", os.Getenv("TEAMS_WEBHOOK_URL"), "Teams の Incoming Webhook または Workflows の URL (ENV: TEAMS_WEBHOOK_URL)")
+}
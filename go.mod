@@ -3,18 +3,21 @@ module github.com/shouni/go-notifier
 go 1.24.0
 
 require (
+	github.com/BurntSushi/toml v1.5.0
+	github.com/forPelevin/gomoji v1.4.1
+	github.com/rivo/uniseg v0.4.7
 	github.com/shouni/go-cli-base v1.0.4
 	github.com/shouni/go-http-kit v1.0.2
 	github.com/shouni/go-utils v1.0.3
 	github.com/slack-go/slack v0.17.3
 	github.com/spf13/cobra v1.10.1
+	github.com/yuin/goldmark v1.7.8
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/cenkalti/backoff/v4 v4.3.0 // indirect
-	github.com/forPelevin/gomoji v1.4.1 // indirect
 	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/rivo/uniseg v0.4.7 // indirect
 	github.com/spf13/pflag v1.0.10 // indirect
 )
@@ -0,0 +1,522 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// 通知先の種別
+const (
+	KindSlack    = "slack"
+	KindBacklog  = "backlog"
+	KindTeams    = "teams"
+	KindDiscord  = "discord"
+	KindGChat    = "gchat"
+	KindChatwork = "chatwork"
+	KindEmail    = "email"
+)
+
+// ConfigEnv は設定ファイルのパスを指定する環境変数名です。
+const ConfigEnv = "NOTIFIER_CONFIG"
+
+// Config は設定ファイル全体を表します。
+// 通知先は種別ごとに名前付きで定義し、"slack.alerts" のように「種別.名前」で参照します。
+type Config struct {
+	// DefaultProfile: --target / --profile が指定されない場合に使用するプロファイル名
+	DefaultProfile string                    `yaml:"default_profile" toml:"default_profile"`
+	Slack          map[string]SlackTarget    `yaml:"slack" toml:"slack"`
+	Backlog        map[string]BacklogTarget  `yaml:"backlog" toml:"backlog"`
+	Teams          map[string]TeamsTarget    `yaml:"teams" toml:"teams"`
+	Discord        map[string]DiscordTarget  `yaml:"discord" toml:"discord"`
+	GChat          map[string]GChatTarget    `yaml:"gchat" toml:"gchat"`
+	Chatwork       map[string]ChatworkTarget `yaml:"chatwork" toml:"chatwork"`
+	Email          map[string]EmailTarget    `yaml:"email" toml:"email"`
+	Profiles       map[string]Profile        `yaml:"profiles" toml:"profiles"`
+	Routing        Routing                   `yaml:"routing" toml:"routing"`
+}
+
+// SlackTarget は Slack の通知先設定です。
+// webhook_url と bot_token のどちらかが必要で、bot_token が設定されている場合は Web API (chat.postMessage) を使用します。
+type SlackTarget struct {
+	WebhookURL string `yaml:"webhook_url" toml:"webhook_url"`
+	BotToken   string `yaml:"bot_token" toml:"bot_token"`
+	Username   string `yaml:"username" toml:"username"`
+	IconEmoji  string `yaml:"icon_emoji" toml:"icon_emoji"`
+	Channel    string `yaml:"channel" toml:"channel"`
+}
+
+// BacklogTarget は Backlog の通知先設定です。
+type BacklogTarget struct {
+	SpaceURL string `yaml:"space_url" toml:"space_url"`
+	APIKey   string `yaml:"api_key" toml:"api_key"`
+	// Project: 課題を登録する既定のプロジェクトキー（またはID）
+	Project string `yaml:"project" toml:"project"`
+	// IssueType: 既定の課題種別の ID または名前
+	IssueType string `yaml:"issue_type" toml:"issue_type"`
+	// Priority: 既定の優先度の ID または名前
+	Priority string `yaml:"priority" toml:"priority"`
+	// Emoji: 絵文字の変換方法 (shortcode, ascii, strip, keep。空の場合は shortcode)
+	Emoji string `yaml:"emoji" toml:"emoji"`
+	// EmojiASCII: Emoji が ascii の場合の、絵文字ごとの ASCII 表記 (例: "✅": "[OK]")
+	EmojiASCII map[string]string `yaml:"emoji_ascii" toml:"emoji_ascii"`
+}
+
+// TeamsTarget は Microsoft Teams の通知先設定です。
+type TeamsTarget struct {
+	// WebhookURL: Incoming Webhook または Workflows の Webhook URL
+	WebhookURL string `yaml:"webhook_url" toml:"webhook_url"`
+}
+
+// DiscordTarget は Discord の通知先設定です。
+type DiscordTarget struct {
+	WebhookURL string `yaml:"webhook_url" toml:"webhook_url"`
+	// Username: Webhook の既定の名前を上書きするユーザー名
+	Username string `yaml:"username" toml:"username"`
+	// AvatarURL: Webhook の既定のアバターを上書きする画像の URL
+	AvatarURL string `yaml:"avatar_url" toml:"avatar_url"`
+}
+
+// GChatTarget は Google Chat の通知先設定です。
+type GChatTarget struct {
+	WebhookURL string `yaml:"webhook_url" toml:"webhook_url"`
+	// ThreadKey: 同じキーのメッセージを1つのスレッドにまとめるためのキー
+	ThreadKey string `yaml:"thread_key" toml:"thread_key"`
+}
+
+// ChatworkTarget は Chatwork の通知先設定です。
+type ChatworkTarget struct {
+	APIToken string `yaml:"api_token" toml:"api_token"`
+	// RoomID: 投稿先のルーム ID
+	RoomID string `yaml:"room_id" toml:"room_id"`
+	// To: メッセージの宛先 ([To:accountId]) に指定するアカウント ID
+	To []int `yaml:"to" toml:"to"`
+}
+
+// EmailTarget はメール (SMTP) の通知先設定です。
+type EmailTarget struct {
+	Host string `yaml:"host" toml:"host"`
+	// Port: SMTP サーバーのポート (0 の場合は tls が "tls" なら 465、それ以外は 587)
+	Port     int    `yaml:"port" toml:"port"`
+	Username string `yaml:"username" toml:"username"`
+	Password string `yaml:"password" toml:"password"`
+	// From: 送信者のアドレス (例: "通知 <notifier@example.com>")
+	From    string   `yaml:"from" toml:"from"`
+	To      []string `yaml:"to" toml:"to"`
+	Cc      []string `yaml:"cc" toml:"cc"`
+	Bcc     []string `yaml:"bcc" toml:"bcc"`
+	ReplyTo []string `yaml:"reply_to" toml:"reply_to"`
+	// TLS: 接続の暗号化方法 (starttls, tls, none。空の場合は starttls)
+	TLS string `yaml:"tls" toml:"tls"`
+	// Auth: 認証方式 (plain, login。空の場合は plain)
+	Auth string `yaml:"auth" toml:"auth"`
+	// Headers: 追加するヘッダー (例: "X-Mailer": "go-notifier")
+	Headers map[string]string `yaml:"headers" toml:"headers"`
+}
+
+// Profile は、まとめて使用する通知先の組です。
+type Profile struct {
+	Targets []string `yaml:"targets" toml:"targets"`
+}
+
+// Routing は、メッセージの内容から通知先を決定するルーティング設定です。
+type Routing struct {
+	// DefaultTargets: どのルートにもマッチしない場合の通知先
+	DefaultTargets []string `yaml:"default_targets" toml:"default_targets"`
+	// Routes: 先頭から順に評価されるルート
+	Routes []Route `yaml:"routes" toml:"routes"`
+}
+
+// Route は、1つのルーティングルールです。
+type Route struct {
+	Name    string     `yaml:"name" toml:"name"`
+	Match   RouteMatch `yaml:"match" toml:"match"`
+	Targets []string   `yaml:"targets" toml:"targets"`
+	// Continue: true の場合、マッチした後も後続のルートを評価します
+	Continue bool `yaml:"continue" toml:"continue"`
+}
+
+// RouteMatch は、ルートの適用条件です。設定された条件はすべて満たす必要があります。
+type RouteMatch struct {
+	// Severity: いずれかの重要度に一致すること
+	Severity []string `yaml:"severity" toml:"severity"`
+	// MinSeverity: この重要度以上であること
+	MinSeverity string `yaml:"min_severity" toml:"min_severity"`
+	// Tags: すべてのタグを含むこと
+	Tags []string `yaml:"tags" toml:"tags"`
+	// Title: タイトルにマッチする正規表現
+	Title string `yaml:"title" toml:"title"`
+	// Labels: 値が一致すべきラベル (例: service: api)
+	Labels map[string]string `yaml:"labels" toml:"labels"`
+}
+
+// Load は、指定されたパスの設定ファイルを読み込みます。
+// 形式は拡張子 (.yaml, .yml, .toml) で判定します。
+// 通知先設定の文字列値に含まれる ${VAR} / ${VAR:-default} は、通知先を取得する時点で環境変数から展開されます。
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+
+	var cfg Config
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("YAML設定ファイルのパースに失敗しました (%s): %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), &cfg)
+		if err != nil {
+			return nil, fmt.Errorf("TOML設定ファイルのパースに失敗しました (%s): %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("TOML設定ファイルに不明なキーがあります (%s): %v", path, undecoded)
+		}
+	default:
+		return nil, fmt.Errorf("未対応の設定ファイル形式です: %q (.yaml, .yml, .toml のいずれかを使用してください)", ext)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// DefaultPath は、明示的な指定がない場合に使用する設定ファイルのパスを返します。
+// 環境変数 NOTIFIER_CONFIG、ユーザー設定ディレクトリ配下の notifier/config.{yaml,yml,toml} の順に探索し、
+// 見つからなければ空文字列を返します。
+func DefaultPath() string {
+	if p := os.Getenv(ConfigEnv); p != "" {
+		return p
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	for _, name := range []string{"config.yaml", "config.yml", "config.toml"} {
+		p := filepath.Join(dir, "notifier", name)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// SplitTargetName は "slack.alerts" 形式の通知先名を種別と名前に分割します。
+func SplitTargetName(name string) (kind, key string, err error) {
+	kind, key, ok := strings.Cut(name, ".")
+	if !ok || kind == "" || key == "" {
+		return "", "", fmt.Errorf("通知先名は「種別.名前」の形式で指定してください (例: slack.alerts): %q", name)
+	}
+	switch kind {
+	case KindSlack, KindBacklog, KindTeams, KindDiscord, KindGChat, KindChatwork, KindEmail:
+		return kind, key, nil
+	default:
+		return "", "", fmt.Errorf("不明な通知先の種別です: %q (slack, backlog, teams, discord, gchat, chatwork, email のいずれかを指定してください)", kind)
+	}
+}
+
+// SlackTarget は、名前で指定された Slack の通知先設定を、環境変数を展開して返します。
+// name は "alerts" と "slack.alerts" のどちらの形式でも指定できます。
+func (c *Config) SlackTarget(name string) (SlackTarget, error) {
+	key := strings.TrimPrefix(name, KindSlack+".")
+	t, ok := c.Slack[key]
+	if !ok {
+		return SlackTarget{}, fmt.Errorf("Slack の通知先 %q が設定ファイルに定義されていません (定義済み: %s)", key, strings.Join(sortedKeys(c.Slack), ", "))
+	}
+	if err := expandEnv(KindSlack+"."+key, &t.WebhookURL, &t.BotToken, &t.Username, &t.IconEmoji, &t.Channel); err != nil {
+		return SlackTarget{}, err
+	}
+	return t, nil
+}
+
+// BacklogTarget は、名前で指定された Backlog の通知先設定を、環境変数を展開して返します。
+// name は "ops" と "backlog.ops" のどちらの形式でも指定できます。
+func (c *Config) BacklogTarget(name string) (BacklogTarget, error) {
+	key := strings.TrimPrefix(name, KindBacklog+".")
+	t, ok := c.Backlog[key]
+	if !ok {
+		return BacklogTarget{}, fmt.Errorf("Backlog の通知先 %q が設定ファイルに定義されていません (定義済み: %s)", key, strings.Join(sortedKeys(c.Backlog), ", "))
+	}
+	if err := expandEnv(KindBacklog+"."+key, &t.SpaceURL, &t.APIKey, &t.Project, &t.IssueType, &t.Priority, &t.Emoji); err != nil {
+		return BacklogTarget{}, err
+	}
+	return t, nil
+}
+
+// TeamsTarget は、名前で指定された Teams の通知先設定を、環境変数を展開して返します。
+// name は "general" と "teams.general" のどちらの形式でも指定できます。
+func (c *Config) TeamsTarget(name string) (TeamsTarget, error) {
+	key := strings.TrimPrefix(name, KindTeams+".")
+	t, ok := c.Teams[key]
+	if !ok {
+		return TeamsTarget{}, fmt.Errorf("Teams の通知先 %q が設定ファイルに定義されていません (定義済み: %s)", key, strings.Join(sortedKeys(c.Teams), ", "))
+	}
+	if err := expandEnv(KindTeams+"."+key, &t.WebhookURL); err != nil {
+		return TeamsTarget{}, err
+	}
+	return t, nil
+}
+
+// DiscordTarget は、名前で指定された Discord の通知先設定を、環境変数を展開して返します。
+// name は "releases" と "discord.releases" のどちらの形式でも指定できます。
+func (c *Config) DiscordTarget(name string) (DiscordTarget, error) {
+	key := strings.TrimPrefix(name, KindDiscord+".")
+	t, ok := c.Discord[key]
+	if !ok {
+		return DiscordTarget{}, fmt.Errorf("Discord の通知先 %q が設定ファイルに定義されていません (定義済み: %s)", key, strings.Join(sortedKeys(c.Discord), ", "))
+	}
+	if err := expandEnv(KindDiscord+"."+key, &t.WebhookURL, &t.Username, &t.AvatarURL); err != nil {
+		return DiscordTarget{}, err
+	}
+	return t, nil
+}
+
+// GChatTarget は、名前で指定された Google Chat の通知先設定を、環境変数を展開して返します。
+// name は "oncall" と "gchat.oncall" のどちらの形式でも指定できます。
+func (c *Config) GChatTarget(name string) (GChatTarget, error) {
+	key := strings.TrimPrefix(name, KindGChat+".")
+	t, ok := c.GChat[key]
+	if !ok {
+		return GChatTarget{}, fmt.Errorf("Google Chat の通知先 %q が設定ファイルに定義されていません (定義済み: %s)", key, strings.Join(sortedKeys(c.GChat), ", "))
+	}
+	if err := expandEnv(KindGChat+"."+key, &t.WebhookURL, &t.ThreadKey); err != nil {
+		return GChatTarget{}, err
+	}
+	return t, nil
+}
+
+// ChatworkTarget は、名前で指定された Chatwork の通知先設定を、環境変数を展開して返します。
+// name は "dev" と "chatwork.dev" のどちらの形式でも指定できます。
+func (c *Config) ChatworkTarget(name string) (ChatworkTarget, error) {
+	key := strings.TrimPrefix(name, KindChatwork+".")
+	t, ok := c.Chatwork[key]
+	if !ok {
+		return ChatworkTarget{}, fmt.Errorf("Chatwork の通知先 %q が設定ファイルに定義されていません (定義済み: %s)", key, strings.Join(sortedKeys(c.Chatwork), ", "))
+	}
+	if err := expandEnv(KindChatwork+"."+key, &t.APIToken, &t.RoomID); err != nil {
+		return ChatworkTarget{}, err
+	}
+	return t, nil
+}
+
+// EmailTarget は、名前で指定されたメールの通知先設定を、環境変数を展開して返します。
+// name は "stakeholders" と "email.stakeholders" のどちらの形式でも指定できます。
+func (c *Config) EmailTarget(name string) (EmailTarget, error) {
+	key := strings.TrimPrefix(name, KindEmail+".")
+	t, ok := c.Email[key]
+	if !ok {
+		return EmailTarget{}, fmt.Errorf("メールの通知先 %q が設定ファイルに定義されていません (定義済み: %s)", key, strings.Join(sortedKeys(c.Email), ", "))
+	}
+	if err := expandEnv(KindEmail+"."+key, &t.Host, &t.Username, &t.Password, &t.From); err != nil {
+		return EmailTarget{}, err
+	}
+	if err := expandEnvList(KindEmail+"."+key, &t.To, &t.Cc, &t.Bcc, &t.ReplyTo); err != nil {
+		return EmailTarget{}, err
+	}
+	if err := expandEnvMap(KindEmail+"."+key, &t.Headers); err != nil {
+		return EmailTarget{}, err
+	}
+	return t, nil
+}
+
+// ResolveTargets は、プロファイル名と明示的な通知先名から、使用する通知先名の一覧を重複なく返します。
+// どちらも指定されていない場合は DefaultProfile を使用します。
+func (c *Config) ResolveTargets(profile string, targets []string) ([]string, error) {
+	if profile == "" && len(targets) == 0 {
+		profile = c.DefaultProfile
+	}
+
+	var names []string
+	if profile != "" {
+		p, ok := c.Profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("プロファイル %q が設定ファイルに定義されていません (定義済み: %s)", profile, strings.Join(sortedKeys(c.Profiles), ", "))
+		}
+		names = append(names, p.Targets...)
+	}
+	names = append(names, targets...)
+
+	seen := make(map[string]bool, len(names))
+	resolved := make([]string, 0, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		if err := c.checkTarget(name); err != nil {
+			return nil, err
+		}
+		seen[name] = true
+		resolved = append(resolved, name)
+	}
+	return resolved, nil
+}
+
+// checkTarget は、通知先名が設定ファイルに定義されているかを確認します。
+func (c *Config) checkTarget(name string) error {
+	kind, key, err := SplitTargetName(name)
+	if err != nil {
+		return err
+	}
+
+	defined := false
+	switch kind {
+	case KindSlack:
+		_, defined = c.Slack[key]
+	case KindBacklog:
+		_, defined = c.Backlog[key]
+	case KindTeams:
+		_, defined = c.Teams[key]
+	case KindDiscord:
+		_, defined = c.Discord[key]
+	case KindGChat:
+		_, defined = c.GChat[key]
+	case KindChatwork:
+		_, defined = c.Chatwork[key]
+	case KindEmail:
+		_, defined = c.Email[key]
+	}
+	if !defined {
+		return fmt.Errorf("通知先 %q が設定ファイルに定義されていません", name)
+	}
+	return nil
+}
+
+// validate は、プロファイルとルートが参照する通知先が定義されているか、およびルートの条件が正しいかを検証します。
+func (c *Config) validate() error {
+	var errs []error
+	for _, name := range sortedKeys(c.Profiles) {
+		for _, target := range c.Profiles[name].Targets {
+			if err := c.checkTarget(target); err != nil {
+				errs = append(errs, fmt.Errorf("プロファイル %q: %w", name, err))
+			}
+		}
+	}
+	for i, route := range c.Routing.Routes {
+		label := route.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		if len(route.Targets) == 0 {
+			errs = append(errs, fmt.Errorf("ルート %s: targets が指定されていません", label))
+		}
+		for _, target := range route.Targets {
+			if err := c.checkTarget(target); err != nil {
+				errs = append(errs, fmt.Errorf("ルート %s: %w", label, err))
+			}
+		}
+		if route.Match.Title != "" {
+			if _, err := regexp.Compile(route.Match.Title); err != nil {
+				errs = append(errs, fmt.Errorf("ルート %s: title の正規表現が不正です: %w", label, err))
+			}
+		}
+	}
+	for _, target := range c.Routing.DefaultTargets {
+		if err := c.checkTarget(target); err != nil {
+			errs = append(errs, fmt.Errorf("routing.default_targets: %w", err))
+		}
+	}
+	if c.DefaultProfile != "" {
+		if _, ok := c.Profiles[c.DefaultProfile]; !ok {
+			errs = append(errs, fmt.Errorf("default_profile %q が profiles に定義されていません", c.DefaultProfile))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// expandEnv は、通知先設定の文字列値に含まれる環境変数参照をその場で展開します。
+func expandEnv(target string, fields ...*string) error {
+	for _, f := range fields {
+		v, err := expandEnvString(*f)
+		if err != nil {
+			return fmt.Errorf("通知先 %s: %w", target, err)
+		}
+		*f = v
+	}
+	return nil
+}
+
+// expandEnvList は、通知先設定の文字列のリストに含まれる環境変数参照を展開します。
+// 設定ファイルの値を書き換えないように、展開した値は新しいスライスに格納します。
+func expandEnvList(target string, lists ...*[]string) error {
+	for _, list := range lists {
+		if *list == nil {
+			continue
+		}
+		expanded := make([]string, len(*list))
+		for i, s := range *list {
+			v, err := expandEnvString(s)
+			if err != nil {
+				return fmt.Errorf("通知先 %s: %w", target, err)
+			}
+			expanded[i] = v
+		}
+		*list = expanded
+	}
+	return nil
+}
+
+// expandEnvMap は、通知先設定のマップの値に含まれる環境変数参照を展開します。
+// 設定ファイルの値を書き換えないように、展開した値は新しいマップに格納します。
+func expandEnvMap(target string, m *map[string]string) error {
+	if *m == nil {
+		return nil
+	}
+	expanded := make(map[string]string, len(*m))
+	for k, s := range *m {
+		v, err := expandEnvString(s)
+		if err != nil {
+			return fmt.Errorf("通知先 %s: ヘッダー %s: %w", target, k, err)
+		}
+		expanded[k] = v
+	}
+	*m = expanded
+	return nil
+}
+
+// envRefRegex は ${VAR} および ${VAR:-default} 形式の環境変数参照にマッチします。
+var envRefRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// expandEnvString は、文字列中の環境変数参照を展開します。
+// 未設定の環境変数をデフォルト値なしで参照した場合はエラーを返します。
+func expandEnvString(s string) (string, error) {
+	var missing []string
+	expanded := envRefRegex.ReplaceAllStringFunc(s, func(ref string) string {
+		m := envRefRegex.FindStringSubmatch(ref)
+		if v, ok := os.LookupEnv(m[1]); ok && v != "" {
+			return v
+		}
+		if strings.Contains(ref, ":-") {
+			return m[2]
+		}
+		missing = append(missing, m[1])
+		return ""
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("環境変数が設定されていません: %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// sortedKeys は、エラーメッセージを安定させるためにマップのキーをソートして返します。
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,386 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeConfig は、一時ディレクトリに設定ファイルを作成してパスを返します。
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const testYAML = `
+default_profile: ops
+slack:
+  alerts:
+    webhook_url: ${TEST_SLACK_WEBHOOK}
+    channel: ${TEST_SLACK_CHANNEL:-#alerts}
+backlog:
+  ops:
+    space_url: https://example.backlog.jp
+    api_key: ${TEST_BACKLOG_API_KEY}
+    project: OPS
+email:
+  stakeholders:
+    host: smtp.example.com
+    from: notifier@example.com
+    to: [a@example.com]
+profiles:
+  ops:
+    targets: [slack.alerts, backlog.ops]
+  all:
+    targets: [slack.alerts, email.stakeholders, backlog.ops]
+routing:
+  default_targets: [slack.alerts]
+  routes:
+    - name: critical
+      match:
+        min_severity: critical
+        title: "^\\[prod\\]"
+      targets: [backlog.ops]
+      continue: true
+`
+
+const testTOML = `
+default_profile = "ops"
+
+[slack.alerts]
+webhook_url = "${TEST_SLACK_WEBHOOK}"
+channel = "${TEST_SLACK_CHANNEL:-#alerts}"
+
+[backlog.ops]
+space_url = "https://example.backlog.jp"
+api_key = "${TEST_BACKLOG_API_KEY}"
+project = "OPS"
+
+[email.stakeholders]
+host = "smtp.example.com"
+from = "notifier@example.com"
+to = ["a@example.com"]
+
+[profiles.ops]
+targets = ["slack.alerts", "backlog.ops"]
+
+[profiles.all]
+targets = ["slack.alerts", "email.stakeholders", "backlog.ops"]
+
+[routing]
+default_targets = ["slack.alerts"]
+
+[[routing.routes]]
+name = "critical"
+targets = ["backlog.ops"]
+continue = true
+
+[routing.routes.match]
+min_severity = "critical"
+title = '^\[prod\]'
+`
+
+func TestLoad(t *testing.T) {
+	for _, name := range []string{"config.yaml", "config.yml", "config.toml"} {
+		t.Run(name, func(t *testing.T) {
+			content := testYAML
+			if strings.HasSuffix(name, ".toml") {
+				content = testTOML
+			}
+			cfg, err := Load(writeConfig(t, name, content))
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			if cfg.DefaultProfile != "ops" {
+				t.Errorf("DefaultProfile = %q", cfg.DefaultProfile)
+			}
+			// 環境変数は読み込み時ではなく、通知先を取得する時点で展開する
+			if got := cfg.Slack["alerts"].WebhookURL; got != "${TEST_SLACK_WEBHOOK}" {
+				t.Errorf("raw webhook_url = %q, want the unexpanded reference", got)
+			}
+			if got := cfg.Email["stakeholders"].To; !slices.Equal(got, []string{"a@example.com"}) {
+				t.Errorf("email to = %v", got)
+			}
+			if len(cfg.Routing.Routes) != 1 {
+				t.Fatalf("routes = %+v", cfg.Routing.Routes)
+			}
+			route := cfg.Routing.Routes[0]
+			if route.Name != "critical" || !route.Continue || route.Match.MinSeverity != "critical" || route.Match.Title != `^\[prod\]` {
+				t.Errorf("route = %+v", route)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "YAML の不明なキー",
+			file:    "config.yaml",
+			content: "slack:\n  alerts:\n    webhook_url: https://hooks.slack.com/x\n    webhook: typo\n",
+			wantErr: "field webhook not found",
+		},
+		{
+			name:    "YAML の不明なトップレベルのキー",
+			file:    "config.yaml",
+			content: "slacks:\n  alerts: {}\n",
+			wantErr: "field slacks not found",
+		},
+		{
+			name:    "TOML の不明なキー",
+			file:    "config.toml",
+			content: "[slack.alerts]\nwebhook_url = \"https://hooks.slack.com/x\"\nwebhook = \"typo\"\n",
+			wantErr: "不明なキー",
+		},
+		{
+			name:    "未対応の拡張子",
+			file:    "config.json",
+			content: "{}",
+			wantErr: "未対応の設定ファイル形式",
+		},
+		{
+			name:    "プロファイルが未定義の通知先を参照",
+			file:    "config.yaml",
+			content: "profiles:\n  ops:\n    targets: [slack.missing]\n",
+			wantErr: `プロファイル "ops"`,
+		},
+		{
+			name:    "通知先名の種別が不明",
+			file:    "config.yaml",
+			content: "routing:\n  default_targets: [pager.ops]\n",
+			wantErr: "不明な通知先の種別",
+		},
+		{
+			name:    "ルートの正規表現が不正",
+			file:    "config.yaml",
+			content: "slack:\n  alerts: {}\nrouting:\n  routes:\n    - name: bad\n      match:\n        title: \"[\"\n      targets: [slack.alerts]\n",
+			wantErr: "title の正規表現が不正",
+		},
+		{
+			name:    "ルートの targets がない",
+			file:    "config.yaml",
+			content: "routing:\n  routes:\n    - name: empty\n",
+			wantErr: "targets が指定されていません",
+		},
+		{
+			name:    "default_profile が未定義",
+			file:    "config.yaml",
+			content: "default_profile: ops\n",
+			wantErr: `default_profile "ops"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExpandEnvString(t *testing.T) {
+	t.Setenv("TEST_SET", "value")
+	t.Setenv("TEST_EMPTY", "")
+	t.Setenv("TEST_UNSET", "")
+	os.Unsetenv("TEST_UNSET")
+
+	tests := []struct {
+		name    string
+		s       string
+		want    string
+		wantErr string
+	}{
+		{"参照なし", "https://example.com", "https://example.com", ""},
+		{"設定済みの変数", "${TEST_SET}", "value", ""},
+		{"文字列の途中の参照", "https://example.com/${TEST_SET}/hook", "https://example.com/value/hook", ""},
+		{"設定済みの変数はデフォルト値より優先", "${TEST_SET:-default}", "value", ""},
+		{"未設定の変数はデフォルト値", "${TEST_UNSET:-default}", "default", ""},
+		{"空の変数はデフォルト値", "${TEST_EMPTY:-default}", "default", ""},
+		{"空のデフォルト値", "${TEST_UNSET:-}", "", ""},
+		{"デフォルト値に記号を含む", "${TEST_UNSET:-#alerts:general}", "#alerts:general", ""},
+		{"$VAR 形式は展開しない", "$TEST_SET", "$TEST_SET", ""},
+		{"未設定の変数", "${TEST_UNSET}", "", "TEST_UNSET"},
+		{"空の変数", "${TEST_EMPTY}", "", "TEST_EMPTY"},
+		{"未設定の変数をすべて報告", "${TEST_UNSET}/${TEST_SET}/${TEST_MISSING}", "", "TEST_UNSET, TEST_MISSING"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandEnvString(tt.s)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandEnvString(%q): %v", tt.s, err)
+			}
+			if got != tt.want {
+				t.Errorf("expandEnvString(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTargetExpandsEnv(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "config.yaml", testYAML))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	t.Run("展開", func(t *testing.T) {
+		t.Setenv("TEST_SLACK_WEBHOOK", "https://hooks.slack.com/services/T/B/X")
+		for _, name := range []string{"alerts", "slack.alerts"} {
+			target, err := cfg.SlackTarget(name)
+			if err != nil {
+				t.Fatalf("SlackTarget(%q): %v", name, err)
+			}
+			if target.WebhookURL != "https://hooks.slack.com/services/T/B/X" || target.Channel != "#alerts" {
+				t.Errorf("SlackTarget(%q) = %+v", name, target)
+			}
+		}
+		// 展開は取得した値にのみ行い、読み込んだ設定は変更しない
+		if got := cfg.Slack["alerts"].WebhookURL; got != "${TEST_SLACK_WEBHOOK}" {
+			t.Errorf("config was modified: %q", got)
+		}
+	})
+
+	t.Run("メールの宛先とヘッダー", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, "email.yaml", `
+email:
+  oncall:
+    host: smtp.example.com
+    from: notifier@example.com
+    to: ["${TEST_EMAIL_TO}", b@example.com]
+    cc: ["${TEST_EMAIL_CC:-cc@example.com}"]
+    reply_to: ["${TEST_EMAIL_TO}"]
+    headers:
+      X-Environment: ${TEST_EMAIL_ENV}
+`))
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		t.Setenv("TEST_EMAIL_TO", "oncall@example.com")
+		t.Setenv("TEST_EMAIL_ENV", "prod")
+		target, err := cfg.EmailTarget("email.oncall")
+		if err != nil {
+			t.Fatalf("EmailTarget: %v", err)
+		}
+		if !slices.Equal(target.To, []string{"oncall@example.com", "b@example.com"}) || !slices.Equal(target.Cc, []string{"cc@example.com"}) ||
+			!slices.Equal(target.ReplyTo, []string{"oncall@example.com"}) || target.Bcc != nil || target.Headers["X-Environment"] != "prod" {
+			t.Errorf("EmailTarget = %+v", target)
+		}
+		if got := cfg.Email["oncall"]; got.To[0] != "${TEST_EMAIL_TO}" || got.Headers["X-Environment"] != "${TEST_EMAIL_ENV}" {
+			t.Errorf("config was modified: %+v", got)
+		}
+
+		os.Unsetenv("TEST_EMAIL_ENV")
+		if _, err := cfg.EmailTarget("oncall"); err == nil || !strings.Contains(err.Error(), "X-Environment") || !strings.Contains(err.Error(), "TEST_EMAIL_ENV") {
+			t.Errorf("err = %v, want it to name the header and the variable", err)
+		}
+	})
+
+	t.Run("未設定の環境変数", func(t *testing.T) {
+		t.Setenv("TEST_BACKLOG_API_KEY", "")
+		os.Unsetenv("TEST_BACKLOG_API_KEY")
+		_, err := cfg.BacklogTarget("backlog.ops")
+		if err == nil || !strings.Contains(err.Error(), "backlog.ops") || !strings.Contains(err.Error(), "TEST_BACKLOG_API_KEY") {
+			t.Fatalf("err = %v, want it to name the target and the variable", err)
+		}
+	})
+
+	t.Run("未定義の通知先", func(t *testing.T) {
+		_, err := cfg.SlackTarget("missing")
+		if err == nil || !strings.Contains(err.Error(), "定義済み: alerts") {
+			t.Fatalf("err = %v, want it to list the defined targets", err)
+		}
+	})
+}
+
+func TestResolveTargets(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "config.yaml", testYAML))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		profile string
+		targets []string
+		want    []string
+		wantErr string
+	}{
+		{"指定なしは default_profile", "", nil, []string{"slack.alerts", "backlog.ops"}, ""},
+		{"プロファイル", "all", nil, []string{"slack.alerts", "email.stakeholders", "backlog.ops"}, ""},
+		{"通知先のみを指定すると default_profile は使わない", "", []string{"email.stakeholders"}, []string{"email.stakeholders"}, ""},
+		{"プロファイルと通知先を重複なく結合", "ops", []string{"backlog.ops", "email.stakeholders"}, []string{"slack.alerts", "backlog.ops", "email.stakeholders"}, ""},
+		{"未定義のプロファイル", "dev", nil, nil, "定義済み: all, ops"},
+		{"未定義の通知先", "", []string{"slack.missing"}, nil, `"slack.missing"`},
+		{"種別のない通知先名", "", []string{"alerts"}, nil, "種別.名前"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cfg.ResolveTargets(tt.profile, tt.targets)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveTargets: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ResolveTargets(%q, %v) = %v, want %v", tt.profile, tt.targets, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Run("NOTIFIER_CONFIG", func(t *testing.T) {
+		t.Setenv(ConfigEnv, "/etc/notifier/config.toml")
+		if got := DefaultPath(); got != "/etc/notifier/config.toml" {
+			t.Errorf("DefaultPath() = %q", got)
+		}
+	})
+
+	t.Run("ユーザー設定ディレクトリ", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv(ConfigEnv, "")
+		t.Setenv("XDG_CONFIG_HOME", dir)
+		t.Setenv("HOME", dir)
+		t.Setenv("AppData", dir)
+		userDir, err := os.UserConfigDir()
+		if err != nil {
+			t.Skipf("UserConfigDir: %v", err)
+		}
+
+		if got := DefaultPath(); got != "" {
+			t.Errorf("DefaultPath() = %q, want empty without a config file", got)
+		}
+		want := filepath.Join(userDir, "notifier", "config.yml")
+		if err := os.MkdirAll(filepath.Dir(want), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(want, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if got := DefaultPath(); got != want {
+			t.Errorf("DefaultPath() = %q, want %q", got, want)
+		}
+	})
+}
@@ -1,24 +1,46 @@
 package notifier
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/shouni/go-http-kit/pkg/httpkit"
 	"github.com/shouni/go-utils/text"
 )
 
 // BacklogNotifier は Backlog 課題登録用の API クライアントです。
-// Notifier インターフェースを満たしますが、SendText および SendTextWithHeader は Backlog の利用方針（課題登録推奨）に基づきエラーを返します。
+// Notifier インターフェースを実装し、Send は ProjectKey のプロジェクトに課題を登録します。
+// SendText および SendTextWithHeader は Backlog の利用方針（課題登録推奨）に基づきエラーを返します。
 type BacklogNotifier struct {
 	client  httpkit.Client // 汎用クライアント (リトライ機能込み)
 	baseURL string
 	apiKey  string
+	// ProjectKey: Send で課題を登録する既定のプロジェクトキー（またはID）
+	ProjectKey string
+	// IssueType: 既定の課題種別の ID または名前（大文字小文字を区別しない。空の場合はプロジェクトの先頭の課題種別）
+	IssueType string
+	// Priority: 既定の優先度の ID または名前（大文字小文字を区別しない。空の場合は「中 (Normal)」）
+	Priority string
+	// Deduplicate: Send で、同じ事象の課題を重複して登録せずに既存の課題へコメントを追加する (EnsureIssue を使用)
+	Deduplicate bool
+	// Reopen: Deduplicate が有効な場合に、一致した完了済みの課題を「未対応」に戻す (false の場合は新しい課題を登録)
+	Reopen bool
+	// EmojiPolicy: 課題やコメントに含まれる絵文字の変換方法 (空の場合は EmojiShortcode)
+	// 絵文字を保存できない (utf8mb4 に未対応の) スペースでは、EmojiKeep 以外を指定してください
+	EmojiPolicy EmojiPolicy
+	// EmojiASCII: EmojiPolicy が EmojiASCII の場合の、絵文字ごとの ASCII 表記 (DefaultEmojiASCII より優先)
+	EmojiASCII map[string]string
+	// MaxAttachmentSize: 添付ファイル1つあたりのサイズの上限 (バイト。0 の場合は DefaultBacklogAttachmentLimit)
+	MaxAttachmentSize int64
+	// Cache: プロジェクト・課題種別・優先度などのメタデータのキャッシュ (既定の nil の場合はキャッシュせず毎回取得)
+	Cache *BacklogMetadataCache
 }
 
 // BacklogProjectResponse はプロジェクトキーまたはIDで取得した際のレスポンスを扱います。
@@ -40,13 +62,166 @@ type BacklogPriorityResponse struct {
 	Name string `json:"name"`
 }
 
+// BacklogStatusResponse は課題の状態の最小限の構造体です。
+type BacklogStatusResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// BacklogUserResponse はユーザーの最小限の構造体です。
+type BacklogUserResponse struct {
+	ID     int    `json:"id"`
+	UserID string `json:"userId"`
+	Name   string `json:"name"`
+}
+
+// BacklogIssueResponse は課題の取得・更新APIのレスポンスです。
+type BacklogIssueResponse struct {
+	ID          int                        `json:"id"`
+	ProjectID   int                        `json:"projectId"`
+	IssueKey    string                     `json:"issueKey"`
+	KeyID       int                        `json:"keyId"`
+	Summary     string                     `json:"summary"`
+	Description string                     `json:"description"`
+	IssueType   *BacklogIssueTypeResponse  `json:"issueType"`
+	Priority    *BacklogPriorityResponse   `json:"priority"`
+	Status      *BacklogStatusResponse     `json:"status"`
+	Resolution  *BacklogResolutionResponse `json:"resolution"`
+	Assignee    *BacklogUserResponse       `json:"assignee"`
+	StartDate   string                     `json:"startDate"`
+	DueDate     string                     `json:"dueDate"`
+	Created     string                     `json:"created"`
+	Updated     string                     `json:"updated"`
+	// CustomFields: 課題に設定されたカスタム属性の値
+	CustomFields []BacklogIssueCustomField `json:"customFields"`
+}
+
+// BacklogIssueResult は課題の登録結果です。
+type BacklogIssueResult struct {
+	// ID: 課題ID
+	ID int `json:"id"`
+	// ProjectID: 課題を登録したプロジェクトのID
+	ProjectID int `json:"projectId"`
+	// IssueKey: 課題キー (例: PROJECT-123)
+	IssueKey string `json:"issueKey"`
+	// URL: ブラウザで課題を開くためのURL
+	URL string `json:"url"`
+	// Created: 登録日時 (Backlog API が返す ISO 8601 形式)
+	Created string `json:"created"`
+	// Truncations: 上限を超えたために切り詰めた項目 (絵文字の変換後の長さで判定)
+	Truncations []Truncation `json:"truncations,omitempty"`
+}
+
+// BacklogCommentResponse はコメントの投稿APIのレスポンスです。
+type BacklogCommentResponse struct {
+	ID      int    `json:"id"`
+	Content string `json:"content"`
+	Created string `json:"created"`
+}
+
+// BacklogCommentResult はコメントの投稿結果です。
+type BacklogCommentResult struct {
+	// ID: コメントID
+	ID int `json:"id"`
+	// IssueKey: コメントを投稿した課題のキー (課題IDを指定した場合も課題キー)
+	IssueKey string `json:"issueKey"`
+	// URL: ブラウザでコメントを開くためのURL
+	URL string `json:"url"`
+	// Created: 投稿日時 (Backlog API が返す ISO 8601 形式)
+	Created string `json:"created"`
+}
+
+// BacklogIssueUpdate は課題の更新内容です。空の項目は変更されません。
+type BacklogIssueUpdate struct {
+	Summary     string
+	Description string
+	// Status: 状態の ID または名前 (例: "処理中", "完了")。プロジェクト独自の状態も指定できます
+	Status string
+	// Resolution: 完了理由の ID または名前 (例: "対応済み", "重複")
+	Resolution string
+	// Assignee: 担当者のユーザー ID、ログイン名または表示名
+	Assignee string
+	// Comment: 更新と同時に追加するコメント
+	Comment string
+	// CustomFields: 更新するカスタム属性の値
+	CustomFields *BacklogCustomFields
+	// Attachments: 課題に添付するファイル (Comment を指定した場合はコメントに添付されます)
+	Attachments []BacklogAttachment
+}
+
+// BacklogCategoryResponse はカテゴリーの最小限の構造体です。
+type BacklogCategoryResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// BacklogVersionResponse はマイルストーン・発生バージョンの最小限の構造体です。
+type BacklogVersionResponse struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Archived bool   `json:"archived"`
+}
+
 // BacklogIssuePayload は課題登録API (/issues) に必要なペイロードです。
+// Backlog API はフォーム形式のパラメータを受け付けるため、Values でフォームの値に変換して送信します。
 type BacklogIssuePayload struct {
 	ProjectID   int    `json:"projectId"`
 	Summary     string `json:"summary"`
 	Description string `json:"description"`
 	IssueTypeID int    `json:"issueTypeId"` // 必須
 	PriorityID  int    `json:"priorityId"`  // 必須
+	// 以下は任意の項目 (ゼロ値の場合は送信しません)
+	ParentIssueID  int     `json:"parentIssueId,omitempty"`
+	StartDate      string  `json:"startDate,omitempty"`
+	DueDate        string  `json:"dueDate,omitempty"`
+	EstimatedHours float64 `json:"estimatedHours,omitempty"`
+	AssigneeID     int     `json:"assigneeId,omitempty"`
+	CategoryIDs    []int   `json:"categoryId,omitempty"`
+	VersionIDs     []int   `json:"versionId,omitempty"`
+	MilestoneIDs   []int   `json:"milestoneId,omitempty"`
+	AttachmentIDs  []int   `json:"attachmentId,omitempty"`
+	// CustomFields: カスタム属性のフォームパラメータ (customField_{id})
+	CustomFields map[string][]string `json:"-"`
+}
+
+// Values は、ペイロードを課題登録APIのフォームパラメータに変換します。
+func (p BacklogIssuePayload) Values() url.Values {
+	form := url.Values{}
+	form.Set("projectId", strconv.Itoa(p.ProjectID))
+	form.Set("summary", p.Summary)
+	form.Set("description", p.Description)
+	form.Set("issueTypeId", strconv.Itoa(p.IssueTypeID))
+	form.Set("priorityId", strconv.Itoa(p.PriorityID))
+
+	if p.ParentIssueID != 0 {
+		form.Set("parentIssueId", strconv.Itoa(p.ParentIssueID))
+	}
+	if p.StartDate != "" {
+		form.Set("startDate", p.StartDate)
+	}
+	if p.DueDate != "" {
+		form.Set("dueDate", p.DueDate)
+	}
+	if p.EstimatedHours != 0 {
+		form.Set("estimatedHours", strconv.FormatFloat(p.EstimatedHours, 'f', -1, 64))
+	}
+	if p.AssigneeID != 0 {
+		form.Set("assigneeId", strconv.Itoa(p.AssigneeID))
+	}
+	for _, id := range p.CategoryIDs {
+		form.Add("categoryId[]", strconv.Itoa(id))
+	}
+	for _, id := range p.VersionIDs {
+		form.Add("versionId[]", strconv.Itoa(id))
+	}
+	for _, id := range p.MilestoneIDs {
+		form.Add("milestoneId[]", strconv.Itoa(id))
+	}
+	addAttachmentIDs(form, p.AttachmentIDs)
+	for k, v := range p.CustomFields {
+		form[k] = v
+	}
+	return form
 }
 
 // BacklogErrorResponse はBacklog APIが返す一般的なエラー構造体です。
@@ -69,6 +244,8 @@ func (e *BacklogError) Error() string {
 }
 
 // NewBacklogNotifier はBacklogNotifierを初期化します。
+// メタデータのキャッシュは無効 (Cache が nil) の状態で作成されます。長く動作するプロセスでは NewBacklogMetadataCache を、
+// CLI のように実行ごとに終了するプロセスでは UseFileCache を使用して有効にしてください。
 func NewBacklogNotifier(client httpkit.Client, spaceURL string, apiKey string) (*BacklogNotifier, error) {
 	if spaceURL == "" || apiKey == "" {
 		return nil, errors.New("BACKLOG_SPACE_URL および BACKLOG_API_KEY の設定が必要です")
@@ -91,13 +268,10 @@ func (c *BacklogNotifier) GetProjectID(ctx context.Context, projectKey string) (
 	if projectKey == "" {
 		return 0, errors.New("プロジェクトIDまたはキーは空にできません")
 	}
-	endpoint := fmt.Sprintf("/projects/%s", projectKey)
-	fullURL := fmt.Sprintf("%s%s?apiKey=%s", c.baseURL, endpoint, c.apiKey)
+	endpoint := "/projects/" + url.PathEscape(projectKey)
 
 	var projectResp BacklogProjectResponse
-	err := c.client.FetchAndDecodeJSON(fullURL, ctx, &projectResp)
-	if err != nil {
-		// FetchAndDecodeJSON がリトライとパースエラーを包括的に返す
+	if err := c.getCachedJSON(ctx, endpoint, &projectResp); err != nil {
 		return 0, fmt.Errorf("Backlog APIへのプロジェクト情報取得リクエストに失敗: %w", err)
 	}
 
@@ -109,81 +283,445 @@ func (c *BacklogNotifier) GetProjectID(ctx context.Context, projectKey string) (
 	return projectResp.ID, nil
 }
 
-// getFirstIssueAttributes は、指定されたプロジェクトの最初の有効な IssueTypeID と PriorityID を取得します。
-func (c *BacklogNotifier) getFirstIssueAttributes(ctx context.Context, projectID int) (issueTypeID int, priorityID int, err error) {
-	// 1. 課題種別 (Issue Types) の取得
+// backlogDefaultPriorityID は、Backlog の標準の優先度「中 (Normal)」の ID です (優先度はスペースによらず共通です)。
+const backlogDefaultPriorityID = 3
+
+// BacklogIssueOptions は課題登録時の任意の設定です。
+type BacklogIssueOptions struct {
+	// IssueTypeID: 課題種別の ID (0 以外の場合は IssueType より優先)
+	IssueTypeID int
+	// IssueType: 課題種別の ID (数字) または名前 (大文字小文字を区別しない)。空の場合は BacklogNotifier.IssueType
+	IssueType string
+	// PriorityID: 優先度の ID (0 以外の場合は Priority より優先)
+	PriorityID int
+	// Priority: 優先度の ID (数字) または名前 (大文字小文字を区別しない)。空の場合は BacklogNotifier.Priority
+	Priority string
+
+	// Assignee: 担当者のユーザー ID (数字)、ログイン名 (userId) または表示名
+	Assignee string
+	// StartDate / DueDate: 開始日・期限日 (yyyy-MM-dd 形式)
+	StartDate string
+	DueDate   string
+	// EstimatedHours: 予定時間 (0 の場合は設定しません)
+	EstimatedHours float64
+	// Categories / Milestones / Versions: カテゴリー・マイルストーン・発生バージョンの ID または名前
+	Categories []string
+	Milestones []string
+	Versions   []string
+	// ParentIssue: 親課題の課題キー (例: PROJECT-123) または課題ID
+	ParentIssue string
+	// CustomFields: カスタム属性の値 (必須のカスタム属性が未設定の場合は送信前にエラーになります)
+	CustomFields *BacklogCustomFields
+	// Attachments: 課題に添付するファイル
+	Attachments []BacklogAttachment
+}
+
+// backlogChoice は、ID と名前で識別される Backlog のメタデータ (課題種別・優先度など) の選択肢です。
+type backlogChoice struct {
+	ID   int
+	Name string
+	// Alias: 名前の別名 (ユーザーのログイン名など)
+	Alias string
+}
+
+// resolveBacklogChoice は、ID (数字) または名前 (大文字小文字を区別しない) で選択肢を探し、その ID を返します。
+// 見つからない場合は、有効な選択肢を列挙したエラーを返します。kind はエラーメッセージに使用する項目名です。
+func resolveBacklogChoice(kind, value string, choices []backlogChoice) (int, error) {
+	value = strings.TrimSpace(value)
+	if id, err := strconv.Atoi(value); err == nil {
+		for _, c := range choices {
+			if c.ID == id {
+				return c.ID, nil
+			}
+		}
+	}
+	for _, c := range choices {
+		if strings.EqualFold(c.Name, value) || (c.Alias != "" && strings.EqualFold(c.Alias, value)) {
+			return c.ID, nil
+		}
+	}
+
+	valid := make([]string, 0, len(choices))
+	for _, c := range choices {
+		if c.Alias != "" {
+			valid = append(valid, fmt.Sprintf("%s / %s (ID: %d)", c.Alias, c.Name, c.ID))
+		} else {
+			valid = append(valid, fmt.Sprintf("%s (ID: %d)", c.Name, c.ID))
+		}
+	}
+	return 0, fmt.Errorf("%s %q が見つかりません (有効な値: %s)", kind, value, strings.Join(valid, ", "))
+}
+
+// resolveIssueAttributes は、課題登録に使用する課題種別 ID と優先度 ID を決定します。
+// 指定は opts > BacklogNotifier の IssueType / Priority の順に優先し、いずれも空の場合は
+// プロジェクトの先頭の課題種別と、標準の優先度「中 (Normal)」を使用します。
+func (c *BacklogNotifier) resolveIssueAttributes(ctx context.Context, projectID int, opts BacklogIssueOptions) (issueTypeID int, priorityID int, err error) {
+	// 1. 課題種別 (Issue Types) の決定
 	// エンドポイント: /projects/{projectId}/issueTypes
-	issueTypeURL := fmt.Sprintf("%s/projects/%d/issueTypes?apiKey=%s", c.baseURL, projectID, c.apiKey)
-	issueTypeData, fetchErr := c.client.FetchBytes(issueTypeURL, ctx)
-	if fetchErr != nil {
-		return 0, 0, fmt.Errorf("課題種別リストの取得に失敗: %w", fetchErr)
+	issueTypeID = opts.IssueTypeID
+	if issueTypeID == 0 {
+		issueTypes, err := c.ListIssueTypes(ctx, projectID)
+		if err != nil {
+			return 0, 0, err
+		}
+		if len(issueTypes) == 0 {
+			return 0, 0, fmt.Errorf("プロジェクトの課題種別が見つかりませんでした (ProjectID: %d)", projectID)
+		}
+
+		issueType := opts.IssueType
+		if issueType == "" {
+			issueType = c.IssueType
+		}
+		if issueType == "" {
+			issueTypeID = issueTypes[0].ID
+		} else {
+			choices := make([]backlogChoice, 0, len(issueTypes))
+			for _, it := range issueTypes {
+				choices = append(choices, backlogChoice{ID: it.ID, Name: it.Name})
+			}
+			if issueTypeID, err = resolveBacklogChoice("課題種別", issueType, choices); err != nil {
+				return 0, 0, err
+			}
+		}
 	}
 
-	var issueTypes []BacklogIssueTypeResponse
-	if err := json.Unmarshal(issueTypeData, &issueTypes); err != nil {
-		return 0, 0, fmt.Errorf("課題種別リストのパースに失敗しました (ProjectID: %d): %w", projectID, err)
+	// 2. 優先度 (Priorities) の決定
+	// エンドポイント: /priorities (優先度はプロジェクト共通)
+	priorityID = opts.PriorityID
+	if priorityID != 0 {
+		return issueTypeID, priorityID, nil
+	}
+	priority := opts.Priority
+	if priority == "" {
+		priority = c.Priority
+	}
+	if priority == "" {
+		return issueTypeID, backlogDefaultPriorityID, nil
+	}
+
+	priorities, err := c.ListPriorities(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	choices := make([]backlogChoice, 0, len(priorities))
+	for _, p := range priorities {
+		choices = append(choices, backlogChoice{ID: p.ID, Name: p.Name})
+	}
+	if priorityID, err = resolveBacklogChoice("優先度", priority, choices); err != nil {
+		return 0, 0, err
+	}
+
+	return issueTypeID, priorityID, nil
+}
+
+// resolveIssueFields は、opts の任意項目 (担当者・日付・カテゴリーなど) を検証・解決し、ペイロードに設定します。
+// 名前で指定された項目は、指定されている場合にのみメタデータのエンドポイントから一覧を取得して ID に変換します。
+func (c *BacklogNotifier) resolveIssueFields(ctx context.Context, projectID int, opts BacklogIssueOptions, payload *BacklogIssuePayload) error {
+	for _, d := range []struct{ name, value string }{
+		{"開始日", opts.StartDate},
+		{"期限日", opts.DueDate},
+	} {
+		if d.value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d.value); err != nil {
+			return fmt.Errorf("%s %q の形式が不正です (yyyy-MM-dd 形式で指定してください)", d.name, d.value)
+		}
+	}
+	payload.StartDate = opts.StartDate
+	payload.DueDate = opts.DueDate
+
+	if opts.EstimatedHours < 0 {
+		return fmt.Errorf("予定時間は 0 以上で指定してください: %v", opts.EstimatedHours)
+	}
+	payload.EstimatedHours = opts.EstimatedHours
+
+	if opts.Assignee != "" {
+		var err error
+		if payload.AssigneeID, err = c.resolveAssignee(ctx, projectID, opts.Assignee); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.Categories) > 0 {
+		categories, err := c.ListCategories(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		choices := make([]backlogChoice, 0, len(categories))
+		for _, cat := range categories {
+			choices = append(choices, backlogChoice{ID: cat.ID, Name: cat.Name})
+		}
+		if payload.CategoryIDs, err = resolveBacklogChoices("カテゴリー", opts.Categories, choices); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.Milestones) > 0 || len(opts.Versions) > 0 {
+		versions, err := c.ListVersions(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		choices := make([]backlogChoice, 0, len(versions))
+		for _, v := range versions {
+			if !v.Archived {
+				choices = append(choices, backlogChoice{ID: v.ID, Name: v.Name})
+			}
+		}
+		if payload.MilestoneIDs, err = resolveBacklogChoices("マイルストーン", opts.Milestones, choices); err != nil {
+			return err
+		}
+		if payload.VersionIDs, err = resolveBacklogChoices("発生バージョン", opts.Versions, choices); err != nil {
+			return err
+		}
 	}
 
-	// 💡 修正ロジック: "タスク" を優先して探す
-	foundIssueTypeID := 0
-	for _, it := range issueTypes {
-		if it.Name == "タスク" { // あるいは設定可能なデフォルト値
-			foundIssueTypeID = it.ID
-			break
+	if opts.ParentIssue != "" {
+		if id, err := strconv.Atoi(opts.ParentIssue); err == nil {
+			payload.ParentIssueID = id
+		} else {
+			parent, err := c.GetIssue(ctx, opts.ParentIssue)
+			if err != nil {
+				return fmt.Errorf("親課題の取得に失敗: %w", err)
+			}
+			payload.ParentIssueID = parent.ID
 		}
 	}
-	if foundIssueTypeID == 0 && len(issueTypes) > 0 {
-		foundIssueTypeID = issueTypes[0].ID // 見つからなければ最初のものをデフォルトとする
+
+	return nil
+}
+
+// resolveAssignee は、ユーザー ID、ログイン名または表示名で指定された担当者を、プロジェクトのユーザーの ID に変換します。
+func (c *BacklogNotifier) resolveAssignee(ctx context.Context, projectID int, assignee string) (int, error) {
+	users, err := c.ListProjectUsers(ctx, projectID)
+	if err != nil {
+		return 0, err
 	}
-	if foundIssueTypeID == 0 {
-		return 0, 0, fmt.Errorf("プロジェクトの課題種別が見つかりませんでした (ProjectID: %d)", projectID)
+	choices := make([]backlogChoice, 0, len(users))
+	for _, u := range users {
+		choices = append(choices, backlogChoice{ID: u.ID, Name: u.Name, Alias: u.UserID})
 	}
-	issueTypeID = foundIssueTypeID // 採用
+	return resolveBacklogChoice("担当者", assignee, choices)
+}
 
-	// 2. 優先度 (Priorities) の取得
-	// エンドポイント: /priorities (優先度はプロジェクト共通だが、念のため取得)
-	priorityURL := fmt.Sprintf("%s/priorities?apiKey=%s", c.baseURL, c.apiKey)
-	priorityData, fetchErr := c.client.FetchBytes(priorityURL, ctx)
-	if fetchErr != nil {
-		return 0, 0, fmt.Errorf("優先度リストの取得に失敗: %w", fetchErr)
+// resolveBacklogChoices は、複数の値をそれぞれ resolveBacklogChoice で ID に変換します。
+func resolveBacklogChoices(kind string, values []string, choices []backlogChoice) ([]int, error) {
+	var ids []int
+	for _, v := range values {
+		id, err := resolveBacklogChoice(kind, v, choices)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
+	return ids, nil
+}
+
+// ListProjects は、APIキーのユーザーが参加しているプロジェクトの一覧を取得します。
+func (c *BacklogNotifier) ListProjects(ctx context.Context) ([]BacklogProjectResponse, error) {
+	var projects []BacklogProjectResponse
+	if err := c.getCachedJSON(ctx, "/projects", &projects); err != nil {
+		return nil, fmt.Errorf("プロジェクト一覧の取得に失敗: %w", err)
+	}
+	return projects, nil
+}
 
+// ListIssueTypes は、指定されたプロジェクトの課題種別の一覧を取得します。
+func (c *BacklogNotifier) ListIssueTypes(ctx context.Context, projectID int) ([]BacklogIssueTypeResponse, error) {
+	var issueTypes []BacklogIssueTypeResponse
+	if err := c.getCachedJSON(ctx, fmt.Sprintf("/projects/%d/issueTypes", projectID), &issueTypes); err != nil {
+		return nil, fmt.Errorf("課題種別リストの取得に失敗 (ProjectID: %d): %w", projectID, err)
+	}
+	return issueTypes, nil
+}
+
+// ListPriorities は、優先度の一覧を取得します (優先度はスペース共通です)。
+func (c *BacklogNotifier) ListPriorities(ctx context.Context) ([]BacklogPriorityResponse, error) {
 	var priorities []BacklogPriorityResponse
-	if err := json.Unmarshal(priorityData, &priorities); err != nil {
-		return 0, 0, fmt.Errorf("優先度リストのパースに失敗しました: %w", err)
+	if err := c.getCachedJSON(ctx, "/priorities", &priorities); err != nil {
+		return nil, fmt.Errorf("優先度リストの取得に失敗: %w", err)
 	}
+	return priorities, nil
+}
 
-	// 💡 修正ロジック: "中" を優先して探す
-	foundPriorityID := 0
-	for _, p := range priorities {
-		if p.Name == "中" { // あるいは設定可能なデフォルト値
-			foundPriorityID = p.ID
-			break
+// ListProjectUsers は、指定されたプロジェクトに参加しているユーザーの一覧を取得します。
+func (c *BacklogNotifier) ListProjectUsers(ctx context.Context, projectID int) ([]BacklogUserResponse, error) {
+	var users []BacklogUserResponse
+	if err := c.getCachedJSON(ctx, fmt.Sprintf("/projects/%d/users", projectID), &users); err != nil {
+		return nil, fmt.Errorf("プロジェクトのユーザー一覧の取得に失敗 (ProjectID: %d): %w", projectID, err)
+	}
+	return users, nil
+}
+
+// ListCategories は、指定されたプロジェクトのカテゴリーの一覧を取得します。
+func (c *BacklogNotifier) ListCategories(ctx context.Context, projectID int) ([]BacklogCategoryResponse, error) {
+	var categories []BacklogCategoryResponse
+	if err := c.getCachedJSON(ctx, fmt.Sprintf("/projects/%d/categories", projectID), &categories); err != nil {
+		return nil, fmt.Errorf("カテゴリー一覧の取得に失敗 (ProjectID: %d): %w", projectID, err)
+	}
+	return categories, nil
+}
+
+// ListVersions は、指定されたプロジェクトのバージョン (マイルストーン) の一覧を取得します。
+// Backlog ではマイルストーンと発生バージョンは同じ一覧から選択します。
+func (c *BacklogNotifier) ListVersions(ctx context.Context, projectID int) ([]BacklogVersionResponse, error) {
+	var versions []BacklogVersionResponse
+	if err := c.getCachedJSON(ctx, fmt.Sprintf("/projects/%d/versions", projectID), &versions); err != nil {
+		return nil, fmt.Errorf("バージョン (マイルストーン) 一覧の取得に失敗 (ProjectID: %d): %w", projectID, err)
+	}
+	return versions, nil
+}
+
+// GetIssue は、課題キー (例: PROJECT-123) または課題IDで課題を取得します。
+func (c *BacklogNotifier) GetIssue(ctx context.Context, issueIDOrKey string) (*BacklogIssueResponse, error) {
+	if issueIDOrKey == "" {
+		return nil, errors.New("課題キーまたは課題IDは空にできません")
+	}
+
+	var issue BacklogIssueResponse
+	if err := c.getJSON(ctx, "/issues/"+url.PathEscape(issueIDOrKey), nil, &issue); err != nil {
+		return nil, fmt.Errorf("課題 %s の取得に失敗: %w", issueIDOrKey, err)
+	}
+	return &issue, nil
+}
+
+// UpdateIssue は、課題キーまたは課題IDで指定された課題を更新し、更新後の課題を返します。
+// 状態・完了理由・担当者は名前でも指定でき、課題のプロジェクトの設定から ID に変換されます。
+// 件名は切り詰めずに更新するため、絵文字の変換後に上限を超える場合はエラーを返します。
+func (c *BacklogNotifier) UpdateIssue(ctx context.Context, issueIDOrKey string, update BacklogIssueUpdate) (*BacklogIssueResponse, error) {
+	if issueIDOrKey == "" {
+		return nil, errors.New("課題キーまたは課題IDは空にできません")
+	}
+
+	form := url.Values{}
+	if update.Summary != "" {
+		summary := c.sanitizeSummary(update.Summary)
+		if n := TextLength(summary); n > BacklogSummaryLimit {
+			return nil, fmt.Errorf("課題の件名が上限 (%d 文字) を超えています (%d 文字)", BacklogSummaryLimit, n)
 		}
+		form.Set("summary", summary)
 	}
-	if foundPriorityID == 0 && len(priorities) > 0 {
-		foundPriorityID = priorities[0].ID // 見つからなければ最初のものをデフォルトとする
+	if update.Description != "" {
+		form.Set("description", c.sanitizeText(update.Description))
 	}
-	if foundPriorityID == 0 {
-		return 0, 0, fmt.Errorf("優先度が見つかりませんでした")
+	if update.Comment != "" {
+		form.Set("comment", c.sanitizeText(update.Comment))
 	}
-	priorityID = foundPriorityID // 採用
 
-	return issueTypeID, priorityID, nil
+	// 状態・担当者・カスタム属性の照合には、課題のプロジェクトと課題種別が必要
+	var current *BacklogIssueResponse
+	currentIssue := func() (*BacklogIssueResponse, error) {
+		if current != nil {
+			return current, nil
+		}
+		var err error
+		current, err = c.GetIssue(ctx, issueIDOrKey)
+		return current, err
+	}
+
+	if update.Status != "" {
+		issue, err := currentIssue()
+		if err != nil {
+			return nil, err
+		}
+		statusID, err := c.resolveStatus(ctx, issue.ProjectID, update.Status)
+		if err != nil {
+			return nil, err
+		}
+		form.Set("statusId", strconv.Itoa(statusID))
+	}
+	if update.Resolution != "" {
+		resolutionID, err := c.resolveResolution(ctx, update.Resolution)
+		if err != nil {
+			return nil, err
+		}
+		form.Set("resolutionId", strconv.Itoa(resolutionID))
+	}
+	if update.Assignee != "" {
+		issue, err := currentIssue()
+		if err != nil {
+			return nil, err
+		}
+		assigneeID, err := c.resolveAssignee(ctx, issue.ProjectID, update.Assignee)
+		if err != nil {
+			return nil, err
+		}
+		form.Set("assigneeId", strconv.Itoa(assigneeID))
+	}
+	if update.CustomFields.Len() > 0 {
+		issue, err := currentIssue()
+		if err != nil {
+			return nil, err
+		}
+		issueTypeID := 0
+		if issue.IssueType != nil {
+			issueTypeID = issue.IssueType.ID
+		}
+		customFields, err := c.resolveCustomFields(ctx, issue.ProjectID, issueTypeID, update.CustomFields, false)
+		if err != nil {
+			return nil, fmt.Errorf("カスタム属性の設定に失敗: %w", err)
+		}
+		for k, v := range customFields {
+			form[k] = v
+		}
+	}
+	if len(form) == 0 && len(update.Attachments) == 0 {
+		return nil, errors.New("課題の更新内容が指定されていません")
+	}
+
+	// 添付ファイルは、更新内容の検証がすべて済んでから送信する
+	attachmentIDs, err := c.uploadAttachments(ctx, update.Attachments)
+	if err != nil {
+		return nil, err
+	}
+	addAttachmentIDs(form, attachmentIDs)
+
+	respBody, err := c.sendForm(ctx, http.MethodPatch, "/issues/"+url.PathEscape(issueIDOrKey), form)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update Backlog issue %s: %w", issueIDOrKey, err)
+	}
+
+	var issue BacklogIssueResponse
+	if err := json.Unmarshal(respBody, &issue); err != nil {
+		return nil, fmt.Errorf("課題の更新結果のパースに失敗しました: %w", err)
+	}
+	return &issue, nil
+}
+
+// sanitizeText は、EmojiPolicy に従って、課題の詳細やコメントのテキストに含まれる絵文字を変換します。
+func (c *BacklogNotifier) sanitizeText(s string) string {
+	return ApplyEmojiPolicy(s, c.EmojiPolicy, c.EmojiASCII)
+}
+
+// sanitizeSummary は、絵文字を変換したうえで、課題のサマリー (1行) の連続する空白をまとめます。
+func (c *BacklogNotifier) sanitizeSummary(s string) string {
+	return text.NormalizeSpaces(c.sanitizeText(s))
+}
+
+// IssueURL は、課題キーからブラウザで開くための課題のURLを生成します。
+func (c *BacklogNotifier) IssueURL(issueKey string) string {
+	return strings.TrimSuffix(c.baseURL, "/api/v2") + "/view/" + issueKey
 }
 
 // SendIssue は、Backlogに新しい課題を登録します。
-// func (c *BacklogNotifier) SendIssue(ctx context.Context, summary, description string, projectID, issueTypeID, priorityID int) error {
-func (c *BacklogNotifier) SendIssue(ctx context.Context, summary, description string, projectID int) error {
+// opts で課題種別と優先度を ID または名前で指定できます (省略時は BacklogNotifier の設定、またはプロジェクトの既定値)。
+// 登録された課題の ID・課題キー・URL を返します。
+// 絵文字の変換後にサマリーが上限を超える場合は切り詰め、結果の Truncations に記録します。
+func (c *BacklogNotifier) SendIssue(ctx context.Context, summary, description string, projectID int, opts ...BacklogIssueOptions) (*BacklogIssueResult, error) {
+	var opt BacklogIssueOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
 
-	// 1. 絵文字のサニタイズ
-	sanitizedSummary := text.CleanStringFromEmojis(summary)
-	sanitizedDescription := text.CleanStringFromEmojis(description)
+	// 1. 絵文字のサニタイズ (EmojiPolicy に従って変換)
+	var rec truncationRecorder
+	sanitizedSummary := rec.truncate("summary", c.sanitizeSummary(summary), BacklogSummaryLimit)
+	sanitizedDescription := c.sanitizeText(description)
 
 	// 有効な ID を取得
-	validIssueTypeID, validPriorityID, err := c.getFirstIssueAttributes(ctx, projectID)
+	validIssueTypeID, validPriorityID, err := c.resolveIssueAttributes(ctx, projectID, opt)
 	if err != nil {
-		return fmt.Errorf("プロジェクトの有効な課題属性の取得に失敗: %w", err)
+		return nil, fmt.Errorf("プロジェクトの有効な課題属性の取得に失敗: %w", err)
 	}
 
 	// 2. ペイロードの構築
@@ -194,125 +732,260 @@ func (c *BacklogNotifier) SendIssue(ctx context.Context, summary, description st
 		IssueTypeID: validIssueTypeID,
 		PriorityID:  validPriorityID,
 	}
+	if err := c.resolveIssueFields(ctx, projectID, opt, &issueData); err != nil {
+		return nil, fmt.Errorf("課題の項目の設定に失敗: %w", err)
+	}
+	if issueData.CustomFields, err = c.resolveCustomFields(ctx, projectID, validIssueTypeID, opt.CustomFields, true); err != nil {
+		return nil, fmt.Errorf("カスタム属性の設定に失敗: %w", err)
+	}
+	// 添付ファイルは、項目の検証がすべて済んでから送信する
+	if issueData.AttachmentIDs, err = c.uploadAttachments(ctx, opt.Attachments); err != nil {
+		return nil, err
+	}
+
+	// 3. APIリクエストの実行 (フォーム形式)
+	respBody, err := c.sendForm(ctx, http.MethodPost, "/issues", issueData.Values())
+	if err != nil {
+		// エラーを呼び出し元に返す
+		return nil, fmt.Errorf("failed to create issue in Backlog: %w", err)
+	}
+
+	// 4. 登録された課題の情報を返す
+	var issue BacklogIssueResponse
+	if err := json.Unmarshal(respBody, &issue); err != nil {
+		return nil, fmt.Errorf("課題の登録結果のパースに失敗しました: %w", err)
+	}
+	return &BacklogIssueResult{
+		ID:          issue.ID,
+		ProjectID:   issue.ProjectID,
+		IssueKey:    issue.IssueKey,
+		URL:         c.IssueURL(issue.IssueKey),
+		Created:     issue.Created,
+		Truncations: rec.truncations,
+	}, nil
+}
 
-	jsonBody, err := json.Marshal(issueData)
+// Send は、Message を ProjectKey のプロジェクトへの課題として登録します。
+// Title が課題のサマリーに、Body と Fields / Links / Tags が課題の詳細になります。
+// 絵文字の変換後にサマリーが Backlog の上限を超える場合は切り詰め、SendResult の Truncations に記録します。
+// Deduplicate が有効な場合は、同じサマリーの未完了の課題があれば新しい課題を登録せずにコメントを追加します。
+// Attachments は課題 (コメントを追加した場合はコメント) に添付されます。
+func (c *BacklogNotifier) Send(ctx context.Context, msg Message) (*SendResult, error) {
+	if c.ProjectKey == "" {
+		return nil, errors.New("BacklogNotifier: Send には ProjectKey の設定が必要です")
+	}
+	if msg.Title == "" {
+		return nil, errors.New("BacklogNotifier: 課題のサマリーとなる Title が空です")
+	}
+
+	projectID, err := c.GetProjectID(ctx, c.ProjectKey)
 	if err != nil {
-		return fmt.Errorf("failed to marshal issue data: %w", err)
+		return nil, err
+	}
+
+	issueOpts := BacklogIssueOptions{Attachments: backlogAttachments(msg.Attachments)}
+	if c.Deduplicate {
+		ensured, err := c.EnsureIssue(ctx, msg.Title, backlogDescription(msg), projectID, BacklogEnsureOptions{Reopen: c.Reopen, Issue: issueOpts})
+		if err != nil {
+			return nil, err
+		}
+		return &SendResult{Backend: "backlog", ID: ensured.Issue.IssueKey, URL: ensured.Issue.URL, Truncations: ensured.Issue.Truncations}, nil
 	}
 
-	// 3. APIリクエストの実行
-	err = c.postRequest(ctx, "/issues", jsonBody)
+	issue, err := c.SendIssue(ctx, msg.Title, backlogDescription(msg), projectID, issueOpts)
 	if err != nil {
-		// エラーを呼び出し元に返す
-		return fmt.Errorf("failed to create issue in Backlog: %w", err)
+		return nil, err
 	}
 
-	fmt.Printf("✅ Backlog issue successfully created (ProjectID: %d).\n", projectID)
-	return nil
+	return &SendResult{Backend: "backlog", ID: issue.IssueKey, URL: issue.URL, Truncations: issue.Truncations}, nil
+}
+
+// backlogDescription は、Message の本文と付加情報を課題の詳細テキストに変換します。
+func backlogDescription(msg Message) string {
+	var sb strings.Builder
+	sb.WriteString(msg.Body)
+
+	var meta []string
+	if msg.Severity != "" {
+		meta = append(meta, fmt.Sprintf("重要度: %s", msg.Severity))
+	}
+	for _, f := range msg.Fields {
+		meta = append(meta, fmt.Sprintf("%s: %s", f.Name, f.Value))
+	}
+	for _, l := range msg.Links {
+		if l.Text != "" {
+			meta = append(meta, fmt.Sprintf("%s: %s", l.Text, l.URL))
+		} else {
+			meta = append(meta, l.URL)
+		}
+	}
+	if len(msg.Tags) > 0 {
+		meta = append(meta, fmt.Sprintf("タグ: %s", strings.Join(msg.Tags, ", ")))
+	}
+	for _, k := range sortedLabelKeys(msg.Labels) {
+		meta = append(meta, fmt.Sprintf("%s: %s", k, msg.Labels[k]))
+	}
+
+	if len(meta) > 0 {
+		if sb.Len() > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(strings.Join(meta, "\n"))
+	}
+
+	return sb.String()
 }
 
 // SendText は Backlog では課題登録を推奨するため、エラーを返します。
-// Notifier インターフェース (ヘッダーなし) を満たすための実装です。
 func (c *BacklogNotifier) SendText(ctx context.Context, message string) error {
-	return errors.New("BacklogNotifier: Plain text notification is not supported; use SendIssue or PostComment")
+	return errors.New("BacklogNotifier: Plain text notification is not supported; use Send, SendIssue or PostComment")
 }
 
 // SendTextWithHeader は Backlog では課題登録を推奨するため、エラーを返します。
-// Notifier インターフェース (ヘッダーあり) を満たすための実装です。
 func (c *BacklogNotifier) SendTextWithHeader(ctx context.Context, headerText string, message string) error {
-	return errors.New("BacklogNotifier: Plain text notification is not supported; use SendIssue or PostComment")
+	return errors.New("BacklogNotifier: Plain text notification is not supported; use Send, SendIssue or PostComment")
 }
 
 // --- コメント投稿機能の追加 ---
 
-// PostComment は指定された課題IDにコメントを投稿します。
-func (c *BacklogNotifier) PostComment(ctx context.Context, issueID string, content string) error {
+// BacklogCommentOptions はコメント投稿時の任意の指定です。
+type BacklogCommentOptions struct {
+	// Attachments: コメントに添付するファイル
+	Attachments []BacklogAttachment
+	// NotifiedUsers: お知らせを送るユーザーの ID、ログイン名または表示名
+	NotifiedUsers []string
+	// IgnoreMentions: true の場合、本文中の @ログイン名 をお知らせ先に加えない
+	IgnoreMentions bool
+}
+
+// PostComment は指定された課題IDにコメントを投稿し、投稿されたコメントの ID と URL を返します。
+// opts で添付ファイルとお知らせ先のユーザーを指定できます。本文中の @ログイン名 も、課題のプロジェクトのユーザーであればお知らせ先になります。
+func (c *BacklogNotifier) PostComment(ctx context.Context, issueID string, content string, opts ...BacklogCommentOptions) (*BacklogCommentResult, error) {
 	if issueID == "" {
-		return errors.New("issueID cannot be empty for posting a comment")
+		return nil, errors.New("issueID cannot be empty for posting a comment")
+	}
+	var opt BacklogCommentOptions
+	if len(opts) > 0 {
+		opt = opts[0]
 	}
 
-	// 1. 絵文字のサニタイズ (Backlogの制限対策)
-	sanitizedContent := text.CleanStringFromEmojis(content)
+	// 課題IDが指定された場合は、結果の課題キーと URL のために課題キーを取得する
+	issueKey := issueID
+	if _, err := strconv.Atoi(issueID); err == nil {
+		issue, err := c.GetIssue(ctx, issueID)
+		if err != nil {
+			return nil, err
+		}
+		issueKey = issue.IssueKey
+	}
+
+	// 1. 絵文字のサニタイズ (Backlogの制限対策。EmojiPolicy に従って変換)
+	sanitizedContent := c.sanitizeText(content)
 
 	// 2. ペイロードの構築
-	commentData := map[string]string{
-		"content": sanitizedContent,
+	commentData := url.Values{}
+	commentData.Set("content", sanitizedContent)
+	notifiedUserIDs, err := c.resolveNotifiedUsers(ctx, issueID, opt.NotifiedUsers, content, !opt.IgnoreMentions)
+	if err != nil {
+		return nil, fmt.Errorf("お知らせ先の設定に失敗: %w", err)
+	}
+	for _, id := range notifiedUserIDs {
+		commentData.Add("notifiedUserId[]", strconv.Itoa(id))
+	}
+	// 添付ファイルは、お知らせ先の検証が済んでから送信する
+	attachmentIDs, err := c.uploadAttachments(ctx, opt.Attachments)
+	if err != nil {
+		return nil, err
 	}
+	addAttachmentIDs(commentData, attachmentIDs)
 
-	// 3. APIリクエストの実行
+	// 3. APIリクエストの実行 (フォーム形式)
 	// エンドポイント: /issues/{issueIdOrKey}/comments
-	endpoint := fmt.Sprintf("/issues/%s/comments", issueID)
+	endpoint := fmt.Sprintf("/issues/%s/comments", url.PathEscape(issueID))
 
-	// [修正点] postRequest を使用してコメントを投稿 (内部でDoRequestを使用)
-	jsonBody, err := json.Marshal(commentData)
+	respBody, err := c.sendForm(ctx, http.MethodPost, endpoint, commentData)
 	if err != nil {
-		return fmt.Errorf("failed to marshal comment data: %w", err)
+		return nil, fmt.Errorf("failed to post comment to Backlog issue %s: %w", issueID, err)
 	}
 
-	err = c.postRequest(ctx, endpoint, jsonBody)
+	// 4. 投稿されたコメントの情報を返す
+	var comment BacklogCommentResponse
+	if err := json.Unmarshal(respBody, &comment); err != nil {
+		return nil, fmt.Errorf("コメントの投稿結果のパースに失敗しました: %w", err)
+	}
+	return &BacklogCommentResult{
+		ID:       comment.ID,
+		IssueKey: issueKey,
+		URL:      fmt.Sprintf("%s#comment-%d", c.IssueURL(issueKey), comment.ID),
+		Created:  comment.Created,
+	}, nil
+}
+
+// getJSON は、指定されたエンドポイントへ GET リクエストを送信し、レスポンスの JSON を v にデコードします。
+func (c *BacklogNotifier) getJSON(ctx context.Context, endpoint string, query url.Values, v any) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("apiKey", c.apiKey)
+	fullURL := fmt.Sprintf("%s%s?%s", c.baseURL, endpoint, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
 	if err != nil {
-		return fmt.Errorf("failed to post comment to Backlog issue %s: %w", issueID, err)
+		return fmt.Errorf("failed to create GET request for Backlog: %w", err)
 	}
 
-	fmt.Printf("✅ Backlog issue %s successfully commented.\n", issueID)
+	respBodyBytes, err := c.client.DoRequest(req)
+	if err != nil {
+		return c.handleAPIError(err)
+	}
+
+	if err := json.Unmarshal(respBodyBytes, v); err != nil {
+		return fmt.Errorf("Backlog APIのレスポンスのパースに失敗しました: %w", err)
+	}
 	return nil
 }
 
-// postRequest は、指定されたエンドポイントへリクエストを送信する内部ヘルパーメソッドです。
-func (c *BacklogNotifier) postRequest(ctx context.Context, endpoint string, jsonBody []byte) error {
-	fullURL := fmt.Sprintf("%s%s?apiKey=%s", c.baseURL, endpoint, c.apiKey)
+// sendForm は、指定されたエンドポイントへフォーム形式 (application/x-www-form-urlencoded) のリクエストを送信し、
+// レスポンスのボディを返します。
+func (c *BacklogNotifier) sendForm(ctx context.Context, method, endpoint string, form url.Values) ([]byte, error) {
+	fullURL := fmt.Sprintf("%s%s?apiKey=%s", c.baseURL, endpoint, url.QueryEscape(c.apiKey))
 
-	// 1. リクエストの構築
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, strings.NewReader(form.Encode()))
 	if err != nil {
-		return fmt.Errorf("failed to create POST request for Backlog: %w", err)
+		return nil, fmt.Errorf("failed to create %s request for Backlog: %w", method, err)
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	// 2. ヘッダー設定
-	req.Header.Set("Content-Type", "application/json")
-	// Backlog APIキーはクエリパラメータで渡すため、ヘッダーの X-Backlog-Api-Key は不要
-
-	// 3. リトライ付きでの実行 (httpkit.DoRequestを使用)
 	respBodyBytes, err := c.client.DoRequest(req)
 	if err != nil {
-		// リトライ後の最終エラーをBacklog固有のエラーに変換
-		return c.handleAPIError(err, respBodyBytes)
+		return nil, c.handleAPIError(err)
 	}
-
-	// 成功（2xx）の場合
-	return nil
+	return respBodyBytes, nil
 }
 
-// handleAPIError は、httpkit.DoRequest から返されたエラーとボディをBacklog固有のエラーに変換します。
-func (c *BacklogNotifier) handleAPIError(err error, respBody []byte) error {
-	// 1. リトライ/ネットワークエラーの場合
-	if !httpkit.IsNonRetryableError(err) {
+// handleAPIError は、httpkit.DoRequest から返されたエラーをBacklog固有のエラーに変換します。
+// 4xx の場合 DoRequest はボディを返さないため、エラーのボディは NonRetryableHTTPError から取得します。
+func (c *BacklogNotifier) handleAPIError(err error) error {
+	var nonRetryable *httpkit.NonRetryableHTTPError
+	if !errors.As(err, &nonRetryable) {
 		// 5xx またはネットワークエラー
 		return err
 	}
 
-	// 2. NonRetryableHTTPError (4xx) の場合、ステータスコードを取得
-	var nonRetryable *httpkit.NonRetryableHTTPError
-	if errors.As(err, &nonRetryable) {
-		var errorResp BacklogErrorResponse
-
-		// Backlog エラー構造体をパース
-		if json.Unmarshal(respBody, &errorResp) == nil && len(errorResp.Errors) > 0 {
-			firstError := errorResp.Errors[0]
-			return &BacklogError{
-				StatusCode: nonRetryable.StatusCode,
-				Code:       firstError.Code,
-				Message:    firstError.Message,
-			}
-		}
-
-		// パースできなかった場合、生のボディをメッセージにする
+	// Backlog エラー構造体をパース
+	var errorResp BacklogErrorResponse
+	if json.Unmarshal(nonRetryable.Body, &errorResp) == nil && len(errorResp.Errors) > 0 {
+		firstError := errorResp.Errors[0]
 		return &BacklogError{
 			StatusCode: nonRetryable.StatusCode,
-			Message:    fmt.Sprintf("Raw Response: %s", string(respBody)),
+			Code:       firstError.Code,
+			Message:    firstError.Message,
 		}
 	}
 
-	// その他の予期せぬエラー
-	return err
+	// パースできなかった場合、生のボディをメッセージにする
+	return &BacklogError{
+		StatusCode: nonRetryable.StatusCode,
+		Message:    fmt.Sprintf("Raw Response: %s", string(nonRetryable.Body)),
+	}
 }
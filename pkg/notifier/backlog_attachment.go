@@ -0,0 +1,151 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// DefaultBacklogAttachmentLimit は、添付ファイル1つあたりの既定のサイズの上限 (バイト) です。
+const DefaultBacklogAttachmentLimit int64 = 100 << 20
+
+// BacklogAttachment は、課題やコメントに添付するファイルです。
+type BacklogAttachment struct {
+	// Name: 添付ファイルのファイル名 (Backlog 上で表示される名前)
+	Name string
+	// Reader: 添付ファイルの内容
+	Reader io.Reader
+}
+
+// backlogAttachments は、Message の添付ファイルを課題に添付するファイルに変換します。
+func backlogAttachments(attachments []Attachment) []BacklogAttachment {
+	if len(attachments) == 0 {
+		return nil
+	}
+	atts := make([]BacklogAttachment, 0, len(attachments))
+	for _, a := range attachments {
+		atts = append(atts, BacklogAttachment{Name: a.Filename, Reader: a.Content})
+	}
+	return atts
+}
+
+// BacklogAttachmentResponse は添付ファイルの送信APIのレスポンスです。
+type BacklogAttachmentResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// attachmentLimit は、添付ファイル1つあたりのサイズの上限を返します。
+func (c *BacklogNotifier) attachmentLimit() int64 {
+	if c.MaxAttachmentSize > 0 {
+		return c.MaxAttachmentSize
+	}
+	return DefaultBacklogAttachmentLimit
+}
+
+// readAttachment は、添付ファイルの内容を読み込み、サイズが上限以内であることを検証します。
+// 上限を超えるファイルは、上限を 1 バイト超えた時点で読み込みを打ち切ります。
+func readAttachment(att BacklogAttachment, limit int64) ([]byte, error) {
+	if att.Name == "" {
+		return nil, errors.New("添付ファイルのファイル名が指定されていません")
+	}
+	if att.Reader == nil {
+		return nil, fmt.Errorf("添付ファイル %s の内容が指定されていません", att.Name)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(att.Reader, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("添付ファイル %s の読み込みに失敗: %w", att.Name, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("添付ファイル %s のサイズが上限 (%d バイト) を超えています", att.Name, limit)
+	}
+	return data, nil
+}
+
+// UploadAttachment は、ファイルを Backlog に送信し、課題やコメントに添付するための添付ファイル ID を取得します。
+// サイズが MaxAttachmentSize (既定は DefaultBacklogAttachmentLimit) を超える場合は、送信せずにエラーを返します。
+func (c *BacklogNotifier) UploadAttachment(ctx context.Context, att BacklogAttachment) (*BacklogAttachmentResponse, error) {
+	data, err := readAttachment(att, c.attachmentLimit())
+	if err != nil {
+		return nil, err
+	}
+	return c.uploadAttachment(ctx, att.Name, data)
+}
+
+// uploadAttachment は、読み込み済みのファイルを /space/attachment へマルチパート形式で送信します。
+func (c *BacklogNotifier) uploadAttachment(ctx context.Context, name string, data []byte) (*BacklogAttachmentResponse, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", name)
+	if err != nil {
+		return nil, fmt.Errorf("添付ファイル %s のリクエストの作成に失敗: %w", name, err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, fmt.Errorf("添付ファイル %s のリクエストの作成に失敗: %w", name, err)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("添付ファイル %s のリクエストの作成に失敗: %w", name, err)
+	}
+
+	fullURL := fmt.Sprintf("%s/space/attachment?apiKey=%s", c.baseURL, url.QueryEscape(c.apiKey))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create POST request for Backlog: %w", err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	respBody, err := c.client.DoRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("添付ファイル %s の送信に失敗: %w", name, c.handleAPIError(err))
+	}
+
+	var attachment BacklogAttachmentResponse
+	if err := json.Unmarshal(respBody, &attachment); err != nil {
+		return nil, fmt.Errorf("添付ファイルの送信結果のパースに失敗しました: %w", err)
+	}
+	return &attachment, nil
+}
+
+// uploadAttachments は、すべての添付ファイルのサイズを検証してから順に送信し、添付ファイル ID を返します。
+// 一部のファイルだけが送信されることのないよう、送信前にすべてのファイルを読み込んで検証します。
+func (c *BacklogNotifier) uploadAttachments(ctx context.Context, atts []BacklogAttachment) ([]int, error) {
+	if len(atts) == 0 {
+		return nil, nil
+	}
+
+	limit := c.attachmentLimit()
+	contents := make([][]byte, len(atts))
+	for i, att := range atts {
+		data, err := readAttachment(att, limit)
+		if err != nil {
+			return nil, err
+		}
+		contents[i] = data
+	}
+
+	ids := make([]int, 0, len(atts))
+	for i, att := range atts {
+		attachment, err := c.uploadAttachment(ctx, att.Name, contents[i])
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, attachment.ID)
+	}
+	return ids, nil
+}
+
+// addAttachmentIDs は、添付ファイル ID をフォームパラメータ (attachmentId[]) に追加します。
+func addAttachmentIDs(form url.Values, ids []int) {
+	for _, id := range ids {
+		form.Add("attachmentId[]", strconv.Itoa(id))
+	}
+}
@@ -0,0 +1,197 @@
+package notifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultBacklogCacheTTL は、Backlog のメタデータのキャッシュの既定の有効期間です。
+const DefaultBacklogCacheTTL = 10 * time.Minute
+
+// BacklogMetadataCache は、Backlog のメタデータ (プロジェクト・課題種別・優先度・ユーザー・カテゴリー・状態など) の
+// API レスポンスを有効期間 (TTL) 付きで保持するキャッシュです。複数のゴルーチンから安全に使用できます。
+// ファイルのパスを指定した場合は、CLI の実行をまたいで再利用できるよう、内容をファイルにも保存します。
+type BacklogMetadataCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	path    string // 空の場合はメモリ上のみ
+	entries map[string]backlogCacheEntry
+}
+
+// backlogCacheEntry は、キャッシュされた API レスポンス1件です。
+type backlogCacheEntry struct {
+	Data    json.RawMessage `json:"data"`
+	Expires time.Time       `json:"expires"`
+}
+
+// NewBacklogMetadataCache は、メモリ上のみで保持するキャッシュを作成します。
+func NewBacklogMetadataCache(ttl time.Duration) *BacklogMetadataCache {
+	return &BacklogMetadataCache{ttl: ttl, entries: map[string]backlogCacheEntry{}}
+}
+
+// NewBacklogFileCache は、path のファイルに保存されるキャッシュを作成し、保存済みの内容を読み込みます。
+// ファイルが存在しない、または読み込めない形式の場合は、空のキャッシュで開始します。
+func NewBacklogFileCache(path string, ttl time.Duration) (*BacklogMetadataCache, error) {
+	c := NewBacklogMetadataCache(ttl)
+	c.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("キャッシュファイル %s の読み込みに失敗: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil || c.entries == nil {
+		// 壊れたキャッシュは破棄して取得し直す
+		c.entries = map[string]backlogCacheEntry{}
+	}
+	return c, nil
+}
+
+// get は、有効期間内のキャッシュされたレスポンスを返します。
+func (c *BacklogMetadataCache) get(key string) (json.RawMessage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.Expires) {
+		return nil, false
+	}
+	return e.Data, true
+}
+
+// put は、レスポンスをキャッシュに保存します。ファイルに保存するキャッシュの場合は、ファイルも更新します。
+func (c *BacklogMetadataCache) put(key string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.Expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = backlogCacheEntry{Data: data, Expires: now.Add(c.ttl)}
+	return c.save()
+}
+
+// Invalidate は、キャッシュの内容をすべて破棄します。ファイルに保存するキャッシュの場合は、ファイルも削除します。
+// プロジェクトの課題種別やユーザーなどを変更した直後に、最新の内容を取得し直すために使用します。
+func (c *BacklogMetadataCache) Invalidate() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]backlogCacheEntry{}
+	if c.path == "" {
+		return nil
+	}
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("キャッシュファイル %s の削除に失敗: %w", c.path, err)
+	}
+	return nil
+}
+
+// save は、キャッシュの内容をファイルに書き出します (呼び出し側で mu をロックしていること)。
+// 書き込み途中のファイルを他のプロセスが読まないよう、一時ファイルに書き出してから置き換えます。
+func (c *BacklogMetadataCache) save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return fmt.Errorf("キャッシュのエンコードに失敗: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return fmt.Errorf("キャッシュディレクトリの作成に失敗: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("キャッシュファイルの作成に失敗: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("キャッシュファイルの書き込みに失敗: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("キャッシュファイルの書き込みに失敗: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("キャッシュファイルの置き換えに失敗: %w", err)
+	}
+	return nil
+}
+
+// backlogCachePath は、ユーザーのキャッシュディレクトリ配下の、スペースと API キーの組ごとのキャッシュファイルのパスを返します。
+// API キーごとに参照できるプロジェクトが異なるため API キーもファイル名に含めますが、ハッシュ化するためキーそのものは保存されません。
+func backlogCachePath(baseURL, apiKey string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("ユーザーのキャッシュディレクトリを取得できません: %w", err)
+	}
+	sum := sha256.Sum256([]byte(baseURL + "\n" + apiKey))
+	return filepath.Join(dir, "go-notifier", "backlog-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// UseFileCache は、メタデータのキャッシュを、ユーザーのキャッシュディレクトリ (os.UserCacheDir) 配下のファイルに保存するキャッシュに切り替えます。
+// CLI のように実行ごとにプロセスが終了する場合でも、有効期間内であればメタデータを取得し直さずに済みます。
+func (c *BacklogNotifier) UseFileCache(ttl time.Duration) error {
+	path, err := backlogCachePath(c.baseURL, c.apiKey)
+	if err != nil {
+		return err
+	}
+	cache, err := NewBacklogFileCache(path, ttl)
+	if err != nil {
+		return err
+	}
+	c.Cache = cache
+	return nil
+}
+
+// InvalidateCache は、メタデータのキャッシュを破棄します。キャッシュが無効の場合は何もしません。
+func (c *BacklogNotifier) InvalidateCache() error {
+	if c.Cache == nil {
+		return nil
+	}
+	return c.Cache.Invalidate()
+}
+
+// getCachedJSON は、メタデータを取得する GET リクエストを、キャッシュを経由して実行します。
+// キャッシュが無効 (Cache が nil) の場合は、常に API から取得します。
+func (c *BacklogNotifier) getCachedJSON(ctx context.Context, endpoint string, v any) error {
+	if c.Cache == nil {
+		return c.getJSON(ctx, endpoint, nil, v)
+	}
+
+	key := c.baseURL + endpoint
+	if data, ok := c.Cache.get(key); ok {
+		if err := json.Unmarshal(data, v); err == nil {
+			return nil
+		}
+		// 形式の変わった古いキャッシュは無視して取得し直す
+	}
+
+	var raw json.RawMessage
+	if err := c.getJSON(ctx, endpoint, nil, &raw); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("Backlog APIのレスポンスのパースに失敗しました: %w", err)
+	}
+	// キャッシュの保存に失敗しても、取得した結果はそのまま使用する
+	_ = c.Cache.put(key, raw)
+	return nil
+}
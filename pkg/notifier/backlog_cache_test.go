@@ -0,0 +1,143 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// expire は、キャッシュのすべてのエントリーの有効期間を過ぎた状態にします。
+func (c *BacklogMetadataCache) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.entries {
+		e.Expires = time.Now().Add(-time.Second)
+		c.entries[k] = e
+	}
+}
+
+func TestBacklogCacheTTL(t *testing.T) {
+	var requests atomic.Int32
+	bn := newTestBacklogNotifier(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":3,"name":"中"}]`))
+	}))
+	if bn.Cache != nil {
+		t.Fatal("NewBacklogNotifier enabled the cache, want it to be opt-in")
+	}
+	ctx := context.Background()
+
+	// キャッシュが無効の場合は毎回取得する
+	for range 2 {
+		if _, err := bn.ListPriorities(ctx); err != nil {
+			t.Fatalf("ListPriorities: %v", err)
+		}
+	}
+	if n := requests.Load(); n != 2 {
+		t.Fatalf("got %d requests without a cache, want 2", n)
+	}
+
+	bn.Cache = NewBacklogMetadataCache(time.Minute)
+	for range 2 {
+		priorities, err := bn.ListPriorities(ctx)
+		if err != nil {
+			t.Fatalf("ListPriorities: %v", err)
+		}
+		if len(priorities) != 1 || priorities[0].Name != "中" {
+			t.Errorf("priorities = %+v", priorities)
+		}
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("got %d requests, want the second call to be served from the cache", n)
+	}
+
+	// 有効期間を過ぎたら取得し直す
+	bn.Cache.expire()
+	if _, err := bn.ListPriorities(ctx); err != nil {
+		t.Fatalf("ListPriorities: %v", err)
+	}
+	if n := requests.Load(); n != 4 {
+		t.Errorf("got %d requests, want the expired entry to be fetched again", n)
+	}
+}
+
+func TestBacklogFileCache(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "go-notifier", "backlog.json")
+
+	cache, err := NewBacklogFileCache(path, time.Minute)
+	if err != nil {
+		t.Fatalf("NewBacklogFileCache: %v", err)
+	}
+	if err := cache.put("https://example.backlog.jp/api/v2/priorities", []byte(`[{"id":3}]`)); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := cache.put("https://example.backlog.jp/api/v2/projects", []byte(`[]`)); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	// 一時ファイルに書き出してから置き換えるため、一時ファイルは残らない
+	files, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name() != "backlog.json" {
+		t.Errorf("cache directory = %v, want only backlog.json", files)
+	}
+	var saved map[string]backlogCacheEntry
+	if data, err := os.ReadFile(path); err != nil || json.Unmarshal(data, &saved) != nil || len(saved) != 2 {
+		t.Fatalf("cache file = %v (%v), want 2 entries", saved, err)
+	}
+
+	// 別のプロセスで読み込んでも、有効期間内の内容を使用できる
+	reloaded, err := NewBacklogFileCache(path, time.Minute)
+	if err != nil {
+		t.Fatalf("NewBacklogFileCache: %v", err)
+	}
+	if data, ok := reloaded.get("https://example.backlog.jp/api/v2/priorities"); !ok || string(data) != `[{"id":3}]` {
+		t.Errorf("reloaded entry = %s (%v)", data, ok)
+	}
+
+	// 壊れたファイルは破棄して空のキャッシュで開始する
+	if err := os.WriteFile(path, []byte("{broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	broken, err := NewBacklogFileCache(path, time.Minute)
+	if err != nil {
+		t.Fatalf("NewBacklogFileCache with a broken file: %v", err)
+	}
+	if _, ok := broken.get("https://example.backlog.jp/api/v2/priorities"); ok {
+		t.Error("got an entry from a broken cache file")
+	}
+}
+
+func TestBacklogCacheInvalidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backlog.json")
+	cache, err := NewBacklogFileCache(path, time.Minute)
+	if err != nil {
+		t.Fatalf("NewBacklogFileCache: %v", err)
+	}
+	if err := cache.put("key", []byte(`{}`)); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	if err := cache.Invalidate(); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	if _, ok := cache.get("key"); ok {
+		t.Error("entry remains after Invalidate")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("cache file remains after Invalidate: %v", err)
+	}
+	// ファイルがない状態で再度破棄してもエラーにしない
+	if err := cache.Invalidate(); err != nil {
+		t.Errorf("second Invalidate: %v", err)
+	}
+}
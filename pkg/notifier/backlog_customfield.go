@@ -0,0 +1,359 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BacklogCustomFieldType はカスタム属性の種別 (Backlog API の typeId) です。
+type BacklogCustomFieldType int
+
+const (
+	CustomFieldText         BacklogCustomFieldType = 1 // 文字列
+	CustomFieldSentence     BacklogCustomFieldType = 2 // 文章
+	CustomFieldNumeric      BacklogCustomFieldType = 3 // 数値
+	CustomFieldDate         BacklogCustomFieldType = 4 // 日付
+	CustomFieldSingleList   BacklogCustomFieldType = 5 // 単一リスト
+	CustomFieldMultipleList BacklogCustomFieldType = 6 // 複数リスト
+	CustomFieldCheckbox     BacklogCustomFieldType = 7 // チェックボックス
+	CustomFieldRadio        BacklogCustomFieldType = 8 // ラジオ
+)
+
+// String はカスタム属性の種別名を返します。
+func (t BacklogCustomFieldType) String() string {
+	switch t {
+	case CustomFieldText:
+		return "文字列"
+	case CustomFieldSentence:
+		return "文章"
+	case CustomFieldNumeric:
+		return "数値"
+	case CustomFieldDate:
+		return "日付"
+	case CustomFieldSingleList:
+		return "単一リスト"
+	case CustomFieldMultipleList:
+		return "複数リスト"
+	case CustomFieldCheckbox:
+		return "チェックボックス"
+	case CustomFieldRadio:
+		return "ラジオ"
+	default:
+		return fmt.Sprintf("不明 (%d)", int(t))
+	}
+}
+
+// isList は、選択肢から値を選ぶ種別かを返します。
+func (t BacklogCustomFieldType) isList() bool {
+	return t == CustomFieldSingleList || t == CustomFieldMultipleList || t == CustomFieldCheckbox || t == CustomFieldRadio
+}
+
+// isMultiple は、複数の選択肢を選べる種別かを返します。
+func (t BacklogCustomFieldType) isMultiple() bool {
+	return t == CustomFieldMultipleList || t == CustomFieldCheckbox
+}
+
+// BacklogCustomFieldResponse はプロジェクトのカスタム属性の定義です。
+type BacklogCustomFieldResponse struct {
+	ID          int                    `json:"id"`
+	TypeID      BacklogCustomFieldType `json:"typeId"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Required    bool                   `json:"required"`
+	// ApplicableIssueTypes: 適用される課題種別の ID (空の場合はすべての課題種別)
+	ApplicableIssueTypes []int `json:"applicableIssueTypes"`
+	// Items: リスト系の種別の選択肢
+	Items []BacklogCustomFieldItem `json:"items"`
+}
+
+// BacklogCustomFieldItem はリスト系のカスタム属性の選択肢です。
+type BacklogCustomFieldItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// BacklogIssueCustomField は、課題に設定されたカスタム属性の値です。
+type BacklogIssueCustomField struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Value: 値 (種別により文字列・数値・選択肢のオブジェクトなど形式が異なります)
+	Value json.RawMessage `json:"value"`
+}
+
+// customFieldText は、課題の文字列のカスタム属性の値を返します。未設定または文字列でない場合は空文字列を返します。
+func (i BacklogIssueResponse) customFieldText(id int) string {
+	for _, f := range i.CustomFields {
+		if f.ID != id {
+			continue
+		}
+		var value string
+		if json.Unmarshal(f.Value, &value) == nil {
+			return value
+		}
+	}
+	return ""
+}
+
+// appliesTo は、カスタム属性が指定された課題種別に適用されるかを返します。
+func (f BacklogCustomFieldResponse) appliesTo(issueTypeID int) bool {
+	return len(f.ApplicableIssueTypes) == 0 || slices.Contains(f.ApplicableIssueTypes, issueTypeID)
+}
+
+// ListCustomFields は、指定されたプロジェクトのカスタム属性の定義の一覧を取得します。
+func (c *BacklogNotifier) ListCustomFields(ctx context.Context, projectID int) ([]BacklogCustomFieldResponse, error) {
+	var fields []BacklogCustomFieldResponse
+	if err := c.getCachedJSON(ctx, fmt.Sprintf("/projects/%d/customFields", projectID), &fields); err != nil {
+		return nil, fmt.Errorf("カスタム属性一覧の取得に失敗 (ProjectID: %d): %w", projectID, err)
+	}
+	return fields, nil
+}
+
+// customFieldValue は、フィールド名で指定されたカスタム属性の値です。
+type customFieldValue struct {
+	name string
+	// raw: 文字列で指定された値 (Set)。カスタム属性の種別に応じて変換します
+	raw []string
+	// 型付きで指定された値 (SetText / SetNumber / SetDate / SetItems)
+	kind   BacklogCustomFieldType
+	text   string
+	number float64
+	date   time.Time
+	items  []string
+}
+
+// BacklogCustomFields は、課題に設定するカスタム属性の値をフィールド名で保持します。
+// 値は送信前にプロジェクトのカスタム属性の定義と照合され、種別の不一致や存在しない選択肢はエラーになります。
+type BacklogCustomFields struct {
+	values []*customFieldValue
+}
+
+// value は、フィールド名に対応する値を取得します (なければ追加します)。
+func (f *BacklogCustomFields) value(name string) *customFieldValue {
+	for _, v := range f.values {
+		if strings.EqualFold(v.name, name) {
+			return v
+		}
+	}
+	v := &customFieldValue{name: name}
+	f.values = append(f.values, v)
+	return v
+}
+
+// Len は、値が設定されたカスタム属性の数を返します。
+func (f *BacklogCustomFields) Len() int {
+	if f == nil {
+		return 0
+	}
+	return len(f.values)
+}
+
+// clone は、値を追加しても元の BacklogCustomFields が変更されないように複製します (nil の場合は空の値を返します)。
+func (f *BacklogCustomFields) clone() *BacklogCustomFields {
+	c := &BacklogCustomFields{}
+	if f == nil {
+		return c
+	}
+	for _, v := range f.values {
+		copied := *v
+		c.values = append(c.values, &copied)
+	}
+	return c
+}
+
+// SetText は、文字列・文章のカスタム属性に値を設定します。
+func (f *BacklogCustomFields) SetText(name, value string) *BacklogCustomFields {
+	v := f.value(name)
+	v.kind, v.text = CustomFieldText, value
+	return f
+}
+
+// SetNumber は、数値のカスタム属性に値を設定します。
+func (f *BacklogCustomFields) SetNumber(name string, value float64) *BacklogCustomFields {
+	v := f.value(name)
+	v.kind, v.number = CustomFieldNumeric, value
+	return f
+}
+
+// SetDate は、日付のカスタム属性に値を設定します。
+func (f *BacklogCustomFields) SetDate(name string, value time.Time) *BacklogCustomFields {
+	v := f.value(name)
+	v.kind, v.date = CustomFieldDate, value
+	return f
+}
+
+// SetItems は、リスト・チェックボックス・ラジオのカスタム属性に、選択肢の名前または ID を設定します。
+func (f *BacklogCustomFields) SetItems(name string, items ...string) *BacklogCustomFields {
+	v := f.value(name)
+	v.kind, v.items = CustomFieldMultipleList, items
+	return f
+}
+
+// Set は、文字列で指定された値を、カスタム属性の種別に応じて変換して設定します (CLI の name=value 形式など)。
+// リスト系の種別では、カンマ区切りで複数の選択肢を指定できます。同じ名前で複数回呼び出した場合は値が追加されます。
+func (f *BacklogCustomFields) Set(name, value string) *BacklogCustomFields {
+	v := f.value(name)
+	v.kind = 0
+	v.raw = append(v.raw, value)
+	return f
+}
+
+// formValues は、カスタム属性の定義と照合して、課題 API のフォームパラメータ (customField_{id}) に変換します。
+func (f *BacklogCustomFields) formValues(defs []BacklogCustomFieldResponse) (map[string][]string, error) {
+	form := map[string][]string{}
+	if f == nil {
+		return form, nil
+	}
+
+	for _, v := range f.values {
+		idx := slices.IndexFunc(defs, func(d BacklogCustomFieldResponse) bool {
+			return strings.EqualFold(d.Name, v.name) || strconv.Itoa(d.ID) == v.name
+		})
+		if idx < 0 {
+			names := make([]string, 0, len(defs))
+			for _, d := range defs {
+				names = append(names, d.Name)
+			}
+			return nil, fmt.Errorf("カスタム属性 %q が見つかりません (有効な値: %s)", v.name, strings.Join(names, ", "))
+		}
+		def := defs[idx]
+
+		values, err := v.encode(def)
+		if err != nil {
+			return nil, fmt.Errorf("カスタム属性 %q: %w", def.Name, err)
+		}
+		form[fmt.Sprintf("customField_%d", def.ID)] = values
+	}
+	return form, nil
+}
+
+// encode は、値をカスタム属性の種別に応じたフォームの値に変換します。
+func (v *customFieldValue) encode(def BacklogCustomFieldResponse) ([]string, error) {
+	mismatch := func() error {
+		return fmt.Errorf("%s の値は設定できません (カスタム属性の種別: %s)", v.kind, def.TypeID)
+	}
+
+	switch def.TypeID {
+	case CustomFieldText, CustomFieldSentence:
+		switch {
+		case v.raw != nil:
+			return []string{strings.Join(v.raw, "\n")}, nil
+		case v.kind == CustomFieldText:
+			return []string{v.text}, nil
+		}
+		return nil, mismatch()
+
+	case CustomFieldNumeric:
+		switch {
+		case v.raw != nil:
+			if len(v.raw) > 1 {
+				return nil, fmt.Errorf("数値の値は1つだけ指定できます")
+			}
+			n, err := strconv.ParseFloat(strings.TrimSpace(v.raw[0]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("数値として解釈できません: %q", v.raw[0])
+			}
+			return []string{strconv.FormatFloat(n, 'f', -1, 64)}, nil
+		case v.kind == CustomFieldNumeric:
+			return []string{strconv.FormatFloat(v.number, 'f', -1, 64)}, nil
+		}
+		return nil, mismatch()
+
+	case CustomFieldDate:
+		switch {
+		case v.raw != nil:
+			if len(v.raw) > 1 {
+				return nil, fmt.Errorf("日付の値は1つだけ指定できます")
+			}
+			d := strings.TrimSpace(v.raw[0])
+			if _, err := time.Parse("2006-01-02", d); err != nil {
+				return nil, fmt.Errorf("日付 %q の形式が不正です (yyyy-MM-dd 形式で指定してください)", d)
+			}
+			return []string{d}, nil
+		case v.kind == CustomFieldDate:
+			return []string{v.date.Format("2006-01-02")}, nil
+		}
+		return nil, mismatch()
+	}
+
+	if !def.TypeID.isList() {
+		return nil, fmt.Errorf("未対応のカスタム属性の種別です: %s", def.TypeID)
+	}
+
+	items := v.items
+	if v.raw != nil {
+		items = nil
+		for _, r := range v.raw {
+			for _, item := range strings.Split(r, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		}
+	} else if v.kind != CustomFieldMultipleList {
+		return nil, mismatch()
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("選択肢が指定されていません")
+	}
+	if len(items) > 1 && !def.TypeID.isMultiple() {
+		return nil, fmt.Errorf("%s には選択肢を1つだけ指定できます", def.TypeID)
+	}
+
+	choices := make([]backlogChoice, 0, len(def.Items))
+	for _, item := range def.Items {
+		choices = append(choices, backlogChoice{ID: item.ID, Name: item.Name})
+	}
+	ids, err := resolveBacklogChoices("選択肢", items, choices)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.Itoa(id))
+	}
+	return values, nil
+}
+
+// resolveCustomFields は、カスタム属性の値を検証してフォームパラメータに変換します。
+// checkRequired が true の場合 (課題の登録時)、課題種別に適用される必須のカスタム属性が未設定であればエラーを返します。
+func (c *BacklogNotifier) resolveCustomFields(ctx context.Context, projectID, issueTypeID int, fields *BacklogCustomFields, checkRequired bool) (map[string][]string, error) {
+	if fields.Len() == 0 && !checkRequired {
+		return nil, nil
+	}
+
+	defs, err := c.ListCustomFields(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 課題種別に適用されないカスタム属性は照合の対象外とする
+	applicable := make([]BacklogCustomFieldResponse, 0, len(defs))
+	for _, d := range defs {
+		if d.appliesTo(issueTypeID) {
+			applicable = append(applicable, d)
+		}
+	}
+
+	form, err := fields.formValues(applicable)
+	if err != nil {
+		return nil, err
+	}
+
+	if checkRequired {
+		var missing []string
+		for _, d := range applicable {
+			if d.Required && len(form[fmt.Sprintf("customField_%d", d.ID)]) == 0 {
+				missing = append(missing, fmt.Sprintf("%s (%s)", d.Name, d.TypeID))
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("必須のカスタム属性が設定されていません: %s", strings.Join(missing, ", "))
+		}
+	}
+	return form, nil
+}
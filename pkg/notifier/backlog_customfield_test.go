@@ -0,0 +1,139 @@
+package notifier
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCustomFieldDefs は、カスタム属性の各種別の定義です。
+var testCustomFieldDefs = []BacklogCustomFieldResponse{
+	{ID: 50, TypeID: CustomFieldText, Name: "フィンガープリント"},
+	{ID: 51, TypeID: CustomFieldNumeric, Name: "件数"},
+	{ID: 52, TypeID: CustomFieldDate, Name: "発生日"},
+	{ID: 53, TypeID: CustomFieldSingleList, Name: "環境", Items: []BacklogCustomFieldItem{{ID: 1, Name: "prod"}, {ID: 2, Name: "stg"}}},
+	{ID: 54, TypeID: CustomFieldCheckbox, Name: "影響範囲", Items: []BacklogCustomFieldItem{{ID: 3, Name: "API"}, {ID: 4, Name: "バッチ"}}},
+	{ID: 55, TypeID: CustomFieldSentence, Name: "メモ"},
+}
+
+func TestBacklogCustomFieldsFormValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields *BacklogCustomFields
+		want   map[string][]string
+	}{
+		{"文字列", (&BacklogCustomFields{}).SetText("フィンガープリント", "batch.nightly"), map[string][]string{"customField_50": {"batch.nightly"}}},
+		{"ID で指定", (&BacklogCustomFields{}).SetText("50", "batch.nightly"), map[string][]string{"customField_50": {"batch.nightly"}}},
+		{"数値", (&BacklogCustomFields{}).SetNumber("件数", 2.5), map[string][]string{"customField_51": {"2.5"}}},
+		{"日付", (&BacklogCustomFields{}).SetDate("発生日", time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)), map[string][]string{"customField_52": {"2026-10-16"}}},
+		{"選択肢の名前", (&BacklogCustomFields{}).SetItems("環境", "PROD"), map[string][]string{"customField_53": {"1"}}},
+		{"複数の選択肢", (&BacklogCustomFields{}).SetItems("影響範囲", "API", "4"), map[string][]string{"customField_54": {"3", "4"}}},
+		{"文字列からの変換", (&BacklogCustomFields{}).Set("件数", " 3 ").Set("発生日", "2026-10-16").Set("影響範囲", "API, バッチ"), map[string][]string{
+			"customField_51": {"3"}, "customField_52": {"2026-10-16"}, "customField_54": {"3", "4"},
+		}},
+		{"文章は複数の値を改行で連結する", (&BacklogCustomFields{}).Set("メモ", "1行目").Set("メモ", "2行目"), map[string][]string{"customField_55": {"1行目\n2行目"}}},
+		{"未指定", nil, map[string][]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fields.formValues(testCustomFieldDefs)
+			if err != nil {
+				t.Fatalf("formValues: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("formValues() = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if !slices.Equal(got[k], v) {
+					t.Errorf("%s = %v, want %v", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestBacklogCustomFieldsFormValuesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  *BacklogCustomFields
+		wantErr string
+	}{
+		{"存在しない属性", (&BacklogCustomFields{}).Set("重要度", "高"), `カスタム属性 "重要度" が見つかりません`},
+		{"種別の不一致", (&BacklogCustomFields{}).SetNumber("フィンガープリント", 1), "数値 の値は設定できません (カスタム属性の種別: 文字列)"},
+		{"数値の形式", (&BacklogCustomFields{}).Set("件数", "多数"), `数値として解釈できません: "多数"`},
+		{"日付の形式", (&BacklogCustomFields{}).Set("発生日", "10/16"), `日付 "10/16" の形式が不正です`},
+		{"単一リストに複数の選択肢", (&BacklogCustomFields{}).SetItems("環境", "prod", "stg"), "単一リスト には選択肢を1つだけ指定できます"},
+		{"存在しない選択肢", (&BacklogCustomFields{}).SetItems("環境", "dev"), `選択肢 "dev" が見つかりません (有効な値: prod (ID: 1), stg (ID: 2))`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.fields.formValues(testCustomFieldDefs)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBacklogSendIssueRequiredCustomFields(t *testing.T) {
+	newNotifier := func(t *testing.T) (*BacklogNotifier, *fakeBacklog) {
+		bn, fake := newFakeBacklogNotifier(t)
+		fake.customFields = []BacklogCustomFieldResponse{
+			{ID: 50, TypeID: CustomFieldText, Name: "フィンガープリント"},
+			{ID: 53, TypeID: CustomFieldSingleList, Name: "環境", Required: true, Items: []BacklogCustomFieldItem{{ID: 1, Name: "prod"}}},
+			// 課題種別「バグ」にのみ適用される必須の属性
+			{ID: 56, TypeID: CustomFieldText, Name: "再現手順", Required: true, ApplicableIssueTypes: []int{11}},
+		}
+		return bn, fake
+	}
+	ctx := context.Background()
+
+	t.Run("未設定の必須の属性", func(t *testing.T) {
+		bn, fake := newNotifier(t)
+		_, err := bn.SendIssue(ctx, "夜間バッチの失敗", "", 1, BacklogIssueOptions{IssueType: "バグ"})
+		if err == nil || !strings.Contains(err.Error(), "必須のカスタム属性が設定されていません: 環境 (単一リスト), 再現手順 (文字列)") {
+			t.Errorf("err = %v, want it to list the missing required fields", err)
+		}
+		if len(fake.created) != 0 {
+			t.Errorf("created %d issues, want 0", len(fake.created))
+		}
+	})
+
+	t.Run("課題種別に適用されない属性は必須としない", func(t *testing.T) {
+		bn, fake := newNotifier(t)
+		opts := BacklogIssueOptions{CustomFields: (&BacklogCustomFields{}).Set("環境", "prod")}
+		if _, err := bn.SendIssue(ctx, "夜間バッチの失敗", "", 1, opts); err != nil {
+			t.Fatalf("SendIssue: %v", err)
+		}
+		if got := fake.created[0]["customField_53"]; !slices.Equal(got, []string{"1"}) {
+			t.Errorf("customField_53 = %v, want [1]", got)
+		}
+		if fake.created[0].Has("customField_56") {
+			t.Error("sent a custom field that does not apply to the issue type")
+		}
+	})
+
+	t.Run("課題種別に適用されない属性は指定できない", func(t *testing.T) {
+		bn, _ := newNotifier(t)
+		opts := BacklogIssueOptions{CustomFields: (&BacklogCustomFields{}).Set("環境", "prod").Set("再現手順", "手順")}
+		if _, err := bn.SendIssue(ctx, "夜間バッチの失敗", "", 1, opts); err == nil || !strings.Contains(err.Error(), `"再現手順" が見つかりません`) {
+			t.Errorf("err = %v, want the field for another issue type to be rejected", err)
+		}
+	})
+
+	t.Run("更新時は必須の属性を検証しない", func(t *testing.T) {
+		bn, fake := newNotifier(t)
+		if _, err := bn.SendIssue(ctx, "夜間バッチの失敗", "", 1, BacklogIssueOptions{CustomFields: (&BacklogCustomFields{}).Set("環境", "prod")}); err != nil {
+			t.Fatalf("SendIssue: %v", err)
+		}
+		update := BacklogIssueUpdate{CustomFields: (&BacklogCustomFields{}).SetText("フィンガープリント", "batch.nightly")}
+		if _, err := bn.UpdateIssue(ctx, "PROJ-1", update); err != nil {
+			t.Fatalf("UpdateIssue: %v", err)
+		}
+		if got := fake.updated[0]; got.Get("customField_50") != "batch.nightly" || got.Has("customField_53") {
+			t.Errorf("update form = %v, want only customField_50", got)
+		}
+	})
+}
@@ -0,0 +1,254 @@
+package notifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shouni/go-utils/text"
+)
+
+// backlogOpenStatusID は、Backlog の標準の状態「未対応 (Open)」の ID です。再オープン時に使用します。
+const backlogOpenStatusID = 1
+
+// backlogRecurrenceComment は、一致した課題に追加する再発のコメントの1行目の書式です。
+const backlogRecurrenceComment = "再発を検知しました (発生回数: %d 回目)"
+
+// backlogOccurrenceLine は、課題の詳細の末尾に記録するフィンガープリントと発生回数の行の書式です。
+// HTML のコメントなどの隠しマーカーは Backlog 記法ではそのまま表示されるため、どちらの記法でも読める1行のテキストにします。
+const backlogOccurrenceLine = "go-notifier フィンガープリント: %s / 発生回数: %d"
+
+var (
+	// backlogOccurrencePattern は、課題の詳細からフィンガープリントと発生回数の行を取得する正規表現です。
+	backlogOccurrencePattern = regexp.MustCompile(`(?m)^go-notifier フィンガープリント: ([A-Za-z0-9._:-]+) / 発生回数: (\d+)[ \t]*$`)
+	// backlogFingerprintPattern は、指定されたフィンガープリントに使用できる文字です。
+	backlogFingerprintPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+)
+
+// BacklogEnsureOptions は EnsureIssue の動作を指定します。
+type BacklogEnsureOptions struct {
+	// FingerprintField: フィンガープリントを保持する文字列のカスタム属性の名前または ID。
+	// 空で Fingerprint も空の場合は、サマリーが完全に一致する課題を同じ事象とみなします
+	FingerprintField string
+	// Fingerprint: 同一の事象を識別する値 (英数字と "._:-")。空の場合はサマリーから計算します。
+	// FingerprintField を指定しない場合は、課題の詳細に記録したフィンガープリントで照合します
+	Fingerprint string
+	// Reopen: 一致した課題が完了している場合に、新しい課題を登録せず「未対応」に戻します
+	Reopen bool
+	// Issue: 新しい課題を登録する場合の課題種別・優先度などの指定
+	Issue BacklogIssueOptions
+}
+
+// BacklogEnsureResult は EnsureIssue の結果です。
+type BacklogEnsureResult struct {
+	// Issue: 登録された、または一致した課題
+	Issue BacklogIssueResult `json:"issue"`
+	// Fingerprint: 課題に記録したフィンガープリント
+	Fingerprint string `json:"fingerprint"`
+	// Created: 新しい課題を登録した場合は true (一致した課題にコメントを追加した場合は false)
+	Created bool `json:"created"`
+	// Reopened: 完了していた課題を「未対応」に戻した場合は true
+	Reopened bool `json:"reopened"`
+	// Occurrences: 今回を含めた発生回数
+	Occurrences int `json:"occurrences"`
+}
+
+// IssueFingerprint は、課題のサマリーから重複判定用のフィンガープリントを計算します。
+// 絵文字・大文字小文字・空白の違いは無視するため、同じ件名で繰り返し通知される事象は同じ値になります。
+func IssueFingerprint(summary string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(text.CleanStringFromEmojis(summary)), " "))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:8])
+}
+
+// backlogIssueMatcher は、既存の課題を検索する条件と、検索結果が同じ事象かを判定する関数です。
+type backlogIssueMatcher struct {
+	query url.Values
+	match func(issue BacklogIssueResponse) bool
+}
+
+// issueMatcher は、EnsureIssue の照合条件を組み立てます。
+// FingerprintField を指定した場合はカスタム属性の値で、Fingerprint だけを指定した場合は課題の詳細に記録したフィンガープリントで、
+// どちらも指定しない場合は (登録時と同じ変換をした) サマリーで照合します。
+func (c *BacklogNotifier) issueMatcher(ctx context.Context, summary string, projectID int, fingerprint string, opts BacklogEnsureOptions) (*backlogIssueMatcher, error) {
+	query := url.Values{}
+	query.Set("projectId[]", strconv.Itoa(projectID))
+	query.Set("sort", "updated")
+	query.Set("order", "desc")
+	query.Set("count", "100")
+
+	if opts.FingerprintField != "" {
+		field, err := c.fingerprintField(ctx, projectID, opts.FingerprintField)
+		if err != nil {
+			return nil, err
+		}
+		query.Set(fmt.Sprintf("customField_%d", field.ID), fingerprint)
+		return &backlogIssueMatcher{
+			query: query,
+			match: func(issue BacklogIssueResponse) bool { return issue.customFieldText(field.ID) == fingerprint },
+		}, nil
+	}
+
+	if strings.TrimSpace(opts.Fingerprint) != "" {
+		// キーワード検索は部分一致のため、課題の詳細に同じフィンガープリントが記録された課題だけを対象にする
+		query.Set("keyword", fingerprint)
+		return &backlogIssueMatcher{
+			query: query,
+			match: func(issue BacklogIssueResponse) bool {
+				fp, _, ok := parseOccurrenceLine(issue.Description)
+				return ok && fp == fingerprint
+			},
+		}, nil
+	}
+
+	// 検索は部分一致のため、登録されるサマリーと完全に一致する課題だけを対象にする
+	sanitized, _ := TruncateText(c.sanitizeSummary(summary), BacklogSummaryLimit, ellipsis)
+	query.Set("keyword", sanitized)
+	return &backlogIssueMatcher{
+		query: query,
+		match: func(issue BacklogIssueResponse) bool { return issue.Summary == sanitized },
+	}, nil
+}
+
+// fingerprintField は、フィンガープリントを保持するカスタム属性の定義を名前または ID で取得します。
+func (c *BacklogNotifier) fingerprintField(ctx context.Context, projectID int, nameOrID string) (*BacklogCustomFieldResponse, error) {
+	defs, err := c.ListCustomFields(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range defs {
+		if !strings.EqualFold(d.Name, nameOrID) && strconv.Itoa(d.ID) != nameOrID {
+			continue
+		}
+		if d.TypeID != CustomFieldText {
+			return nil, fmt.Errorf("フィンガープリントのカスタム属性 %q は文字列の種別である必要があります (種別: %s)", d.Name, d.TypeID)
+		}
+		return &d, nil
+	}
+	return nil, fmt.Errorf("フィンガープリントのカスタム属性 %q が見つかりません", nameOrID)
+}
+
+// findExistingIssue は、照合条件に一致する課題を検索します。
+// 未完了の課題を優先し、未完了の課題がなければ最近更新された完了済みの課題を返します。見つからない場合は nil を返します。
+func (c *BacklogNotifier) findExistingIssue(ctx context.Context, matcher *backlogIssueMatcher) (*BacklogIssueResponse, error) {
+	var issues []BacklogIssueResponse
+	if err := c.getJSON(ctx, "/issues", matcher.query, &issues); err != nil {
+		return nil, fmt.Errorf("同じ事象の課題の検索に失敗: %w", err)
+	}
+
+	var closed *BacklogIssueResponse
+	for i := range issues {
+		if !matcher.match(issues[i]) {
+			continue
+		}
+		if issues[i].Status == nil || issues[i].Status.ID != backlogClosedStatusID {
+			return &issues[i], nil
+		}
+		if closed == nil {
+			closed = &issues[i]
+		}
+	}
+	return closed, nil
+}
+
+// parseOccurrenceLine は、課題の詳細に記録したフィンガープリントと発生回数を取得します。
+// 記録がない場合は ok に false を返します。
+func parseOccurrenceLine(description string) (fingerprint string, occurrences int, ok bool) {
+	m := backlogOccurrencePattern.FindStringSubmatch(description)
+	if m == nil {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", 0, false
+	}
+	return m[1], n, true
+}
+
+// withOccurrenceLine は、課題の詳細のフィンガープリントと発生回数の行を更新します。行がない場合は末尾に追加します。
+func withOccurrenceLine(description, fingerprint string, occurrences int) string {
+	line := fmt.Sprintf(backlogOccurrenceLine, fingerprint, occurrences)
+	if loc := backlogOccurrencePattern.FindStringIndex(description); loc != nil {
+		return description[:loc[0]] + line + description[loc[1]:]
+	}
+	if strings.TrimSpace(description) == "" {
+		return line
+	}
+	return strings.TrimRight(description, "\n") + "\n\n" + line
+}
+
+// EnsureIssue は、同一の事象の課題が重複して登録されないように課題を登録します。
+// 同じ事象の未完了の課題があれば、新しい課題を登録せずに今回の発生と発生回数をコメントとして追加します。
+// 一致した課題が完了している場合は、opts.Reopen が true なら「未対応」に戻してコメントを追加し、false なら新しい課題を登録します。
+// 同じ事象かどうかは、opts.FingerprintField を指定した場合はそのカスタム属性に保持したフィンガープリントで、
+// opts.Fingerprint だけを指定した場合は課題の詳細に記録したフィンガープリントで、どちらも指定しない場合はサマリーの完全一致で判定します。
+// フィンガープリントと発生回数は課題の詳細の末尾の1行に記録し、再発のたびに更新します。
+func (c *BacklogNotifier) EnsureIssue(ctx context.Context, summary, description string, projectID int, opts BacklogEnsureOptions) (*BacklogEnsureResult, error) {
+	fingerprint := strings.TrimSpace(opts.Fingerprint)
+	if fingerprint == "" {
+		fingerprint = IssueFingerprint(summary)
+	} else if !backlogFingerprintPattern.MatchString(fingerprint) {
+		return nil, fmt.Errorf("フィンガープリント %q に使用できない文字が含まれています (英数字と \"._:-\" のみ使用できます)", fingerprint)
+	}
+
+	matcher, err := c.issueMatcher(ctx, summary, projectID, fingerprint, opts)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := c.findExistingIssue(ctx, matcher)
+	if err != nil {
+		return nil, err
+	}
+	closed := existing != nil && existing.Status != nil && existing.Status.ID == backlogClosedStatusID
+
+	// 1. 一致する課題がない (または完了済みで再オープンしない) 場合は、新しい課題を登録する
+	if existing == nil || (closed && !opts.Reopen) {
+		issueOpts := opts.Issue
+		if opts.FingerprintField != "" {
+			issueOpts.CustomFields = opts.Issue.CustomFields.clone().SetText(opts.FingerprintField, fingerprint)
+		}
+		issue, err := c.SendIssue(ctx, summary, withOccurrenceLine(description, fingerprint, 1), projectID, issueOpts)
+		if err != nil {
+			return nil, err
+		}
+		return &BacklogEnsureResult{Issue: *issue, Fingerprint: fingerprint, Created: true, Occurrences: 1}, nil
+	}
+
+	// 2. 一致する課題がある場合は、課題の詳細の発生回数を更新してコメントを追加する。
+	// 発生回数の記録がない課題 (手動で登録した課題など) は、登録時の 1 回から数える
+	occurrences := 1
+	if _, n, ok := parseOccurrenceLine(existing.Description); ok {
+		occurrences = n
+	}
+	occurrences++
+	update := BacklogIssueUpdate{
+		Description: withOccurrenceLine(existing.Description, fingerprint, occurrences),
+		Comment:     fmt.Sprintf(backlogRecurrenceComment+"\n\n%s", occurrences, description),
+		Attachments: opts.Issue.Attachments,
+	}
+	if closed {
+		update.Status = strconv.Itoa(backlogOpenStatusID)
+	}
+	issue, err := c.UpdateIssue(ctx, existing.IssueKey, update)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BacklogEnsureResult{
+		Issue: BacklogIssueResult{
+			ID:        issue.ID,
+			ProjectID: issue.ProjectID,
+			IssueKey:  issue.IssueKey,
+			URL:       c.IssueURL(issue.IssueKey),
+			Created:   issue.Created,
+		},
+		Fingerprint: fingerprint,
+		Reopened:    closed,
+		Occurrences: occurrences,
+	}, nil
+}
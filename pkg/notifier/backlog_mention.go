@@ -0,0 +1,69 @@
+package notifier
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// mentionPattern は、コメント本文中の @ログイン名 のメンションを検出する正規表現です。
+// メールアドレス (user@example.com) の @ はメンションとして扱いません。
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9._@-])@([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// parseMentions は、テキストに含まれる @ログイン名 のログイン名を、出現順に重複なく返します。
+func parseMentions(content string) []string {
+	var logins []string
+	for _, m := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		// 文末の句読点 (例: "@yamada.") はログイン名に含めない
+		login := strings.TrimRight(m[1], ".-")
+		if login != "" && !slices.Contains(logins, login) {
+			logins = append(logins, login)
+		}
+	}
+	return logins
+}
+
+// resolveNotifiedUsers は、コメントのお知らせ先のユーザー ID を、課題のプロジェクトのユーザーから決定します。
+// notify はユーザー ID、ログイン名または表示名で指定し、見つからない場合はエラーを返します。
+// parseContent が true の場合は、本文中の @ログイン名 もお知らせ先に加えます (プロジェクトのユーザーでないログイン名は無視します)。
+func (c *BacklogNotifier) resolveNotifiedUsers(ctx context.Context, issueIDOrKey string, notify []string, content string, parseContent bool) ([]int, error) {
+	var mentions []string
+	if parseContent {
+		mentions = parseMentions(content)
+	}
+	if len(notify) == 0 && len(mentions) == 0 {
+		return nil, nil
+	}
+
+	issue, err := c.GetIssue(ctx, issueIDOrKey)
+	if err != nil {
+		return nil, err
+	}
+	users, err := c.ListProjectUsers(ctx, issue.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	choices := make([]backlogChoice, 0, len(users))
+	for _, u := range users {
+		choices = append(choices, backlogChoice{ID: u.ID, Name: u.Name, Alias: u.UserID})
+	}
+
+	resolved, err := resolveBacklogChoices("お知らせ先のユーザー", notify, choices)
+	if err != nil {
+		return nil, err
+	}
+	for _, login := range mentions {
+		if idx := slices.IndexFunc(users, func(u BacklogUserResponse) bool { return strings.EqualFold(u.UserID, login) }); idx >= 0 {
+			resolved = append(resolved, users[idx].ID)
+		}
+	}
+
+	var ids []int
+	for _, id := range resolved {
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,82 @@
+package notifier
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"行頭と文中", "@yamada 確認お願いします (cc: @sato)", []string{"yamada", "sato"}},
+		{"全角文字の直後", "担当は@yamadaです", []string{"yamada"}},
+		{"記号を含むログイン名", "@taro.yamada_01 さん", []string{"taro.yamada_01"}},
+		{"文末の句読点は含めない", "@yamada. @sato-", []string{"yamada", "sato"}},
+		{"重複は除く", "@yamada @YAMADA @yamada", []string{"yamada", "YAMADA"}},
+		{"メールアドレスは対象外", "連絡先: yamada@example.com", nil},
+		{"@ のみ", "@ と @@", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseMentions(tt.content); !slices.Equal(got, tt.want) {
+				t.Errorf("parseMentions(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBacklogPostCommentNotifiedUsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		opts    BacklogCommentOptions
+		want    []string
+	}{
+		{"本文のメンション", "@yamada 確認お願いします", BacklogCommentOptions{}, []string{"101"}},
+		{"プロジェクトのユーザーでないログイン名は無視する", "@tanaka @sato 確認お願いします", BacklogCommentOptions{}, []string{"102"}},
+		{"指定と本文のメンションを重複なくまとめる", "@Yamada @sato", BacklogCommentOptions{NotifiedUsers: []string{"佐藤花子", "101"}}, []string{"102", "101"}},
+		{"メンションの解析を無効にする", "@yamada 確認お願いします", BacklogCommentOptions{NotifiedUsers: []string{"sato"}, IgnoreMentions: true}, []string{"102"}},
+		{"お知らせ先なし", "確認しました (yamada@example.com)", BacklogCommentOptions{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bn, fake := newFakeBacklogNotifier(t)
+			ctx := context.Background()
+			if _, err := bn.SendIssue(ctx, "夜間バッチの失敗", "", 1); err != nil {
+				t.Fatalf("SendIssue: %v", err)
+			}
+
+			if _, err := bn.PostComment(ctx, "PROJ-1", tt.content, tt.opts); err != nil {
+				t.Fatalf("PostComment: %v", err)
+			}
+			form := fake.commented[0]
+			if got := form["notifiedUserId[]"]; !slices.Equal(got, tt.want) {
+				t.Errorf("notifiedUserId[] = %v, want %v", got, tt.want)
+			}
+			if form.Get("content") != tt.content {
+				t.Errorf("content = %q, want %q", form.Get("content"), tt.content)
+			}
+		})
+	}
+}
+
+func TestBacklogPostCommentUnknownNotifiedUser(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+	ctx := context.Background()
+	if _, err := bn.SendIssue(ctx, "夜間バッチの失敗", "", 1); err != nil {
+		t.Fatalf("SendIssue: %v", err)
+	}
+
+	_, err := bn.PostComment(ctx, "PROJ-1", "確認お願いします", BacklogCommentOptions{NotifiedUsers: []string{"tanaka"}})
+	if err == nil || !strings.Contains(err.Error(), `お知らせ先のユーザー "tanaka" が見つかりません (有効な値: yamada / 山田太郎 (ID: 101), sato / 佐藤花子 (ID: 102))`) {
+		t.Errorf("err = %v, want it to list the project users", err)
+	}
+	if len(fake.commented) != 0 {
+		t.Errorf("posted %d comments, want 0", len(fake.commented))
+	}
+}
@@ -0,0 +1,88 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// backlogClosedStatusID は、Backlog の標準の状態「完了 (Closed)」の ID です。
+// プロジェクト独自の状態を追加しても、完了の ID は変わりません。
+const backlogClosedStatusID = 4
+
+// BacklogResolutionResponse は完了理由の最小限の構造体です。
+type BacklogResolutionResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ListStatuses は、指定されたプロジェクトの課題の状態の一覧を取得します (プロジェクト独自の状態を含みます)。
+func (c *BacklogNotifier) ListStatuses(ctx context.Context, projectID int) ([]BacklogStatusResponse, error) {
+	var statuses []BacklogStatusResponse
+	if err := c.getCachedJSON(ctx, fmt.Sprintf("/projects/%d/statuses", projectID), &statuses); err != nil {
+		return nil, fmt.Errorf("状態一覧の取得に失敗 (ProjectID: %d): %w", projectID, err)
+	}
+	return statuses, nil
+}
+
+// ListResolutions は、完了理由の一覧を取得します (完了理由はスペース共通です)。
+func (c *BacklogNotifier) ListResolutions(ctx context.Context) ([]BacklogResolutionResponse, error) {
+	var resolutions []BacklogResolutionResponse
+	if err := c.getCachedJSON(ctx, "/resolutions", &resolutions); err != nil {
+		return nil, fmt.Errorf("完了理由一覧の取得に失敗: %w", err)
+	}
+	return resolutions, nil
+}
+
+// resolveStatus は、ID または名前で指定された状態を、プロジェクトの状態の ID に変換します。
+func (c *BacklogNotifier) resolveStatus(ctx context.Context, projectID int, status string) (int, error) {
+	statuses, err := c.ListStatuses(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	choices := make([]backlogChoice, 0, len(statuses))
+	for _, s := range statuses {
+		choices = append(choices, backlogChoice{ID: s.ID, Name: s.Name})
+	}
+	return resolveBacklogChoice("状態", status, choices)
+}
+
+// resolveResolution は、ID または名前で指定された完了理由を ID に変換します。
+// 「対応済み」の ID は 0 のため、未指定との区別は呼び出し側で空文字列により行います。
+func (c *BacklogNotifier) resolveResolution(ctx context.Context, resolution string) (int, error) {
+	resolutions, err := c.ListResolutions(ctx)
+	if err != nil {
+		return 0, err
+	}
+	choices := make([]backlogChoice, 0, len(resolutions))
+	for _, r := range resolutions {
+		choices = append(choices, backlogChoice{ID: r.ID, Name: r.Name})
+	}
+	return resolveBacklogChoice("完了理由", resolution, choices)
+}
+
+// ChangeStatus は、課題の状態を ID または名前 (例: "処理中") で変更します。comment が空でない場合は、コメントも追加します。
+func (c *BacklogNotifier) ChangeStatus(ctx context.Context, issueIDOrKey, status, comment string) (*BacklogIssueResponse, error) {
+	if status == "" {
+		return nil, fmt.Errorf("課題 %s の変更後の状態が指定されていません", issueIDOrKey)
+	}
+	return c.UpdateIssue(ctx, issueIDOrKey, BacklogIssueUpdate{Status: status, Comment: comment})
+}
+
+// SetResolution は、課題の完了理由を ID または名前 (例: "対応済み") で設定します。comment が空でない場合は、コメントも追加します。
+func (c *BacklogNotifier) SetResolution(ctx context.Context, issueIDOrKey, resolution, comment string) (*BacklogIssueResponse, error) {
+	if resolution == "" {
+		return nil, fmt.Errorf("課題 %s の完了理由が指定されていません", issueIDOrKey)
+	}
+	return c.UpdateIssue(ctx, issueIDOrKey, BacklogIssueUpdate{Resolution: resolution, Comment: comment})
+}
+
+// CloseIssue は、課題の状態を「完了」にします。resolution が空でない場合は完了理由も設定し、comment が空でない場合はコメントも追加します。
+// 監視ジョブなどが自動で登録した課題を、復旧時に自動で完了させる用途を想定しています。
+func (c *BacklogNotifier) CloseIssue(ctx context.Context, issueIDOrKey, resolution, comment string) (*BacklogIssueResponse, error) {
+	return c.UpdateIssue(ctx, issueIDOrKey, BacklogIssueUpdate{
+		Status:     strconv.Itoa(backlogClosedStatusID),
+		Resolution: resolution,
+		Comment:    comment,
+	})
+}
@@ -0,0 +1,128 @@
+package notifier
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBacklogChangeStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		want   string
+	}{
+		{"名前", "処理中", "2"},
+		{"プロジェクト独自の状態", "レビュー待ち", "100"},
+		{"ID", "3", "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bn, fake := newFakeBacklogNotifier(t)
+			ctx := context.Background()
+			if _, err := bn.SendIssue(ctx, "夜間バッチの失敗", "", 1); err != nil {
+				t.Fatalf("SendIssue: %v", err)
+			}
+
+			issue, err := bn.ChangeStatus(ctx, "PROJ-1", tt.status, "対応を開始します")
+			if err != nil {
+				t.Fatalf("ChangeStatus: %v", err)
+			}
+			if issue.Status == nil || strconv.Itoa(issue.Status.ID) != tt.want {
+				t.Errorf("status = %+v, want ID %s", issue.Status, tt.want)
+			}
+			form := fake.updated[0]
+			if form.Get("statusId") != tt.want || form.Get("comment") != "対応を開始します" || form.Has("resolutionId") {
+				t.Errorf("update form = %v", form)
+			}
+		})
+	}
+}
+
+func TestBacklogCloseIssue(t *testing.T) {
+	tests := []struct {
+		name           string
+		resolution     string
+		wantResolution string
+	}{
+		{"完了理由なし", "", ""},
+		// 「対応済み」の ID は 0 のため、未指定と区別して送信する
+		{"対応済み", "対応済み", "0"},
+		{"ID", "3", "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bn, fake := newFakeBacklogNotifier(t)
+			ctx := context.Background()
+			if _, err := bn.SendIssue(ctx, "夜間バッチの失敗", "", 1); err != nil {
+				t.Fatalf("SendIssue: %v", err)
+			}
+
+			issue, err := bn.CloseIssue(ctx, "1", tt.resolution, "")
+			if err != nil {
+				t.Fatalf("CloseIssue: %v", err)
+			}
+			if issue.Status == nil || issue.Status.ID != backlogClosedStatusID {
+				t.Errorf("status = %+v, want closed", issue.Status)
+			}
+			form := fake.updated[0]
+			if form.Get("statusId") != "4" || form.Has("comment") {
+				t.Errorf("update form = %v", form)
+			}
+			if got, ok := form["resolutionId"]; (tt.wantResolution == "") == ok || (ok && got[0] != tt.wantResolution) {
+				t.Errorf("resolutionId = %v, want %q", got, tt.wantResolution)
+			}
+		})
+	}
+}
+
+func TestBacklogSetResolution(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+	ctx := context.Background()
+	if _, err := bn.SendIssue(ctx, "夜間バッチの失敗", "", 1); err != nil {
+		t.Fatalf("SendIssue: %v", err)
+	}
+
+	issue, err := bn.SetResolution(ctx, "PROJ-1", "重複", "PROJ-2 で対応します")
+	if err != nil {
+		t.Fatalf("SetResolution: %v", err)
+	}
+	if issue.Resolution == nil || issue.Resolution.ID != 3 {
+		t.Errorf("resolution = %+v, want ID 3", issue.Resolution)
+	}
+	if form := fake.updated[0]; form.Get("resolutionId") != "3" || form.Has("statusId") || form.Get("comment") != "PROJ-2 で対応します" {
+		t.Errorf("update form = %v", form)
+	}
+}
+
+func TestBacklogLifecycleErrors(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+	ctx := context.Background()
+	if _, err := bn.SendIssue(ctx, "夜間バッチの失敗", "", 1); err != nil {
+		t.Fatalf("SendIssue: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr string
+	}{
+		{"状態が空", func() error { _, err := bn.ChangeStatus(ctx, "PROJ-1", "", ""); return err }, "変更後の状態が指定されていません"},
+		{"存在しない状態", func() error { _, err := bn.ChangeStatus(ctx, "PROJ-1", "保留", ""); return err },
+			`状態 "保留" が見つかりません (有効な値: 未対応 (ID: 1), 処理中 (ID: 2), 処理済み (ID: 3), 完了 (ID: 4), レビュー待ち (ID: 100))`},
+		{"完了理由が空", func() error { _, err := bn.SetResolution(ctx, "PROJ-1", "", ""); return err }, "完了理由が指定されていません"},
+		{"存在しない完了理由", func() error { _, err := bn.CloseIssue(ctx, "PROJ-1", "取り下げ", ""); return err }, `完了理由 "取り下げ" が見つかりません`},
+		{"存在しない課題", func() error { _, err := bn.ChangeStatus(ctx, "PROJ-99", "処理中", ""); return err }, "課題 PROJ-99 の取得に失敗"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+	if len(fake.updated) != 0 {
+		t.Errorf("issue was updated %d times, want 0", len(fake.updated))
+	}
+}
@@ -0,0 +1,795 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// newTestBacklogNotifier は、httptest のサーバーに接続する BacklogNotifier を生成します。
+// メタデータのキャッシュは無効にし、5xx のリトライは行いません。
+func newTestBacklogNotifier(t *testing.T, handler http.Handler) *BacklogNotifier {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := httpkit.New(5*time.Second, httpkit.WithMaxRetries(0))
+	bn, err := NewBacklogNotifier(*client, server.URL, "test-key")
+	if err != nil {
+		t.Fatalf("NewBacklogNotifier: %v", err)
+	}
+	return bn
+}
+
+func TestBacklogAPIErrorParsesBody(t *testing.T) {
+	bn := newTestBacklogNotifier(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors":[{"message":"No project.","code":6,"moreInfo":""}]}`))
+	}))
+
+	_, err := bn.GetProjectID(context.Background(), "NOPE")
+	var be *BacklogError
+	if !errors.As(err, &be) {
+		t.Fatalf("error = %v, want *BacklogError", err)
+	}
+	if be.StatusCode != http.StatusNotFound || be.Code != 6 || be.Message != "No project." {
+		t.Errorf("BacklogError = %+v, want status 404, code 6, message %q", be, "No project.")
+	}
+}
+
+func TestBacklogAPIErrorRawBody(t *testing.T) {
+	bn := newTestBacklogNotifier(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("Authentication failure"))
+	}))
+
+	_, err := bn.GetProjectID(context.Background(), "PROJ")
+	var be *BacklogError
+	if !errors.As(err, &be) {
+		t.Fatalf("error = %v, want *BacklogError", err)
+	}
+	if be.StatusCode != http.StatusUnauthorized || be.Message != "Raw Response: Authentication failure" {
+		t.Errorf("BacklogError = %+v, want status 401 with the raw body", be)
+	}
+}
+
+// fakeBacklog は、課題の登録・検索・更新・コメントと添付ファイルの送信を模擬する Backlog API です。
+// プロジェクトは ID 1 (課題種別「タスク」「バグ」) のみで、登録された課題とコメントはメモリ上に保持します。
+// 優先度・完了理由・ユーザー・カテゴリー・バージョンは、fakeBacklog* の固定の一覧を返します。
+type fakeBacklog struct {
+	mu sync.Mutex
+	// customFields: プロジェクトのカスタム属性の定義
+	customFields []BacklogCustomFieldResponse
+	// issues: 登録済みの課題
+	issues []BacklogIssueResponse
+	// comments: 課題キーごとのコメント (投稿順)
+	comments map[string][]BacklogCommentResponse
+	// searches: 課題の検索 (GET /issues) で受け取ったクエリ
+	searches []url.Values
+	// created: 課題の登録 (POST /issues) で受け取ったフォーム
+	created []url.Values
+	// updated: 課題の更新 (PATCH /issues/:key) で受け取ったフォーム
+	updated []url.Values
+	// commented: コメントの投稿 (POST /issues/:key/comments) で受け取ったフォーム
+	commented []url.Values
+	// uploads: 送信された添付ファイルの「ファイル名=内容」
+	uploads []string
+}
+
+func (f *fakeBacklog) handler(t *testing.T) http.Handler {
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(v); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}
+	parseForm := func(r *http.Request) url.Values {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		form := r.PostForm
+		form.Del("apiKey")
+		return form
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v2/projects/{key}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, BacklogProjectResponse{ID: 1, Key: r.PathValue("key")})
+	})
+	mux.HandleFunc("GET /api/v2/projects/1/issueTypes", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []BacklogIssueTypeResponse{{ID: 10, Name: "タスク"}, {ID: 11, Name: "バグ"}})
+	})
+	mux.HandleFunc("GET /api/v2/priorities", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, fakeBacklogPriorities)
+	})
+	mux.HandleFunc("GET /api/v2/resolutions", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, fakeBacklogResolutions)
+	})
+	mux.HandleFunc("GET /api/v2/projects/1/users", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, fakeBacklogUsers)
+	})
+	mux.HandleFunc("GET /api/v2/projects/1/categories", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, fakeBacklogCategories)
+	})
+	mux.HandleFunc("GET /api/v2/projects/1/versions", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, fakeBacklogVersions)
+	})
+	mux.HandleFunc("GET /api/v2/projects/1/customFields", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		writeJSON(w, append([]BacklogCustomFieldResponse{}, f.customFields...))
+	})
+	mux.HandleFunc("GET /api/v2/projects/1/statuses", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []BacklogStatusResponse{
+			{ID: backlogOpenStatusID, Name: "未対応"},
+			{ID: 2, Name: "処理中"},
+			{ID: 3, Name: "処理済み"},
+			{ID: backlogClosedStatusID, Name: "完了"},
+			{ID: 100, Name: "レビュー待ち"},
+		})
+	})
+	mux.HandleFunc("POST /api/v2/space/attachment", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("FormFile: %v", err)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.uploads = append(f.uploads, header.Filename+"="+string(data))
+		writeJSON(w, BacklogAttachmentResponse{ID: 100 + len(f.uploads), Name: header.Filename, Size: int64(len(data))})
+	})
+	mux.HandleFunc("GET /api/v2/issues", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		query := r.URL.Query()
+		query.Del("apiKey")
+		f.searches = append(f.searches, query)
+
+		// キーワードとカスタム属性の値は、実際の API と同様に部分一致で検索する
+		matches := func(issue BacklogIssueResponse) bool {
+			if k := query.Get("keyword"); !strings.Contains(issue.Summary, k) && !strings.Contains(issue.Description, k) {
+				return false
+			}
+			for name, v := range query {
+				if id, ok := strings.CutPrefix(name, "customField_"); ok {
+					n, _ := strconv.Atoi(id)
+					if text := issue.customFieldText(n); text == "" || !strings.Contains(text, v[0]) {
+						return false
+					}
+				}
+			}
+			return true
+		}
+		var found []BacklogIssueResponse
+		// 更新日時の降順 (後から登録・更新した課題が先頭)
+		for _, issue := range slices.Backward(f.issues) {
+			if matches(issue) {
+				found = append(found, issue)
+			}
+		}
+		writeJSON(w, found)
+	})
+	mux.HandleFunc("GET /api/v2/issues/{key}", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if i := f.index(r.PathValue("key")); i >= 0 {
+			writeJSON(w, f.issues[i])
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors":[{"message":"No issue.","code":6}]}`))
+	})
+	mux.HandleFunc("POST /api/v2/issues", func(w http.ResponseWriter, r *http.Request) {
+		form := parseForm(r)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.created = append(f.created, form)
+		id := len(f.issues) + 1
+		issue := BacklogIssueResponse{
+			ID:          id,
+			ProjectID:   1,
+			IssueKey:    fmt.Sprintf("PROJ-%d", id),
+			Summary:     form.Get("summary"),
+			Description: form.Get("description"),
+			IssueType:   &BacklogIssueTypeResponse{ID: 10, Name: "タスク"},
+			Status:      &BacklogStatusResponse{ID: backlogOpenStatusID, Name: "未対応"},
+		}
+		for _, def := range f.customFields {
+			if v, ok := form[fmt.Sprintf("customField_%d", def.ID)]; ok {
+				value, _ := json.Marshal(v[0])
+				issue.CustomFields = append(issue.CustomFields, BacklogIssueCustomField{ID: def.ID, Name: def.Name, Value: value})
+			}
+		}
+		f.issues = append(f.issues, issue)
+		writeJSON(w, issue)
+	})
+	mux.HandleFunc("GET /api/v2/issues/{key}/comments", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		comments := slices.Clone(f.comments[r.PathValue("key")])
+		if r.URL.Query().Get("order") != "asc" {
+			slices.Reverse(comments)
+		}
+		writeJSON(w, append([]BacklogCommentResponse{}, comments...))
+	})
+	mux.HandleFunc("POST /api/v2/issues/{key}/comments", func(w http.ResponseWriter, r *http.Request) {
+		form := parseForm(r)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		i := f.index(r.PathValue("key"))
+		if i < 0 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"message":"No issue.","code":6}]}`))
+			return
+		}
+		f.commented = append(f.commented, form)
+		key := f.issues[i].IssueKey
+		f.addComment(key, form.Get("content"))
+		writeJSON(w, f.comments[key][len(f.comments[key])-1])
+	})
+	mux.HandleFunc("PATCH /api/v2/issues/{key}", func(w http.ResponseWriter, r *http.Request) {
+		form := parseForm(r)
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		i := f.index(r.PathValue("key"))
+		if i < 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.updated = append(f.updated, form)
+		issue := f.issues[i]
+		if form.Has("description") {
+			issue.Description = form.Get("description")
+		}
+		if form.Has("statusId") {
+			id, _ := strconv.Atoi(form.Get("statusId"))
+			issue.Status = &BacklogStatusResponse{ID: id}
+		}
+		if form.Has("resolutionId") {
+			id, _ := strconv.Atoi(form.Get("resolutionId"))
+			issue.Resolution = &BacklogResolutionResponse{ID: id}
+		}
+		if form.Has("comment") {
+			f.addComment(issue.IssueKey, form.Get("comment"))
+		}
+		// 更新した課題を末尾に移し、検索結果の先頭にする
+		f.issues = append(slices.Delete(f.issues, i, i+1), issue)
+		writeJSON(w, issue)
+	})
+	return mux
+}
+
+// fakeBacklog が返す、プロジェクト ID 1 のメタデータです。
+var (
+	fakeBacklogPriorities  = []BacklogPriorityResponse{{ID: 2, Name: "高"}, {ID: 3, Name: "中"}, {ID: 4, Name: "低"}}
+	fakeBacklogResolutions = []BacklogResolutionResponse{{ID: 0, Name: "対応済み"}, {ID: 1, Name: "対応しない"}, {ID: 2, Name: "無効"}, {ID: 3, Name: "重複"}}
+	fakeBacklogUsers       = []BacklogUserResponse{{ID: 101, UserID: "yamada", Name: "山田太郎"}, {ID: 102, UserID: "sato", Name: "佐藤花子"}}
+	fakeBacklogCategories  = []BacklogCategoryResponse{{ID: 201, Name: "バッチ"}, {ID: 202, Name: "API"}}
+	fakeBacklogVersions    = []BacklogVersionResponse{{ID: 301, Name: "v1.0"}, {ID: 302, Name: "v2.0"}, {ID: 303, Name: "v0.9", Archived: true}}
+)
+
+// addComment は、課題にコメントを追加します。呼び出し側で mu をロックしてください。
+func (f *fakeBacklog) addComment(key, content string) {
+	if f.comments == nil {
+		f.comments = map[string][]BacklogCommentResponse{}
+	}
+	f.comments[key] = append(f.comments[key], BacklogCommentResponse{ID: len(f.comments[key]) + 1, Content: content})
+}
+
+// close は、課題を「完了」にします。
+func (f *fakeBacklog) close(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.issues[f.index(key)].Status = &BacklogStatusResponse{ID: backlogClosedStatusID, Name: "完了"}
+}
+
+// index は、課題キーまたは課題IDに一致する課題の位置を返します。見つからない場合は -1 を返します。
+func (f *fakeBacklog) index(idOrKey string) int {
+	return slices.IndexFunc(f.issues, func(issue BacklogIssueResponse) bool {
+		return issue.IssueKey == idOrKey || strconv.Itoa(issue.ID) == idOrKey
+	})
+}
+
+// newFakeBacklogNotifier は、fakeBacklog に接続する ProjectKey 設定済みの BacklogNotifier を生成します。
+func newFakeBacklogNotifier(t *testing.T) (*BacklogNotifier, *fakeBacklog) {
+	t.Helper()
+	fake := &fakeBacklog{}
+	bn := newTestBacklogNotifier(t, fake.handler(t))
+	bn.ProjectKey = "PROJ"
+	return bn, fake
+}
+
+func TestBacklogSendAttachments(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+
+	result, err := bn.Send(context.Background(), Message{
+		Title:       "夜間バッチの失敗",
+		Body:        "ログを添付します。",
+		Attachments: []Attachment{{Filename: "batch.log", Content: strings.NewReader("error: timeout")}},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.ID != "PROJ-1" {
+		t.Errorf("ID = %q, want PROJ-1", result.ID)
+	}
+	if want := []string{"batch.log=error: timeout"}; !slices.Equal(fake.uploads, want) {
+		t.Errorf("uploads = %q, want %q", fake.uploads, want)
+	}
+	if got := fake.created[0]["attachmentId[]"]; !slices.Equal(got, []string{"101"}) {
+		t.Errorf("attachmentId[] = %q, want [101]", got)
+	}
+}
+
+func TestBacklogSendDeduplicateAttachments(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+	bn.Deduplicate = true
+
+	if _, err := bn.Send(context.Background(), Message{Title: "夜間バッチの失敗"}); err != nil {
+		t.Fatalf("first Send: %v", err)
+	}
+	_, err := bn.Send(context.Background(), Message{
+		Title:       "夜間バッチの失敗",
+		Attachments: []Attachment{{Filename: "batch.log", Content: strings.NewReader("error: timeout")}},
+	})
+	if err != nil {
+		t.Fatalf("second Send: %v", err)
+	}
+	if len(fake.created) != 1 || len(fake.updated) != 1 {
+		t.Fatalf("created %d / updated %d issues, want 1 / 1", len(fake.created), len(fake.updated))
+	}
+	if got := fake.updated[0]["attachmentId[]"]; !slices.Equal(got, []string{"101"}) {
+		t.Errorf("attachmentId[] of the comment = %q, want [101]", got)
+	}
+}
+
+func TestBacklogSendRecordsSanitizedSummaryTruncation(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+
+	// 250 文字の件名は上限以内だが、絵文字をショートコード (:fire:) に変換すると上限を超える
+	title := strings.Repeat("あ", 250) + "🔥"
+	result, err := bn.Send(context.Background(), Message{Title: title})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	summary := fake.created[0].Get("summary")
+	if n := TextLength(summary); n != BacklogSummaryLimit {
+		t.Errorf("summary length = %d, want %d", n, BacklogSummaryLimit)
+	}
+	want := []Truncation{{Field: "summary", Length: 256, Limit: BacklogSummaryLimit}}
+	if !slices.Equal(result.Truncations, want) {
+		t.Errorf("Truncations = %v, want %v", result.Truncations, want)
+	}
+}
+
+func TestBacklogUpdateIssueRejectsLongSummary(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+
+	_, err := bn.UpdateIssue(context.Background(), "PROJ-1", BacklogIssueUpdate{Summary: strings.Repeat("あ", 250) + "🔥"})
+	if err == nil {
+		t.Fatal("UpdateIssue succeeded, want an error for the summary over the limit")
+	}
+	if len(fake.updated) != 0 {
+		t.Errorf("issue was updated %d times, want 0", len(fake.updated))
+	}
+}
+
+func TestBacklogEnsureIssueCommentsOnRepeat(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+	bn.Deduplicate = true
+	ctx := context.Background()
+
+	// 件名を部分的に含むだけの課題は、同じ事象とみなさない
+	if _, err := bn.SendIssue(ctx, "夜間バッチの失敗 (手動で登録)", "", 1); err != nil {
+		t.Fatalf("SendIssue: %v", err)
+	}
+	for i := range 3 {
+		result, err := bn.Send(ctx, Message{Title: "夜間バッチの失敗", Body: fmt.Sprintf("%d 回目の失敗", i+1)})
+		if err != nil {
+			t.Fatalf("Send #%d: %v", i+1, err)
+		}
+		if result.ID != "PROJ-2" {
+			t.Errorf("Send #%d ID = %q, want PROJ-2", i+1, result.ID)
+		}
+	}
+
+	if len(fake.created) != 2 {
+		t.Errorf("created %d issues, want 2", len(fake.created))
+	}
+	wantLine := fmt.Sprintf(backlogOccurrenceLine, IssueFingerprint("夜間バッチの失敗"), 1)
+	if got := fake.created[1].Get("description"); got != "1 回目の失敗\n\n"+wantLine {
+		t.Errorf("description = %q, want the message body followed by %q", got, wantLine)
+	}
+	search := fake.searches[0]
+	if search.Get("projectId[]") != "1" || search.Get("keyword") != "夜間バッチの失敗" {
+		t.Errorf("search query = %v, want projectId[]=1 and the summary as keyword", search)
+	}
+
+	comments := fake.comments["PROJ-2"]
+	if len(comments) != 2 {
+		t.Fatalf("got %d comments, want 2", len(comments))
+	}
+	for i, want := range []string{"再発を検知しました (発生回数: 2 回目)\n\n2 回目の失敗", "再発を検知しました (発生回数: 3 回目)\n\n3 回目の失敗"} {
+		if comments[i].Content != want {
+			t.Errorf("comment %d = %q, want %q", i+1, comments[i].Content, want)
+		}
+	}
+	// 発生回数は課題の詳細の行で更新し、本文は登録時のまま残す
+	if got, want := fake.updated[1].Get("description"), "1 回目の失敗\n\n"+fmt.Sprintf(backlogOccurrenceLine, IssueFingerprint("夜間バッチの失敗"), 3); got != want {
+		t.Errorf("updated description = %q, want %q", got, want)
+	}
+}
+
+func TestBacklogEnsureIssueClosed(t *testing.T) {
+	tests := []struct {
+		name        string
+		reopen      bool
+		wantKey     string
+		wantCreated bool
+	}{
+		{"再オープンしない場合は新しい課題を登録する", false, "PROJ-2", true},
+		{"再オープンする場合は未対応に戻してコメントする", true, "PROJ-1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bn, fake := newFakeBacklogNotifier(t)
+			ctx := context.Background()
+			opts := BacklogEnsureOptions{Reopen: tt.reopen}
+
+			if _, err := bn.EnsureIssue(ctx, "夜間バッチの失敗", "", 1, opts); err != nil {
+				t.Fatalf("first EnsureIssue: %v", err)
+			}
+			fake.close("PROJ-1")
+
+			result, err := bn.EnsureIssue(ctx, "夜間バッチの失敗", "", 1, opts)
+			if err != nil {
+				t.Fatalf("second EnsureIssue: %v", err)
+			}
+			if result.Issue.IssueKey != tt.wantKey || result.Created != tt.wantCreated || result.Reopened != tt.reopen {
+				t.Errorf("result = %+v, want key %s, created %v, reopened %v", result, tt.wantKey, tt.wantCreated, tt.reopen)
+			}
+			if tt.reopen {
+				if got := fake.updated[0].Get("statusId"); got != strconv.Itoa(backlogOpenStatusID) {
+					t.Errorf("statusId = %q, want %d", got, backlogOpenStatusID)
+				}
+				if result.Occurrences != 2 {
+					t.Errorf("Occurrences = %d, want 2", result.Occurrences)
+				}
+			}
+		})
+	}
+}
+
+func TestBacklogEnsureIssueFingerprintField(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+	fake.customFields = []BacklogCustomFieldResponse{{ID: 50, TypeID: CustomFieldText, Name: "フィンガープリント"}}
+	ctx := context.Background()
+	opts := BacklogEnsureOptions{FingerprintField: "フィンガープリント", Fingerprint: "batch.nightly"}
+
+	// 件名が異なっても、フィンガープリントが一致すれば同じ事象とみなす
+	first, err := bn.EnsureIssue(ctx, "夜間バッチの失敗 (10/16)", "", 1, opts)
+	if err != nil {
+		t.Fatalf("first EnsureIssue: %v", err)
+	}
+	second, err := bn.EnsureIssue(ctx, "夜間バッチの失敗 (10/17)", "", 1, opts)
+	if err != nil {
+		t.Fatalf("second EnsureIssue: %v", err)
+	}
+
+	if !first.Created || second.Created || second.Issue.IssueKey != first.Issue.IssueKey {
+		t.Errorf("results = %+v / %+v, want the second to comment on the first", first, second)
+	}
+	if got := fake.created[0].Get("customField_50"); got != "batch.nightly" {
+		t.Errorf("customField_50 = %q, want batch.nightly", got)
+	}
+	if got := fake.searches[1].Get("customField_50"); got != "batch.nightly" || fake.searches[1].Has("keyword") {
+		t.Errorf("search query = %v, want customField_50=batch.nightly without keyword", fake.searches[1])
+	}
+}
+
+func TestBacklogEnsureIssueFingerprintWithoutField(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+	ctx := context.Background()
+	opts := BacklogEnsureOptions{Fingerprint: "batch.nightly"}
+
+	// 別のフィンガープリント (前方一致する値) の課題は、同じ事象とみなさない
+	if _, err := bn.EnsureIssue(ctx, "夜間バッチの失敗 (別系統)", "", 1, BacklogEnsureOptions{Fingerprint: "batch.nightly.v2"}); err != nil {
+		t.Fatalf("EnsureIssue: %v", err)
+	}
+	first, err := bn.EnsureIssue(ctx, "夜間バッチの失敗 (10/16)", "ログ 1", 1, opts)
+	if err != nil {
+		t.Fatalf("first EnsureIssue: %v", err)
+	}
+	second, err := bn.EnsureIssue(ctx, "夜間バッチの失敗 (10/17)", "ログ 2", 1, opts)
+	if err != nil {
+		t.Fatalf("second EnsureIssue: %v", err)
+	}
+
+	if !first.Created || first.Issue.IssueKey != "PROJ-2" || second.Created || second.Issue.IssueKey != "PROJ-2" {
+		t.Errorf("results = %+v / %+v, want the second to comment on PROJ-2", first, second)
+	}
+	if second.Fingerprint != "batch.nightly" || second.Occurrences != 2 {
+		t.Errorf("result = %+v, want fingerprint batch.nightly and 2 occurrences", second)
+	}
+	if got := fake.searches[2].Get("keyword"); got != "batch.nightly" {
+		t.Errorf("keyword = %q, want the fingerprint", got)
+	}
+	if got, want := fake.updated[0].Get("description"), "ログ 1\n\ngo-notifier フィンガープリント: batch.nightly / 発生回数: 2"; got != want {
+		t.Errorf("updated description = %q, want %q", got, want)
+	}
+}
+
+func TestBacklogEnsureIssueOccurrencesFromDescription(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+	ctx := context.Background()
+
+	// 発生回数は課題の詳細の行から取得し、コメントの内容には依存しない
+	fake.issues = append(fake.issues, BacklogIssueResponse{
+		ID:          1,
+		ProjectID:   1,
+		IssueKey:    "PROJ-1",
+		Summary:     "夜間バッチの失敗",
+		Description: "手順書を参照\n\ngo-notifier フィンガープリント: batch.nightly / 発生回数: 41\n\n追記: 担当は山田",
+		Status:      &BacklogStatusResponse{ID: backlogOpenStatusID},
+	})
+	fake.addComment("PROJ-1", "再発を検知しました (発生回数: 7 回目)")
+
+	result, err := bn.EnsureIssue(ctx, "夜間バッチの失敗", "", 1, BacklogEnsureOptions{Fingerprint: "batch.nightly"})
+	if err != nil {
+		t.Fatalf("EnsureIssue: %v", err)
+	}
+	if result.Created || result.Occurrences != 42 {
+		t.Errorf("result = %+v, want 42 occurrences on the existing issue", result)
+	}
+	want := "手順書を参照\n\ngo-notifier フィンガープリント: batch.nightly / 発生回数: 42\n\n追記: 担当は山田"
+	if got := fake.updated[0].Get("description"); got != want {
+		t.Errorf("description = %q, want %q", got, want)
+	}
+}
+
+func TestBacklogEnsureIssueInvalidFingerprint(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+	fake.customFields = []BacklogCustomFieldResponse{{ID: 51, TypeID: CustomFieldNumeric, Name: "件数"}}
+	ctx := context.Background()
+
+	if _, err := bn.EnsureIssue(ctx, "夜間バッチの失敗", "", 1, BacklogEnsureOptions{Fingerprint: "batch nightly"}); err == nil {
+		t.Error("EnsureIssue with a fingerprint containing a space succeeded, want an error")
+	}
+	if _, err := bn.EnsureIssue(ctx, "夜間バッチの失敗", "", 1, BacklogEnsureOptions{FingerprintField: "件数"}); err == nil {
+		t.Error("EnsureIssue with a numeric FingerprintField succeeded, want an error")
+	}
+	if len(fake.created) != 0 {
+		t.Errorf("created %d issues, want 0", len(fake.created))
+	}
+}
+
+func TestBacklogPostCommentByIssueID(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+	ctx := context.Background()
+	if _, err := bn.SendIssue(ctx, "夜間バッチの失敗", "", 1); err != nil {
+		t.Fatalf("SendIssue: %v", err)
+	}
+
+	// 課題IDで指定しても、結果の課題キーと URL には課題キーを使用する
+	for _, idOrKey := range []string{"1", "PROJ-1"} {
+		result, err := bn.PostComment(ctx, idOrKey, "再実行しました")
+		if err != nil {
+			t.Fatalf("PostComment(%q): %v", idOrKey, err)
+		}
+		if result.IssueKey != "PROJ-1" || !strings.HasSuffix(result.URL, "/view/PROJ-1#comment-"+strconv.Itoa(result.ID)) {
+			t.Errorf("PostComment(%q) = %+v, want the issue key PROJ-1", idOrKey, result)
+		}
+	}
+	if len(fake.comments["PROJ-1"]) != 2 {
+		t.Errorf("got %d comments, want 2", len(fake.comments["PROJ-1"]))
+	}
+
+	if _, err := bn.PostComment(ctx, "99", "再実行しました"); err == nil {
+		t.Error("PostComment to a missing issue ID succeeded, want an error")
+	}
+}
+
+func TestResolveBacklogChoice(t *testing.T) {
+	choices := []backlogChoice{
+		{ID: 10, Name: "タスク"},
+		{ID: 11, Name: "Bug"},
+		{ID: 12, Name: "2024"},
+		{ID: 101, Name: "山田太郎", Alias: "yamada"},
+	}
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{"ID", "11", 11},
+		{"名前", "タスク", 10},
+		{"大文字小文字を区別しない", "bug", 11},
+		{"前後の空白を無視する", "  タスク ", 10},
+		{"ID に一致しない数字は名前で照合する", "2024", 12},
+		{"別名", "YAMADA", 101},
+		{"別名のある選択肢の名前", "山田太郎", 101},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveBacklogChoice("課題種別", tt.value, choices)
+			if err != nil || got != tt.want {
+				t.Errorf("resolveBacklogChoice(%q) = %d, %v; want %d", tt.value, got, err, tt.want)
+			}
+		})
+	}
+
+	_, err := resolveBacklogChoice("課題種別", "障害", choices)
+	want := `課題種別 "障害" が見つかりません (有効な値: タスク (ID: 10), Bug (ID: 11), 2024 (ID: 12), yamada / 山田太郎 (ID: 101))`
+	if err == nil || err.Error() != want {
+		t.Errorf("err = %v, want %q", err, want)
+	}
+}
+
+func TestBacklogSendIssueTypeAndPriority(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaults     [2]string // BacklogNotifier の IssueType, Priority
+		opts         BacklogIssueOptions
+		wantType     string
+		wantPriority string
+	}{
+		{"既定値", [2]string{}, BacklogIssueOptions{}, "10", "3"},
+		{"BacklogNotifier の設定", [2]string{"バグ", "高"}, BacklogIssueOptions{}, "11", "2"},
+		{"オプションが優先", [2]string{"バグ", "高"}, BacklogIssueOptions{IssueType: "タスク", Priority: "4"}, "10", "4"},
+		{"ID の指定はメタデータを参照しない", [2]string{}, BacklogIssueOptions{IssueTypeID: 99, PriorityID: 98}, "99", "98"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bn, fake := newFakeBacklogNotifier(t)
+			bn.IssueType, bn.Priority = tt.defaults[0], tt.defaults[1]
+			if _, err := bn.SendIssue(context.Background(), "夜間バッチの失敗", "", 1, tt.opts); err != nil {
+				t.Fatalf("SendIssue: %v", err)
+			}
+			form := fake.created[0]
+			if form.Get("issueTypeId") != tt.wantType || form.Get("priorityId") != tt.wantPriority {
+				t.Errorf("issueTypeId = %s, priorityId = %s; want %s, %s", form.Get("issueTypeId"), form.Get("priorityId"), tt.wantType, tt.wantPriority)
+			}
+		})
+	}
+}
+
+func TestBacklogSendIssueUnknownPriority(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+	_, err := bn.SendIssue(context.Background(), "夜間バッチの失敗", "", 1, BacklogIssueOptions{Priority: "緊急"})
+	if err == nil || !strings.Contains(err.Error(), "有効な値: 高 (ID: 2), 中 (ID: 3), 低 (ID: 4)") {
+		t.Errorf("err = %v, want it to list the valid priorities", err)
+	}
+	if len(fake.created) != 0 {
+		t.Errorf("created %d issues, want 0", len(fake.created))
+	}
+}
+
+func TestBacklogIssuePayloadValues(t *testing.T) {
+	payload := BacklogIssuePayload{
+		ProjectID:      1,
+		Summary:        "夜間バッチの失敗",
+		Description:    "詳細",
+		IssueTypeID:    10,
+		PriorityID:     3,
+		ParentIssueID:  5,
+		StartDate:      "2026-10-01",
+		DueDate:        "2026-10-31",
+		EstimatedHours: 1.5,
+		AssigneeID:     101,
+		CategoryIDs:    []int{201, 202},
+		VersionIDs:     []int{301},
+		MilestoneIDs:   []int{302},
+		AttachmentIDs:  []int{401},
+		CustomFields:   map[string][]string{"customField_50": {"batch.nightly"}, "customField_52": {"1", "2"}},
+	}
+	want := url.Values{
+		"projectId":      {"1"},
+		"summary":        {"夜間バッチの失敗"},
+		"description":    {"詳細"},
+		"issueTypeId":    {"10"},
+		"priorityId":     {"3"},
+		"parentIssueId":  {"5"},
+		"startDate":      {"2026-10-01"},
+		"dueDate":        {"2026-10-31"},
+		"estimatedHours": {"1.5"},
+		"assigneeId":     {"101"},
+		"categoryId[]":   {"201", "202"},
+		"versionId[]":    {"301"},
+		"milestoneId[]":  {"302"},
+		"attachmentId[]": {"401"},
+		"customField_50": {"batch.nightly"},
+		"customField_52": {"1", "2"},
+	}
+	if got := payload.Values(); got.Encode() != want.Encode() {
+		t.Errorf("Values() =\n%s\nwant:\n%s", got.Encode(), want.Encode())
+	}
+
+	// 任意の項目がゼロ値の場合は送信しない
+	minimal := BacklogIssuePayload{ProjectID: 1, Summary: "件名", IssueTypeID: 10, PriorityID: 3}
+	wantMinimal := url.Values{"projectId": {"1"}, "summary": {"件名"}, "description": {""}, "issueTypeId": {"10"}, "priorityId": {"3"}}
+	if got := minimal.Values(); got.Encode() != wantMinimal.Encode() {
+		t.Errorf("Values() = %s, want %s", got.Encode(), wantMinimal.Encode())
+	}
+}
+
+func TestBacklogSendIssueFields(t *testing.T) {
+	bn, fake := newFakeBacklogNotifier(t)
+	ctx := context.Background()
+	if _, err := bn.SendIssue(ctx, "親課題", "", 1); err != nil {
+		t.Fatalf("SendIssue: %v", err)
+	}
+
+	_, err := bn.SendIssue(ctx, "夜間バッチの失敗", "", 1, BacklogIssueOptions{
+		Assignee:       "sato",
+		StartDate:      "2026-10-16",
+		DueDate:        "2026-10-20",
+		EstimatedHours: 2,
+		Categories:     []string{"バッチ", "202"},
+		Milestones:     []string{"v2.0"},
+		Versions:       []string{"V1.0"},
+		ParentIssue:    "PROJ-1",
+	})
+	if err != nil {
+		t.Fatalf("SendIssue: %v", err)
+	}
+	form := fake.created[1]
+	for key, want := range map[string][]string{
+		"assigneeId":     {"102"},
+		"startDate":      {"2026-10-16"},
+		"dueDate":        {"2026-10-20"},
+		"estimatedHours": {"2"},
+		"categoryId[]":   {"201", "202"},
+		"milestoneId[]":  {"302"},
+		"versionId[]":    {"301"},
+		"parentIssueId":  {"1"},
+	} {
+		if got := form[key]; !slices.Equal(got, want) {
+			t.Errorf("%s = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestBacklogSendIssueInvalidFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    BacklogIssueOptions
+		wantErr string
+	}{
+		{"日付の形式", BacklogIssueOptions{DueDate: "2026/10/20"}, `期限日 "2026/10/20" の形式が不正です`},
+		{"予定時間", BacklogIssueOptions{EstimatedHours: -1}, "予定時間は 0 以上"},
+		{"担当者", BacklogIssueOptions{Assignee: "tanaka"}, "yamada / 山田太郎 (ID: 101)"},
+		{"カテゴリー", BacklogIssueOptions{Categories: []string{"UI"}}, `カテゴリー "UI" が見つかりません`},
+		// アーカイブ済みのバージョンは選択肢に含めない
+		{"アーカイブ済みのバージョン", BacklogIssueOptions{Versions: []string{"v0.9"}}, `発生バージョン "v0.9" が見つかりません (有効な値: v1.0 (ID: 301), v2.0 (ID: 302))`},
+		{"親課題", BacklogIssueOptions{ParentIssue: "PROJ-99"}, "親課題の取得に失敗"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bn, fake := newFakeBacklogNotifier(t)
+			_, err := bn.SendIssue(context.Background(), "夜間バッチの失敗", "", 1, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if len(fake.created) != 0 {
+				t.Errorf("created %d issues, want 0", len(fake.created))
+			}
+		})
+	}
+}
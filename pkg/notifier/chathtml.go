@@ -0,0 +1,204 @@
+package notifier
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// chatCodeColor は、Google Chat のカードでコードを表示する際の文字色です (カードの HTML には等幅フォントがないため)。
+const chatCodeColor = "#c5221f"
+
+// markdownToChatSections は、Markdown テキストを Google Chat のカードで使用できる HTML (b, i, s, a, font, br) に変換します。
+// 水平線でセクションを区切り、セクションごとにトップレベルのブロックの HTML の列を返します。
+func markdownToChatSections(markdown string) [][]string {
+	source := []byte(markdown)
+	doc := markdownParser.Parse(text.NewReader(source))
+
+	r := &chatHTMLRenderer{mrkdwnRenderer{source: source}}
+	sections := [][]string{nil}
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		if _, ok := n.(*ast.ThematicBreak); ok {
+			sections = append(sections, nil)
+			continue
+		}
+		if block := r.block(n, 0); block != "" {
+			sections[len(sections)-1] = append(sections[len(sections)-1], block)
+		}
+	}
+
+	// 空のセクション (先頭・末尾の水平線など) は除く
+	nonEmpty := sections[:0]
+	for _, s := range sections {
+		if len(s) > 0 {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+	return nonEmpty
+}
+
+// chatHTMLRenderer は、goldmark の AST を Google Chat のカードの HTML に変換するレンダラーです。
+// コードブロックの行の取得などの AST の読み取りは mrkdwnRenderer と共通です。
+type chatHTMLRenderer struct {
+	mrkdwnRenderer
+}
+
+// block は、ブロック要素を HTML に変換します。depth はリストの入れ子の深さです。
+func (r *chatHTMLRenderer) block(n ast.Node, depth int) string {
+	switch n := n.(type) {
+	case *ast.Paragraph, *ast.TextBlock:
+		return r.inlines(n)
+	case *ast.Heading:
+		if title := r.inlines(n); title != "" {
+			return "<b>" + title + "</b>"
+		}
+		return ""
+	case *ast.List:
+		return r.list(n, depth)
+	case *ast.Blockquote:
+		return "│ " + strings.ReplaceAll(r.children(n, depth, "<br>"), "<br>", "<br>│ ")
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		return chatCode(r.lines(n))
+	case *ast.ThematicBreak:
+		return "──────────"
+	case *ast.HTMLBlock:
+		return chatBreaks(html.EscapeString(strings.TrimRight(r.lines(n), "\n")))
+	case *east.Table:
+		// 表は mrkdwn と同じ形式で整形し、コードと同じ色のテキストとして描画する
+		return chatCode(strings.TrimSuffix(strings.TrimPrefix(html.UnescapeString(r.mrkdwnRenderer.table(n)), "```\n"), "\n```"))
+	default:
+		return r.children(n, depth, "<br><br>")
+	}
+}
+
+// children は、子ブロックを変換して sep で連結します。
+func (r *chatHTMLRenderer) children(n ast.Node, depth int, sep string) string {
+	var parts []string
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if s := r.block(c, depth); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// list は、箇条書き・番号付きリストを、入れ子の深さに応じたインデント付きで変換します。
+func (r *chatHTMLRenderer) list(n *ast.List, depth int) string {
+	indent := strings.Repeat("&nbsp;&nbsp;&nbsp;&nbsp;", depth)
+	number := n.Start
+	if number == 0 {
+		number = 1
+	}
+
+	var lines []string
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		marker := listBullets[depth%len(listBullets)]
+		if n.IsOrdered() {
+			marker = fmt.Sprintf("%d.", number)
+			number++
+		}
+
+		var body []string
+		for c := item.FirstChild(); c != nil; c = c.NextSibling() {
+			if nested, ok := c.(*ast.List); ok {
+				body = append(body, r.list(nested, depth+1))
+				continue
+			}
+			if s := r.block(c, depth+1); s != "" {
+				body = append(body, s)
+			}
+		}
+
+		if len(body) == 0 {
+			lines = append(lines, indent+marker)
+			continue
+		}
+		if _, nested := item.FirstChild().(*ast.List); nested {
+			lines = append(lines, indent+marker)
+			lines = append(lines, body...)
+			continue
+		}
+		lines = append(lines, indent+marker+" "+body[0])
+		lines = append(lines, body[1:]...)
+	}
+	return strings.Join(lines, "<br>")
+}
+
+// inlines は、インライン要素を HTML に変換します。
+func (r *chatHTMLRenderer) inlines(n ast.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		r.inline(&sb, c)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// inline は、1つのインライン要素を HTML として書き込みます。
+func (r *chatHTMLRenderer) inline(sb *strings.Builder, n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Text:
+		sb.WriteString(html.EscapeString(r.unescape(n.Segment.Value(r.source))))
+		if n.HardLineBreak() || n.SoftLineBreak() {
+			sb.WriteString("<br>")
+		}
+	case *ast.String:
+		sb.WriteString(html.EscapeString(string(n.Value)))
+	case *ast.CodeSpan:
+		sb.WriteString(chatCode(r.rawText(n)))
+	case *ast.Emphasis:
+		tag := "i"
+		if n.Level >= 2 {
+			tag = "b"
+		}
+		sb.WriteString("<" + tag + ">" + r.inlines(n) + "</" + tag + ">")
+	case *east.Strikethrough:
+		sb.WriteString("<s>" + r.inlines(n) + "</s>")
+	case *ast.Link:
+		sb.WriteString(chatLink(string(n.Destination), r.inlines(n)))
+	case *ast.AutoLink:
+		url := string(n.URL(r.source))
+		if n.AutoLinkType == ast.AutoLinkEmail && !strings.HasPrefix(url, "mailto:") {
+			url = "mailto:" + url
+		}
+		sb.WriteString(chatLink(url, html.EscapeString(string(n.Label(r.source)))))
+	case *ast.Image:
+		sb.WriteString(chatLink(string(n.Destination), r.inlines(n)))
+	case *east.TaskCheckBox:
+		if n.IsChecked {
+			sb.WriteString("☑ ")
+		} else {
+			sb.WriteString("☐ ")
+		}
+	case *ast.RawHTML:
+		for i := 0; i < n.Segments.Len(); i++ {
+			seg := n.Segments.At(i)
+			sb.WriteString(html.EscapeString(string(seg.Value(r.source))))
+		}
+	default:
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			r.inline(sb, c)
+		}
+	}
+}
+
+// chatLink は、URL と表示テキスト (HTML) からリンクを生成します。
+func chatLink(url, label string) string {
+	if label == "" {
+		label = html.EscapeString(url)
+	}
+	return `<a href="` + html.EscapeString(url) + `">` + label + "</a>"
+}
+
+// chatCode は、コードを色付きのテキストとして描画します。改行は <br> に変換します。
+func chatCode(code string) string {
+	return `<font color="` + chatCodeColor + `">` + chatBreaks(html.EscapeString(code)) + "</font>"
+}
+
+// chatBreaks は、改行を <br> に変換します。
+func chatBreaks(s string) string {
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
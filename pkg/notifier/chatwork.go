@@ -0,0 +1,310 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// DefaultChatworkAPIURL は Chatwork API v2 のベース URL です。
+const DefaultChatworkAPIURL = "https://api.chatwork.com/v2"
+
+// ChatworkNotifier は Chatwork API v2 を使用して、ルームにメッセージやタスクを投稿するためのクライアントです。
+// Notifier インターフェースを実装します。
+type ChatworkNotifier struct {
+	// RoomID: 投稿先のルーム ID
+	RoomID string
+	// To: メッセージの先頭で宛先 ([To:accountId]) に指定するアカウント ID
+	To []int
+	// APIURL: API のベース URL (空の場合は DefaultChatworkAPIURL。テスト用のサーバーなどを指定できます)
+	APIURL string
+	// httpClient: 汎用クライアント (リトライロジックを含む)
+	client   httpkit.Client
+	apiToken string
+}
+
+// ChatworkTaskOptions は、タスクの担当者と期限の指定です。
+type ChatworkTaskOptions struct {
+	// AssigneeIDs: 担当者のアカウント ID (1人以上必須)
+	AssigneeIDs []int
+	// Due: 期限 (ゼロ値の場合は期限なし)
+	Due time.Time
+	// DueHasTime: true の場合は Due の時刻まで、false の場合は Due の日付を期限とします
+	DueHasTime bool
+}
+
+// ChatworkMessageResponse はメッセージの投稿APIのレスポンスです。
+type ChatworkMessageResponse struct {
+	MessageID string `json:"message_id"`
+}
+
+// ChatworkTaskResponse はタスクの追加APIのレスポンスです。
+type ChatworkTaskResponse struct {
+	TaskIDs []int `json:"task_ids"`
+}
+
+// ChatworkErrorResponse は Chatwork API が返すエラーのレスポンスです。
+type ChatworkErrorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// ChatworkError は Chatwork API から返されるエラーを表すカスタムエラーです。
+type ChatworkError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ChatworkError) Error() string {
+	return fmt.Sprintf("Chatwork API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// NewChatworkNotifier は ChatworkNotifier を初期化します。
+func NewChatworkNotifier(client httpkit.Client, apiToken, roomID string) (*ChatworkNotifier, error) {
+	if apiToken == "" || roomID == "" {
+		return nil, errors.New("CHATWORK_API_TOKEN および投稿先のルーム ID の設定が必要です")
+	}
+	return &ChatworkNotifier{
+		RoomID:   roomID,
+		client:   client,
+		apiToken: apiToken,
+	}, nil
+}
+
+// --- Notifier インターフェース実装 ---
+
+// Send は、Message を Chatwork のメッセージ記法 ([info][title]...[/title]...[/info]) に変換して投稿します。
+// Title はタイトル、Body は本文、Fields は「名前: 値」の行、Links は「テキスト: URL」の行、
+// Severity・Tags・Labels は末尾の行として描画されます。To が設定されている場合は、先頭に [To:accountId] を付与します。
+// ファイルのアップロードには対応していないため、Attachments は無視されます。
+// SendResult の ID にはメッセージ ID、Channel にはルーム ID、URL にはメッセージの URL が設定されます。
+func (c *ChatworkNotifier) Send(ctx context.Context, msg Message) (*SendResult, error) {
+	header := msg.Title
+	if header == "" {
+		header = defaultHeader(msg.Body)
+	}
+
+	resp, err := c.PostMessage(ctx, ChatworkInfo(header, chatworkMessageBody(msg)))
+	if err != nil {
+		return nil, err
+	}
+	return &SendResult{Backend: "chatwork", ID: resp.MessageID, URL: c.MessageURL(resp.MessageID), Channel: c.RoomID}, nil
+}
+
+// SendTextWithHeader は、ヘッダー付きのテキストメッセージを [info][title] 記法に変換して投稿します。
+func (c *ChatworkNotifier) SendTextWithHeader(ctx context.Context, headerText string, message string) error {
+	_, err := c.PostMessage(ctx, ChatworkInfo(headerText, chatworkBody(message)))
+	return err
+}
+
+// SendText は、プレーンテキストメッセージを通知します。（ヘッダーなし）
+// 本文の1行目からデフォルトヘッダーを生成し、SendTextWithHeader にフォールバックします。
+func (c *ChatworkNotifier) SendText(ctx context.Context, message string) error {
+	return c.SendTextWithHeader(ctx, defaultHeader(message), message)
+}
+
+// PostMessage は、Chatwork の記法で記述済みのメッセージをルームに投稿します。
+// To が設定されている場合は、メッセージの先頭に [To:accountId] を付与します。
+func (c *ChatworkNotifier) PostMessage(ctx context.Context, body string) (*ChatworkMessageResponse, error) {
+	form := url.Values{}
+	form.Set("body", ChatworkMentions(c.To)+body)
+
+	respBody, err := c.postForm(ctx, "/rooms/"+url.PathEscape(c.RoomID)+"/messages", form)
+	if err != nil {
+		return nil, fmt.Errorf("Chatworkへのメッセージの投稿に失敗: %w", err)
+	}
+
+	var resp ChatworkMessageResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("Chatworkのメッセージの投稿結果のパースに失敗しました: %w", err)
+	}
+	return &resp, nil
+}
+
+// CreateTask は、ルームにタスクを追加し、追加したタスクの ID を返します。
+// 担当者ごとに1つのタスクが作成されます。
+func (c *ChatworkNotifier) CreateTask(ctx context.Context, body string, opts ChatworkTaskOptions) (*ChatworkTaskResponse, error) {
+	if len(opts.AssigneeIDs) == 0 {
+		return nil, errors.New("タスクの担当者のアカウント ID を1つ以上指定してください")
+	}
+
+	ids := make([]string, 0, len(opts.AssigneeIDs))
+	for _, id := range opts.AssigneeIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+	form := url.Values{}
+	form.Set("body", body)
+	form.Set("to_ids", strings.Join(ids, ","))
+	switch {
+	case opts.Due.IsZero():
+		form.Set("limit_type", "none")
+	case opts.DueHasTime:
+		form.Set("limit", strconv.FormatInt(opts.Due.Unix(), 10))
+		form.Set("limit_type", "time")
+	default:
+		form.Set("limit", strconv.FormatInt(opts.Due.Unix(), 10))
+		form.Set("limit_type", "date")
+	}
+
+	respBody, err := c.postForm(ctx, "/rooms/"+url.PathEscape(c.RoomID)+"/tasks", form)
+	if err != nil {
+		return nil, fmt.Errorf("Chatworkへのタスクの追加に失敗: %w", err)
+	}
+
+	var resp ChatworkTaskResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("Chatworkのタスクの追加結果のパースに失敗しました: %w", err)
+	}
+	return &resp, nil
+}
+
+// MessageURL は、ルームのメッセージの URL を返します。
+func (c *ChatworkNotifier) MessageURL(messageID string) string {
+	if messageID == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://www.chatwork.com/#!rid%s-%s", c.RoomID, messageID)
+}
+
+// postForm は、指定されたエンドポイントへ X-ChatWorkToken ヘッダー付きのフォーム形式のリクエストを送信し、
+// レスポンスのボディを返します。
+func (c *ChatworkNotifier) postForm(ctx context.Context, endpoint string, form url.Values) ([]byte, error) {
+	baseURL := strings.TrimRight(c.APIURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultChatworkAPIURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create POST request for Chatwork: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-ChatWorkToken", c.apiToken)
+
+	respBody, err := c.client.DoRequest(req)
+	if err != nil {
+		return nil, chatworkAPIError(err)
+	}
+	return respBody, nil
+}
+
+// chatworkAPIError は、httpkit.DoRequest から返された 4xx のエラーを Chatwork 固有のエラーに変換します。
+func chatworkAPIError(err error) error {
+	var nonRetryable *httpkit.NonRetryableHTTPError
+	if !errors.As(err, &nonRetryable) {
+		// 5xx またはネットワークエラー
+		return err
+	}
+
+	var errorResp ChatworkErrorResponse
+	if json.Unmarshal(nonRetryable.Body, &errorResp) == nil && len(errorResp.Errors) > 0 {
+		return &ChatworkError{StatusCode: nonRetryable.StatusCode, Message: strings.Join(errorResp.Errors, ", ")}
+	}
+	return &ChatworkError{StatusCode: nonRetryable.StatusCode, Message: fmt.Sprintf("Raw Response: %s", string(nonRetryable.Body))}
+}
+
+// --- メッセージ記法への変換 ---
+
+// ChatworkInfo は、タイトルと本文を Chatwork の [info][title]...[/title]...[/info] 記法に変換します。
+func ChatworkInfo(title, body string) string {
+	return "[info][title]" + title + "[/title]" + body + "[/info]"
+}
+
+// ChatworkMentions は、アカウント ID を宛先 ([To:accountId]) の記法に変換します。宛先がある場合は末尾に改行を付与します。
+func ChatworkMentions(accountIDs []int) string {
+	if len(accountIDs) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for _, id := range accountIDs {
+		fmt.Fprintf(&sb, "[To:%d]", id)
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// chatworkMessageBody は、Message の本文と Fields / Links / Tags / Labels を Chatwork の本文に変換します。
+func chatworkMessageBody(msg Message) string {
+	parts := []string{chatworkBody(msg.Body)}
+
+	var extras []string
+	for _, f := range msg.Fields {
+		extras = append(extras, fmt.Sprintf("%s: %s", f.Name, f.Value))
+	}
+	for _, l := range msg.Links {
+		if l.Text == "" {
+			extras = append(extras, l.URL)
+			continue
+		}
+		extras = append(extras, fmt.Sprintf("%s: %s", l.Text, l.URL))
+	}
+	if len(extras) > 0 {
+		parts = append(parts, "[hr]"+strings.Join(extras, "\n"))
+	}
+
+	var meta []string
+	if msg.Severity != "" {
+		meta = append(meta, fmt.Sprintf("%s %s", msg.Severity.Emoji(), msg.Severity))
+	}
+	if len(msg.Tags) > 0 {
+		meta = append(meta, strings.Join(msg.Tags, ", "))
+	}
+	for _, k := range sortedLabelKeys(msg.Labels) {
+		meta = append(meta, fmt.Sprintf("%s: %s", k, msg.Labels[k]))
+	}
+	if len(meta) > 0 {
+		parts = append(parts, strings.Join(meta, " | "))
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// chatworkBody は、Markdown の本文を Chatwork の記法に変換します。
+// Chatwork は Markdown に対応していないため、コードブロックは [code] に、水平線は [hr] に変換し、見出しの記号を取り除きます。
+// それ以外のテキストはそのまま送信します。
+func chatworkBody(message string) string {
+	var lines []string
+	inFence := false
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), codeFence) {
+			if inFence {
+				lines = append(lines, "[/code]")
+			} else {
+				lines = append(lines, "[code]")
+			}
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			lines = append(lines, line)
+			continue
+		}
+
+		switch {
+		case markdownRuleRegex.MatchString(line):
+			lines = append(lines, "[hr]")
+		case markdownHeadingRegex.MatchString(line):
+			lines = append(lines, markdownHeadingRegex.FindStringSubmatch(line)[1])
+		default:
+			lines = append(lines, line)
+		}
+	}
+	if inFence {
+		lines = append(lines, "[/code]")
+	}
+
+	// [code] と [hr] はそれ自体が改行を伴うため、前後の改行を詰める
+	body := strings.Join(lines, "\n")
+	for _, tag := range []string{"[code]", "[/code]", "[hr]"} {
+		body = strings.ReplaceAll(body, tag+"\n", tag)
+		body = strings.ReplaceAll(body, "\n"+tag, tag)
+	}
+	return strings.TrimSpace(body)
+}
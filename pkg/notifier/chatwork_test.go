@@ -0,0 +1,177 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// chatworkRequest は、テスト用のサーバーが受け取ったリクエストです。
+type chatworkRequest struct {
+	path  string
+	token string
+	form  url.Values
+}
+
+// newTestChatworkNotifier は、httptest のサーバーを API の URL とする ChatworkNotifier を生成します。
+// サーバーはリクエストを requests に記録し、メッセージ ID 1234、タスク ID 11, 12 を返します。
+func newTestChatworkNotifier(t *testing.T, requests *[]chatworkRequest) *ChatworkNotifier {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		*requests = append(*requests, chatworkRequest{path: r.URL.Path, token: r.Header.Get("X-ChatWorkToken"), form: r.PostForm})
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/rooms/100/messages":
+			w.Write([]byte(`{"message_id":"1234"}`))
+		case "/v2/rooms/100/tasks":
+			w.Write([]byte(`{"task_ids":[11,12]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":["Invalid Endpoint or HTTP method"]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := httpkit.New(5*time.Second, httpkit.WithMaxRetries(0))
+	cn, err := NewChatworkNotifier(*client, "test-token", "100")
+	if err != nil {
+		t.Fatalf("NewChatworkNotifier: %v", err)
+	}
+	cn.APIURL = server.URL + "/v2/"
+	return cn
+}
+
+func TestChatworkSend(t *testing.T) {
+	var requests []chatworkRequest
+	cn := newTestChatworkNotifier(t, &requests)
+	cn.To = []int{111, 222}
+
+	result, err := cn.Send(context.Background(), Message{
+		Title:    "バッチ失敗",
+		Body:     "## 概要\n夜間バッチが失敗しました",
+		Severity: SeverityError,
+		Fields:   []Field{{Name: "ジョブ", Value: "nightly"}},
+		Links:    []Link{{Text: "ログ", URL: "https://example.com/log"}, {URL: "https://example.com/run"}},
+		Tags:     []string{"batch"},
+		Labels:   map[string]string{"env": "prod"},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.ID != "1234" || result.Channel != "100" || result.URL != "https://www.chatwork.com/#!rid100-1234" {
+		t.Errorf("result = %+v", result)
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("got %d requests", len(requests))
+	}
+	req := requests[0]
+	if req.path != "/v2/rooms/100/messages" || req.token != "test-token" {
+		t.Errorf("request = %s (token %q)", req.path, req.token)
+	}
+	want := "[To:111][To:222]\n" +
+		"[info][title]バッチ失敗[/title]概要\n夜間バッチが失敗しました\n" +
+		"[hr]ジョブ: nightly\nログ: https://example.com/log\nhttps://example.com/run\n" +
+		"🚨 error | batch | env: prod[/info]"
+	if got := req.form.Get("body"); got != want {
+		t.Errorf("body =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestChatworkBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"そのまま", "1行目\n2行目", "1行目\n2行目"},
+		{"見出しの記号を取り除く", "# 見出し ##\n本文", "見出し\n本文"},
+		{"水平線", "前\n---\n後", "前[hr]後"},
+		{"コードブロック", "実行:\n```bash\n## コメント\n---\n```\n結果", "実行:[code]## コメント\n---[/code]結果"},
+		{"閉じていないコードブロック", "```\nexit 1", "[code]exit 1[/code]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chatworkBody(tt.message); got != tt.want {
+				t.Errorf("chatworkBody(%q) = %q, want %q", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatworkCreateTask(t *testing.T) {
+	due := time.Date(2024, 4, 1, 18, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		opts          ChatworkTaskOptions
+		wantLimitType string
+		wantLimit     string
+	}{
+		{"期限なし", ChatworkTaskOptions{AssigneeIDs: []int{111, 222}}, "none", ""},
+		{"日付", ChatworkTaskOptions{AssigneeIDs: []int{111, 222}, Due: due}, "date", strconv.FormatInt(due.Unix(), 10)},
+		{"時刻", ChatworkTaskOptions{AssigneeIDs: []int{111, 222}, Due: due, DueHasTime: true}, "time", strconv.FormatInt(due.Unix(), 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []chatworkRequest
+			cn := newTestChatworkNotifier(t, &requests)
+			cn.To = []int{999} // タスクの本文には宛先を付与しない
+
+			resp, err := cn.CreateTask(context.Background(), "障害対応", tt.opts)
+			if err != nil {
+				t.Fatalf("CreateTask: %v", err)
+			}
+			if len(resp.TaskIDs) != 2 || resp.TaskIDs[0] != 11 {
+				t.Errorf("TaskIDs = %v", resp.TaskIDs)
+			}
+
+			req := requests[0]
+			if req.path != "/v2/rooms/100/tasks" || req.token != "test-token" {
+				t.Errorf("request = %s (token %q)", req.path, req.token)
+			}
+			if req.form.Get("body") != "障害対応" || req.form.Get("to_ids") != "111,222" {
+				t.Errorf("form = %v", req.form)
+			}
+			if req.form.Get("limit_type") != tt.wantLimitType || req.form.Get("limit") != tt.wantLimit {
+				t.Errorf("limit_type = %q, limit = %q; want %q, %q", req.form.Get("limit_type"), req.form.Get("limit"), tt.wantLimitType, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestChatworkCreateTaskRequiresAssignee(t *testing.T) {
+	var requests []chatworkRequest
+	cn := newTestChatworkNotifier(t, &requests)
+	if _, err := cn.CreateTask(context.Background(), "障害対応", ChatworkTaskOptions{}); err == nil {
+		t.Fatal("CreateTask succeeded without an assignee")
+	}
+	if len(requests) != 0 {
+		t.Errorf("sent %d requests without an assignee", len(requests))
+	}
+}
+
+func TestChatworkAPIError(t *testing.T) {
+	var requests []chatworkRequest
+	cn := newTestChatworkNotifier(t, &requests)
+	cn.RoomID = "999"
+
+	_, err := cn.PostMessage(context.Background(), "本文")
+	var cwErr *ChatworkError
+	if !errors.As(err, &cwErr) {
+		t.Fatalf("err = %v, want a *ChatworkError", err)
+	}
+	if cwErr.StatusCode != http.StatusNotFound || cwErr.Message != "Invalid Endpoint or HTTP method" {
+		t.Errorf("error = %+v", cwErr)
+	}
+}
@@ -0,0 +1,335 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// Discord のレート制限 (429 Too Many Requests) への対応の既定値
+const (
+	// defaultDiscordRateLimitRetries: レート制限による再送の既定の最大回数
+	defaultDiscordRateLimitRetries = 3
+	// maxDiscordRetryAfter: 待機する retry_after の上限。これより長い待機を求められた場合は再送せずにエラーを返す
+	maxDiscordRetryAfter = time.Minute
+)
+
+// DiscordNotifier は Discord の Webhook と連携するためのクライアントです。
+// Notifier インターフェースを実装します。メッセージは埋め込み (embed) として投稿されます。
+type DiscordNotifier struct {
+	// WebhookURL: Discord の Webhook URL (https://discord.com/api/webhooks/{id}/{token})
+	WebhookURL string
+	// httpClient: 汎用クライアント (リトライロジックを含む)
+	client httpkit.Client
+	// Username: Webhook の既定の名前を上書きするユーザー名
+	Username string
+	// AvatarURL: Webhook の既定のアバターを上書きする画像の URL
+	AvatarURL string
+	// MaxRateLimitRetries: レート制限 (429) を受けた場合に再送する最大回数 (0 の場合は 3)
+	MaxRateLimitRetries int
+}
+
+// NewDiscordNotifier は DiscordNotifier の新しいインスタンスを作成します。
+func NewDiscordNotifier(client httpkit.Client, webhookURL string) (*DiscordNotifier, error) {
+	if webhookURL == "" {
+		return nil, errors.New("Discord への投稿には Webhook URL の設定が必要です")
+	}
+	return &DiscordNotifier{
+		WebhookURL: webhookURL,
+		client:     client,
+	}, nil
+}
+
+// discordPayload は Discord の Webhook の実行 (Execute Webhook) のリクエストです。
+type discordPayload struct {
+	Username  string         `json:"username,omitempty"`
+	AvatarURL string         `json:"avatar_url,omitempty"`
+	Embeds    []discordEmbed `json:"embeds"`
+}
+
+// discordEmbed は Discord の埋め込みです。
+type discordEmbed struct {
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color,omitempty"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Footer      *discordEmbedFooter `json:"footer,omitempty"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+}
+
+// discordEmbedField は埋め込みのフィールドです。
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// discordEmbedFooter は埋め込みのフッターです。
+type discordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// discordMessageResponse は、wait=true で Webhook を実行した場合に返される、投稿したメッセージです。
+type discordMessageResponse struct {
+	ID        string `json:"id"`
+	ChannelID string `json:"channel_id"`
+}
+
+// discordRateLimitResponse は、レート制限 (429) のレスポンスのボディです。
+type discordRateLimitResponse struct {
+	Message string `json:"message"`
+	// RetryAfter: 再送までに待機する秒数 (小数を含む)
+	RetryAfter float64 `json:"retry_after"`
+	Global     bool    `json:"global"`
+}
+
+// --- Notifier インターフェース実装 ---
+
+// Send は、Message を Discord の埋め込みに変換して投稿します。
+// Title は埋め込みのタイトル、Body は説明、Severity は色、Fields はフィールド、Links は「リンク」フィールド、
+// Severity と Tags と Labels はフッターとして描画されます。ファイルのアップロードには対応していないため、Attachments は無視されます。
+// SendResult の ID と Channel には、投稿したメッセージの ID とチャンネル ID が設定されます。
+// Discord の上限を超えたために切り詰めた (または省略した) 項目は、SendResult の Truncations に記録されます。
+func (d *DiscordNotifier) Send(ctx context.Context, msg Message) (*SendResult, error) {
+	var rec truncationRecorder
+	header := msg.Title
+	if header == "" {
+		header = defaultHeader(msg.Body)
+	}
+
+	embeds := buildDiscordEmbeds(header, msg.Body, discordMessageFields(msg, &rec), discordFooter(msg), severityOrDefault(msg.Severity), &rec)
+	resp, err := d.post(ctx, embeds, &rec)
+	if err != nil {
+		return nil, err
+	}
+	return &SendResult{Backend: "discord", ID: resp.ID, Channel: resp.ChannelID, Truncations: rec.truncations}, nil
+}
+
+// SendTextWithHeader は、ヘッダー付きのテキストメッセージを埋め込みに変換して投稿します。
+// headerText は埋め込みのタイトルとして、message は説明 (Markdown として解釈可能) として描画されます。
+func (d *DiscordNotifier) SendTextWithHeader(ctx context.Context, headerText string, message string) error {
+	_, err := d.post(ctx, buildDiscordEmbeds(headerText, message, nil, "", SeverityInfo, nil), nil)
+	return err
+}
+
+// SendText は、プレーンテキストメッセージを通知します。（ヘッダーなし）
+// 本文の1行目からデフォルトヘッダーを生成し、SendTextWithHeader にフォールバックします。
+func (d *DiscordNotifier) SendText(ctx context.Context, message string) error {
+	return d.SendTextWithHeader(ctx, defaultHeader(message), message)
+}
+
+// post は、埋め込みを Webhook で送信し、投稿したメッセージを返します。
+// レート制限 (429) を受けた場合は、レスポンスの retry_after の秒数だけ待機してから、MaxRateLimitRetries 回まで再送します。
+func (d *DiscordNotifier) post(ctx context.Context, embeds []discordEmbed, rec *truncationRecorder) (*discordMessageResponse, error) {
+	// wait=true を指定すると、Discord は投稿したメッセージ (ID を含む) を返す
+	endpoint, err := url.Parse(d.WebhookURL)
+	if err != nil {
+		return nil, fmt.Errorf("Discord の Webhook URL が不正です: %w", err)
+	}
+	query := endpoint.Query()
+	query.Set("wait", "true")
+	endpoint.RawQuery = query.Encode()
+
+	payload := discordPayload{
+		Username:  rec.truncate("username", d.Username, DiscordUsernameLimit),
+		AvatarURL: d.AvatarURL,
+		Embeds:    embeds,
+	}
+
+	for attempt := 0; ; attempt++ {
+		respBody, err := d.client.PostJSONAndFetchBytes(endpoint.String(), payload, ctx)
+		if err == nil {
+			var resp discordMessageResponse
+			if err := json.Unmarshal(respBody, &resp); err != nil {
+				return nil, fmt.Errorf("Discord Webhookの送信結果のパースに失敗しました: %w", err)
+			}
+			return &resp, nil
+		}
+
+		wait, limited := discordRetryAfter(err)
+		if !limited || attempt >= d.maxRateLimitRetries() {
+			return nil, fmt.Errorf("Discord Webhookメッセージの送信に失敗しました: %w", err)
+		}
+		if wait > maxDiscordRetryAfter {
+			return nil, fmt.Errorf("Discord のレート制限により送信できません (再送まで %s): %w", wait, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("Discord のレート制限による再送の待機中に中断されました: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// maxRateLimitRetries は、レート制限を受けた場合に再送する最大回数を返します。
+func (d *DiscordNotifier) maxRateLimitRetries() int {
+	if d.MaxRateLimitRetries > 0 {
+		return d.MaxRateLimitRetries
+	}
+	return defaultDiscordRateLimitRetries
+}
+
+// discordRetryAfter は、エラーがレート制限 (429) によるものであれば、再送までに待機する時間を返します。
+// httpkit は 4xx のレスポンスを再送しないため、ボディの retry_after を読み取って呼び出し元で再送します。
+func discordRetryAfter(err error) (time.Duration, bool) {
+	var httpErr *httpkit.NonRetryableHTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	var limit discordRateLimitResponse
+	if err := json.Unmarshal(httpErr.Body, &limit); err != nil || limit.RetryAfter <= 0 {
+		// retry_after を読み取れない場合は1秒待機する
+		return time.Second, true
+	}
+	return time.Duration(limit.RetryAfter * float64(time.Second)), true
+}
+
+// --- 埋め込みの構築 ---
+
+// discordSeverityColor は、重要度に対応する埋め込みの色を返します。
+func discordSeverityColor(s Severity) int {
+	switch s {
+	case SeveritySuccess:
+		return 0x2EB67D
+	case SeverityWarning:
+		return 0xECB22E
+	case SeverityError:
+		return 0xE01E5A
+	case SeverityCritical:
+		return 0x8B0000
+	default:
+		return 0x1D9BD1
+	}
+}
+
+// buildDiscordEmbeds は、タイトル・本文・フィールド・フッターから、1メッセージに収まる埋め込みの列を構築します。
+// 本文は説明の上限 (4096 文字) ごとに複数の埋め込みに分割され、タイトルは最初の埋め込みに、フィールドとフッターは最後の埋め込みに付与されます。
+// すべての埋め込みのテキストの合計が上限 (6000 文字) を超える場合は、フィールドを優先して残し、本文を切り詰めます
+// (タイトル・フッター・フィールドだけで上限を超える場合は、収まらないフィールドを省略します)。
+// 切り詰めや省略は rec に記録されます (rec は nil でも構いません)。
+func buildDiscordEmbeds(headerText, message string, fields []discordEmbedField, footerText string, severity Severity, rec *truncationRecorder) []discordEmbed {
+	title := rec.truncate("title", headerText, DiscordTitleLimit)
+	footerText = rec.truncate("footer", footerText, DiscordFooterLimit)
+
+	// 本文に割り当てられる文字数は、合計の上限からタイトル・フッター・フィールドの分を除いたもの
+	budget := DiscordEmbedTotalLimit - TextLength(title) - TextLength(footerText)
+	fieldLength := 0
+	for i, f := range fields {
+		n := TextLength(f.Name) + TextLength(f.Value)
+		if fieldLength+n > budget {
+			rec.record("field", len(fields), i)
+			fields = fields[:i]
+			break
+		}
+		fieldLength += n
+	}
+	budget -= fieldLength
+
+	// 説明を分割するとコードブロックのフェンスを閉じて開き直すため、その分の余白を確保する
+	if TextLength(message) > DiscordDescriptionLimit {
+		budget -= 2 * (len(codeFence) + 1)
+	}
+	message = rec.truncate("description", message, max(budget, 0))
+
+	var descriptions []string
+	if message != "" {
+		descriptions = splitLines(message, DiscordDescriptionLimit, true)
+	}
+	if len(descriptions) > DiscordMaxEmbeds {
+		rec.record("embed", len(descriptions), DiscordMaxEmbeds)
+		descriptions = descriptions[:DiscordMaxEmbeds]
+	}
+	if len(descriptions) == 0 {
+		descriptions = []string{""}
+	}
+
+	color := discordSeverityColor(severity)
+	embeds := make([]discordEmbed, 0, len(descriptions))
+	for i, d := range descriptions {
+		embed := discordEmbed{Description: d, Color: color}
+		if i == 0 {
+			embed.Title = title
+		}
+		if i == len(descriptions)-1 {
+			embed.Fields = fields
+			if footerText != "" {
+				embed.Footer = &discordEmbedFooter{Text: footerText}
+			}
+			embed.Timestamp = time.Now().Format(time.RFC3339)
+		}
+		embeds = append(embeds, embed)
+	}
+	return embeds
+}
+
+// discordMessageFields は、Message の Fields と Links を埋め込みのフィールドに変換します。
+// Links は Markdown のリンクの一覧として、1つの「リンク」フィールドにまとめます。
+func discordMessageFields(msg Message, rec *truncationRecorder) []discordEmbedField {
+	// Links のフィールドを省略しないよう、Fields はその分を除いた数までとする
+	maxFields := DiscordMaxFields
+	if len(msg.Links) > 0 {
+		maxFields--
+	}
+	msgFields := msg.Fields
+	if len(msgFields) > maxFields {
+		rec.record("field", len(msgFields), maxFields)
+		msgFields = msgFields[:maxFields]
+	}
+
+	var fields []discordEmbedField
+	for _, f := range msgFields {
+		fields = append(fields, discordEmbedField{
+			Name:   rec.truncate("field", nonEmptyDiscordText(f.Name), DiscordFieldNameLimit),
+			Value:  rec.truncate("field", nonEmptyDiscordText(f.Value), DiscordFieldValueLimit),
+			Inline: true,
+		})
+	}
+
+	if len(msg.Links) > 0 {
+		links := make([]string, 0, len(msg.Links))
+		for _, l := range msg.Links {
+			text := l.Text
+			if text == "" {
+				text = l.URL
+			}
+			links = append(links, fmt.Sprintf("[%s](%s)", text, l.URL))
+		}
+		fields = append(fields, discordEmbedField{
+			Name:  "リンク",
+			Value: rec.truncate("links", strings.Join(links, "\n"), DiscordFieldValueLimit),
+		})
+	}
+	return fields
+}
+
+// nonEmptyDiscordText は、Discord が空文字列を受け付けない項目のために、空の値を "-" に置き換えます。
+func nonEmptyDiscordText(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
+
+// discordFooter は、Message の Severity / Tags / Labels をフッターのテキストに変換します。
+func discordFooter(msg Message) string {
+	var meta []string
+	if msg.Severity != "" {
+		meta = append(meta, fmt.Sprintf("%s %s", msg.Severity.Emoji(), msg.Severity))
+	}
+	if len(msg.Tags) > 0 {
+		meta = append(meta, strings.Join(msg.Tags, ", "))
+	}
+	for _, k := range sortedLabelKeys(msg.Labels) {
+		meta = append(meta, fmt.Sprintf("%s: %s", k, msg.Labels[k]))
+	}
+	return strings.Join(meta, " | ")
+}
@@ -0,0 +1,329 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// newTestDiscordNotifier は、httptest のサーバーを Webhook URL とする DiscordNotifier を生成します。
+func newTestDiscordNotifier(t *testing.T, handler http.Handler) *DiscordNotifier {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := httpkit.New(5*time.Second, httpkit.WithMaxRetries(0))
+	dn, err := NewDiscordNotifier(*client, server.URL+"/api/webhooks/1/token")
+	if err != nil {
+		t.Fatalf("NewDiscordNotifier: %v", err)
+	}
+	return dn
+}
+
+// discordRateLimited は、レート制限 (429) のレスポンスを返します。
+func discordRateLimited(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	w.Write([]byte(body))
+}
+
+func TestDiscordSend(t *testing.T) {
+	var payload discordPayload
+	dn := newTestDiscordNotifier(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("wait") != "true" {
+			t.Errorf("query = %q, want wait=true", r.URL.RawQuery)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1100","channel_id":"2200"}`))
+	}))
+	dn.Username = "notifier"
+
+	result, err := dn.Send(context.Background(), Message{
+		Title:    "バッチ失敗",
+		Body:     "夜間バッチが失敗しました",
+		Severity: SeverityError,
+		Fields:   []Field{{Name: "ジョブ", Value: "nightly"}},
+		Links:    []Link{{Text: "ログ", URL: "https://example.com/log"}},
+		Tags:     []string{"batch"},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.ID != "1100" || result.Channel != "2200" || len(result.Truncations) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+
+	if payload.Username != "notifier" || len(payload.Embeds) != 1 {
+		t.Fatalf("payload = %+v", payload)
+	}
+	embed := payload.Embeds[0]
+	if embed.Title != "バッチ失敗" || embed.Description != "夜間バッチが失敗しました" || embed.Color != discordSeverityColor(SeverityError) {
+		t.Errorf("embed = %+v", embed)
+	}
+	if len(embed.Fields) != 2 || embed.Fields[0].Name != "ジョブ" || embed.Fields[1].Value != "[ログ](https://example.com/log)" {
+		t.Errorf("fields = %+v", embed.Fields)
+	}
+	if embed.Footer == nil || embed.Footer.Text != "🚨 error | batch" {
+		t.Errorf("footer = %+v", embed.Footer)
+	}
+}
+
+func TestDiscordRetriesRateLimit(t *testing.T) {
+	var calls atomic.Int32
+	dn := newTestDiscordNotifier(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			discordRateLimited(w, `{"message":"You are being rate limited.","retry_after":0.01,"global":false}`)
+			return
+		}
+		w.Write([]byte(`{"id":"1","channel_id":"2"}`))
+	}))
+
+	if _, err := dn.Send(context.Background(), Message{Body: "本文"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestDiscordRateLimitRetryBudget(t *testing.T) {
+	for _, retries := range []int{0, 2} {
+		var calls atomic.Int32
+		dn := newTestDiscordNotifier(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			discordRateLimited(w, `{"retry_after":0.001}`)
+		}))
+		dn.MaxRateLimitRetries = retries
+
+		_, err := dn.Send(context.Background(), Message{Body: "本文"})
+		var httpErr *httpkit.NonRetryableHTTPError
+		if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusTooManyRequests {
+			t.Fatalf("retries %d: err = %v, want the 429 error", retries, err)
+		}
+		// 0 の場合は既定の回数 (3 回) 再送する
+		want := retries
+		if want == 0 {
+			want = defaultDiscordRateLimitRetries
+		}
+		if got := int(calls.Load()); got != want+1 {
+			t.Errorf("retries %d: calls = %d, want %d", retries, got, want+1)
+		}
+	}
+}
+
+func TestDiscordRateLimitTooLong(t *testing.T) {
+	var calls atomic.Int32
+	dn := newTestDiscordNotifier(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		discordRateLimited(w, `{"retry_after":120}`)
+	}))
+
+	start := time.Now()
+	_, err := dn.Send(context.Background(), Message{Body: "本文"})
+	if err == nil || !strings.Contains(err.Error(), "再送まで 2m0s") {
+		t.Fatalf("err = %v, want the rate limit error without waiting", err)
+	}
+	if calls.Load() != 1 || time.Since(start) > 5*time.Second {
+		t.Errorf("calls = %d, elapsed = %s; want a single call without waiting", calls.Load(), time.Since(start))
+	}
+}
+
+func TestDiscordRateLimitWaitCanceled(t *testing.T) {
+	dn := newTestDiscordNotifier(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		discordRateLimited(w, `{"retry_after":30}`)
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := dn.Send(ctx, Message{Body: "本文"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestDiscordRetryAfter(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantWait    time.Duration
+		wantLimited bool
+	}{
+		{"retry_after の秒数", &httpkit.NonRetryableHTTPError{StatusCode: 429, Body: []byte(`{"retry_after":1.5}`)}, 1500 * time.Millisecond, true},
+		{"retry_after がない場合は1秒", &httpkit.NonRetryableHTTPError{StatusCode: 429, Body: []byte(`{"message":"rate limited"}`)}, time.Second, true},
+		{"ボディが JSON でない場合は1秒", &httpkit.NonRetryableHTTPError{StatusCode: 429, Body: []byte(`Too Many Requests`)}, time.Second, true},
+		{"429 以外の 4xx", &httpkit.NonRetryableHTTPError{StatusCode: 400, Body: []byte(`{"retry_after":1}`)}, 0, false},
+		{"HTTP 以外のエラー", errors.New("connection refused"), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, limited := discordRetryAfter(tt.err)
+			if wait != tt.wantWait || limited != tt.wantLimited {
+				t.Errorf("discordRetryAfter() = %s, %v; want %s, %v", wait, limited, tt.wantWait, tt.wantLimited)
+			}
+		})
+	}
+}
+
+// discordEmbedsLength は、Discord が合計の上限と比較するテキストの長さを返します。
+func discordEmbedsLength(embeds []discordEmbed) int {
+	n := 0
+	for _, e := range embeds {
+		n += TextLength(e.Title) + TextLength(e.Description)
+		for _, f := range e.Fields {
+			n += TextLength(f.Name) + TextLength(f.Value)
+		}
+		if e.Footer != nil {
+			n += TextLength(e.Footer.Text)
+		}
+	}
+	return n
+}
+
+func TestBuildDiscordEmbedsLimits(t *testing.T) {
+	manyFields := func(n, valueLength int) []discordEmbedField {
+		fields := make([]discordEmbedField, n)
+		for i := range fields {
+			fields[i] = discordEmbedField{Name: "項目", Value: strings.Repeat("値", valueLength)}
+		}
+		return fields
+	}
+
+	tests := []struct {
+		name       string
+		title      string
+		message    string
+		fields     []discordEmbedField
+		footer     string
+		wantEmbeds int
+		wantFields int
+		want       []Truncation
+	}{
+		{
+			name:       "上限以内",
+			title:      "タイトル",
+			message:    "本文",
+			fields:     manyFields(3, 10),
+			footer:     "info",
+			wantEmbeds: 1,
+			wantFields: 3,
+		},
+		{
+			name:       "タイトルの上限",
+			title:      strings.Repeat("あ", DiscordTitleLimit+10),
+			wantEmbeds: 1,
+			want:       []Truncation{{Field: "title", Length: DiscordTitleLimit + 10, Limit: DiscordTitleLimit}},
+		},
+		{
+			name:       "フッターの上限",
+			footer:     strings.Repeat("f", DiscordFooterLimit+1),
+			wantEmbeds: 1,
+			want:       []Truncation{{Field: "footer", Length: DiscordFooterLimit + 1, Limit: DiscordFooterLimit}},
+		},
+		{
+			name:       "説明の上限を超える本文は埋め込みを分割する",
+			message:    strings.Repeat(strings.Repeat("あ", 99)+"\n", 50),
+			wantEmbeds: 2,
+		},
+		{
+			name:       "合計の上限を超える本文は切り詰める",
+			title:      "タイトル",
+			message:    strings.Repeat(strings.Repeat("あ", 99)+"\n", 70),
+			fields:     manyFields(2, 500),
+			footer:     "info",
+			wantEmbeds: 2,
+			wantFields: 2,
+			want:       []Truncation{{Field: "description", Length: 7000, Limit: DiscordEmbedTotalLimit - 4 - 4 - 2*(2+500) - 2*(len(codeFence)+1)}},
+		},
+		{
+			name:       "フィールドだけで合計の上限を超える場合はフィールドを省略する",
+			message:    "本文",
+			fields:     manyFields(8, 1000),
+			wantEmbeds: 1,
+			wantFields: 5,
+			want:       []Truncation{{Field: "field", Length: 8, Limit: 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rec truncationRecorder
+			embeds := buildDiscordEmbeds(tt.title, tt.message, tt.fields, tt.footer, SeverityInfo, &rec)
+
+			if len(embeds) != tt.wantEmbeds {
+				t.Fatalf("got %d embeds, want %d", len(embeds), tt.wantEmbeds)
+			}
+			if got := len(embeds[len(embeds)-1].Fields); got != tt.wantFields {
+				t.Errorf("got %d fields, want %d", got, tt.wantFields)
+			}
+			if len(rec.truncations) != len(tt.want) {
+				t.Fatalf("truncations = %v, want %v", rec.truncations, tt.want)
+			}
+			for i, tr := range rec.truncations {
+				if tr != tt.want[i] {
+					t.Errorf("truncations[%d] = %v, want %v", i, tr, tt.want[i])
+				}
+			}
+
+			if n := discordEmbedsLength(embeds); n > DiscordEmbedTotalLimit {
+				t.Errorf("total length %d exceeds %d", n, DiscordEmbedTotalLimit)
+			}
+			for i, e := range embeds {
+				if n := TextLength(e.Description); n > DiscordDescriptionLimit {
+					t.Errorf("embeds[%d] description has %d characters", i, n)
+				}
+				if TextLength(e.Title) > DiscordTitleLimit {
+					t.Errorf("embeds[%d] title has %d characters", i, TextLength(e.Title))
+				}
+				if i > 0 && e.Title != "" {
+					t.Errorf("embeds[%d] title = %q, want it only on the first embed", i, e.Title)
+				}
+			}
+		})
+	}
+}
+
+func TestDiscordMessageFieldsLimits(t *testing.T) {
+	fields := make([]Field, 30)
+	for i := range fields {
+		fields[i] = Field{Name: "項目", Value: "値"}
+	}
+	fields[0] = Field{Name: strings.Repeat("n", DiscordFieldNameLimit+1), Value: strings.Repeat("v", DiscordFieldValueLimit+1)}
+	fields[1] = Field{Name: " ", Value: ""}
+
+	var rec truncationRecorder
+	got := discordMessageFields(Message{Fields: fields, Links: []Link{{URL: "https://example.com"}}}, &rec)
+
+	// Links の分を除いた 24 件と、リンクのフィールド
+	if len(got) != DiscordMaxFields {
+		t.Fatalf("got %d fields, want %d", len(got), DiscordMaxFields)
+	}
+	if got[1].Name != "-" || got[1].Value != "-" {
+		t.Errorf("empty field = %+v, want \"-\"", got[1])
+	}
+	if last := got[len(got)-1]; last.Name != "リンク" || last.Value != "[https://example.com](https://example.com)" {
+		t.Errorf("links field = %+v", last)
+	}
+	want := []Truncation{
+		{Field: "field", Length: 30, Limit: DiscordMaxFields - 1},
+		{Field: "field", Length: DiscordFieldNameLimit + 1, Limit: DiscordFieldNameLimit},
+		{Field: "field", Length: DiscordFieldValueLimit + 1, Limit: DiscordFieldValueLimit},
+	}
+	if len(rec.truncations) != len(want) {
+		t.Fatalf("truncations = %v, want %v", rec.truncations, want)
+	}
+	for i := range want {
+		if rec.truncations[i] != want[i] {
+			t.Errorf("truncations[%d] = %v, want %v", i, rec.truncations[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,687 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EmailTLSMode は、SMTP サーバーとの接続の暗号化方法です。
+type EmailTLSMode string
+
+const (
+	// EmailStartTLS: 平文で接続した後に STARTTLS で暗号化する (既定。通常はポート 587)
+	EmailStartTLS EmailTLSMode = "starttls"
+	// EmailImplicitTLS: 接続時から TLS で暗号化する (SMTPS。通常はポート 465)
+	EmailImplicitTLS EmailTLSMode = "tls"
+	// EmailNoTLS: 暗号化しない (ローカルのリレーサーバーなど向け)
+	EmailNoTLS EmailTLSMode = "none"
+)
+
+// ParseEmailTLSMode は、文字列から接続の暗号化方法を取得します。空文字列の場合は既定の EmailStartTLS を返します。
+func ParseEmailTLSMode(s string) (EmailTLSMode, error) {
+	switch m := EmailTLSMode(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return EmailStartTLS, nil
+	case EmailStartTLS, EmailImplicitTLS, EmailNoTLS:
+		return m, nil
+	default:
+		return "", fmt.Errorf("不明な暗号化方法です: %q (starttls, tls, none のいずれかを指定してください)", s)
+	}
+}
+
+// EmailAuthMethod は、SMTP の認証方式です。
+type EmailAuthMethod string
+
+const (
+	// EmailAuthPlain: PLAIN 認証 (既定)
+	EmailAuthPlain EmailAuthMethod = "plain"
+	// EmailAuthLogin: LOGIN 認証 (Microsoft 365 など PLAIN に対応していないサーバー向け)
+	EmailAuthLogin EmailAuthMethod = "login"
+)
+
+// ParseEmailAuthMethod は、文字列から認証方式を取得します。空文字列の場合は既定の EmailAuthPlain を返します。
+func ParseEmailAuthMethod(s string) (EmailAuthMethod, error) {
+	switch m := EmailAuthMethod(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return EmailAuthPlain, nil
+	case EmailAuthPlain, EmailAuthLogin:
+		return m, nil
+	default:
+		return "", fmt.Errorf("不明な認証方式です: %q (plain, login のいずれかを指定してください)", s)
+	}
+}
+
+// emailReservedHeaders は、EmailNotifier が生成するため Headers で上書きできないヘッダーです。
+var emailReservedHeaders = map[string]bool{
+	"From": true, "To": true, "Cc": true, "Bcc": true, "Reply-To": true, "Subject": true, "Date": true,
+	"Message-Id": true, "Mime-Version": true, "Content-Type": true, "Content-Transfer-Encoding": true,
+}
+
+// EmailNotifier は SMTP サーバー経由でメールを送信するためのクライアントです。
+// Notifier インターフェースを実装します。本文はテキストと HTML の multipart/alternative として送信されます。
+type EmailNotifier struct {
+	// Addr: SMTP サーバーのアドレス (host:port)
+	Addr string
+	// From: 送信者のアドレス (例: "通知 <notifier@example.com>")
+	From string
+	// To / Cc / Bcc: 宛先のアドレス (Bcc はヘッダーに含まれません)
+	To  []string
+	Cc  []string
+	Bcc []string
+	// ReplyTo: 返信先のアドレス
+	ReplyTo []string
+	// Headers: 追加するヘッダー (例: "X-Mailer": "go-notifier")
+	Headers map[string]string
+	// TLSMode: 接続の暗号化方法 (空の場合は EmailStartTLS)
+	TLSMode EmailTLSMode
+	// AuthMethod: 認証方式 (空の場合は EmailAuthPlain。Username が空の場合は認証しません)
+	AuthMethod EmailAuthMethod
+	// Username: SMTP 認証のユーザー名
+	Username string
+	// TLSConfig: TLS の設定 (nil の場合はサーバーのホスト名を検証する既定の設定。テスト用のサーバーの証明書などを指定できます)
+	TLSConfig *tls.Config
+	// LocalName: EHLO で名乗るホスト名 (空の場合は "localhost")
+	LocalName string
+	// Timeout: 接続から送信完了までのタイムアウト (0 の場合は ctx の期限のみ)
+	Timeout  time.Duration
+	password string
+}
+
+// NewEmailNotifier は EmailNotifier を初期化します。username が空の場合は認証を行いません。
+func NewEmailNotifier(addr, username, password, from string, to []string) (*EmailNotifier, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("SMTP サーバーのアドレスは host:port の形式で指定してください: %q", addr)
+	}
+	if from == "" {
+		return nil, errors.New("メールの送信者のアドレスの設定が必要です")
+	}
+	if len(to) == 0 {
+		return nil, errors.New("メールの宛先のアドレスを1つ以上指定してください")
+	}
+	return &EmailNotifier{
+		Addr:     addr,
+		From:     from,
+		To:       to,
+		Username: username,
+		password: password,
+	}, nil
+}
+
+// --- Notifier インターフェース実装 ---
+
+// Send は、Message をメールに変換して送信します。
+// Title は件名と本文の見出し、Body は本文 (Markdown から HTML とテキストの両方に変換)、Fields は表、Links はリンクの一覧、
+// Severity・Tags・Labels は送信時刻とともにフッターとして描画されます。Attachments は添付ファイルとして送信されます。
+// SendResult の ID には生成した Message-ID が設定されます。
+func (e *EmailNotifier) Send(ctx context.Context, msg Message) (*SendResult, error) {
+	subject := msg.Title
+	if subject == "" {
+		subject = defaultHeader(msg.Body)
+	}
+
+	raw, messageID, err := e.buildMessage(subject, msg, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := e.deliver(ctx, raw); err != nil {
+		return nil, err
+	}
+	return &SendResult{Backend: "email", ID: messageID}, nil
+}
+
+// SendTextWithHeader は、headerText を件名、message を本文 (Markdown として解釈可能) としてメールを送信します。
+func (e *EmailNotifier) SendTextWithHeader(ctx context.Context, headerText string, message string) error {
+	raw, _, err := e.buildMessage(headerText, Message{Title: headerText, Body: message}, time.Now())
+	if err != nil {
+		return err
+	}
+	return e.deliver(ctx, raw)
+}
+
+// SendText は、プレーンテキストメッセージを通知します。（ヘッダーなし）
+// 本文の1行目からデフォルトヘッダーを生成し、SendTextWithHeader にフォールバックします。
+func (e *EmailNotifier) SendText(ctx context.Context, message string) error {
+	return e.SendTextWithHeader(ctx, defaultHeader(message), message)
+}
+
+// --- SMTP での送信 ---
+
+// deliver は、SMTP サーバーに接続し、組み立て済みのメッセージを送信します。
+func (e *EmailNotifier) deliver(ctx context.Context, raw []byte) error {
+	from, err := mail.ParseAddress(e.From)
+	if err != nil {
+		return fmt.Errorf("送信者のアドレスが不正です: %w", err)
+	}
+	recipients, err := e.recipients()
+	if err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(e.Addr)
+	if err != nil {
+		return fmt.Errorf("SMTP サーバーのアドレスが不正です: %w", err)
+	}
+	tlsConfig := e.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: host}
+	}
+
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+
+	conn, err := e.dial(ctx, tlsConfig)
+	if err != nil {
+		return err
+	}
+	// ctx の期限切れ・キャンセル時に、処理中の読み書きを中断する
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP サーバーとの通信の開始に失敗しました: %w", err)
+	}
+	defer client.Close()
+
+	if err := e.session(client, host, tlsConfig, from.Address, recipients, raw); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("%w (%w)", err, ctxErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// dial は、SMTP サーバーに接続します。EmailImplicitTLS の場合は TLS のハンドシェイクまで行います。
+func (e *EmailNotifier) dial(ctx context.Context, tlsConfig *tls.Config) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", e.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("SMTP サーバー (%s) への接続に失敗しました: %w", e.Addr, err)
+	}
+	if e.TLSMode != EmailImplicitTLS {
+		return conn, nil
+	}
+
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SMTP サーバーとの TLS のハンドシェイクに失敗しました: %w", err)
+	}
+	return tlsConn, nil
+}
+
+// session は、EHLO・STARTTLS・認証・送信の一連の SMTP コマンドを実行します。
+func (e *EmailNotifier) session(client *smtp.Client, host string, tlsConfig *tls.Config, from string, recipients []string, raw []byte) error {
+	if e.LocalName != "" {
+		if err := client.Hello(e.LocalName); err != nil {
+			return fmt.Errorf("SMTP の EHLO に失敗しました: %w", err)
+		}
+	}
+
+	if e.TLSMode == "" || e.TLSMode == EmailStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP サーバーが STARTTLS に対応していません (暗号化しない場合は TLS モードに none を指定してください)")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("SMTP の STARTTLS に失敗しました: %w", err)
+		}
+	}
+
+	if e.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("SMTP サーバーが認証 (AUTH) に対応していません")
+		}
+		var auth smtp.Auth
+		switch e.AuthMethod {
+		case "", EmailAuthPlain:
+			auth = smtp.PlainAuth("", e.Username, e.password, host)
+		case EmailAuthLogin:
+			auth = &loginAuth{username: e.Username, password: e.password, host: host}
+		default:
+			return fmt.Errorf("不明な認証方式です: %q", e.AuthMethod)
+		}
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP の認証に失敗しました: %w", err)
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("SMTP の MAIL FROM に失敗しました: %w", err)
+	}
+	for _, rcpt := range recipients {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("SMTP の RCPT TO (%s) に失敗しました: %w", rcpt, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP の DATA に失敗しました: %w", err)
+	}
+	if _, err := w.Write(raw); err != nil {
+		w.Close()
+		return fmt.Errorf("メールの本文の送信に失敗しました: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("メールの送信に失敗しました: %w", err)
+	}
+	return client.Quit()
+}
+
+// recipients は、To・Cc・Bcc のエンベロープの宛先 (アドレス部分のみ) を重複なく返します。
+func (e *EmailNotifier) recipients() ([]string, error) {
+	var recipients []string
+	seen := make(map[string]bool)
+	for _, list := range [][]string{e.To, e.Cc, e.Bcc} {
+		addrs, err := parseAddresses(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range addrs {
+			if key := strings.ToLower(a.Address); !seen[key] {
+				seen[key] = true
+				recipients = append(recipients, a.Address)
+			}
+		}
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("メールの宛先のアドレスを1つ以上指定してください")
+	}
+	return recipients, nil
+}
+
+// loginAuth は LOGIN 認証を実装する smtp.Auth です (net/smtp は PLAIN と CRAM-MD5 のみを提供するため)。
+type loginAuth struct {
+	username, password, host string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	// smtp.PlainAuth と同様に、暗号化されていない接続ではローカルホスト以外に資格情報を送信しない
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("暗号化されていない接続では LOGIN 認証を使用できません")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("SMTP サーバーのホスト名が一致しません")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	prompt := strings.ToLower(strings.TrimSpace(string(fromServer)))
+	switch {
+	case strings.HasPrefix(prompt, "user"):
+		return []byte(a.username), nil
+	case strings.HasPrefix(prompt, "pass"):
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("LOGIN 認証で想定外の応答を受信しました: %q", fromServer)
+	}
+}
+
+// isLocalhost は、ホスト名がローカルホストを指すかを判定します。
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
+
+// --- メッセージの組み立て ---
+
+// buildMessage は、ヘッダーと本文 (multipart/alternative、添付ファイルがある場合は multipart/mixed) からなる
+// RFC 5322 形式のメッセージを組み立て、生成した Message-ID とともに返します。
+func (e *EmailNotifier) buildMessage(subject string, msg Message, now time.Time) ([]byte, string, error) {
+	from, err := mail.ParseAddress(e.From)
+	if err != nil {
+		return nil, "", fmt.Errorf("送信者のアドレスが不正です: %w", err)
+	}
+	messageID, err := newMessageID(from.Address)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var header bytes.Buffer
+	writeHeader := func(name, value string) {
+		header.WriteString(name + ": " + value + "\r\n")
+	}
+	writeHeader("From", from.String())
+	for _, h := range []struct {
+		name string
+		list []string
+	}{{"To", e.To}, {"Cc", e.Cc}, {"Reply-To", e.ReplyTo}} {
+		if len(h.list) == 0 {
+			continue
+		}
+		addrs, err := parseAddresses(h.list)
+		if err != nil {
+			return nil, "", err
+		}
+		formatted := make([]string, 0, len(addrs))
+		for _, a := range addrs {
+			formatted = append(formatted, a.String())
+		}
+		writeHeader(h.name, strings.Join(formatted, ",\r\n "))
+	}
+	writeHeader("Subject", encodeHeaderValue(subject))
+	writeHeader("Date", now.Format(time.RFC1123Z))
+	writeHeader("Message-ID", messageID)
+	writeHeader("MIME-Version", "1.0")
+
+	names := make([]string, 0, len(e.Headers))
+	for name := range e.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		canonical, value, err := customHeader(name, e.Headers[name])
+		if err != nil {
+			return nil, "", err
+		}
+		writeHeader(canonical, value)
+	}
+
+	alternative, err := emailAlternative(subject, msg, now)
+	if err != nil {
+		return nil, "", err
+	}
+
+	body := alternative
+	if len(msg.Attachments) > 0 {
+		if body, err = emailMixed(alternative, msg.Attachments); err != nil {
+			return nil, "", err
+		}
+	}
+
+	writeHeader("Content-Type", body.contentType)
+	header.WriteString("\r\n")
+	header.Write(body.content)
+	return header.Bytes(), messageID, nil
+}
+
+// mimeEntity は、マルチパートの MIME のパートです。
+type mimeEntity struct {
+	// contentType: boundary を含む Content-Type ヘッダーの値
+	contentType string
+	content     []byte
+}
+
+// emailAlternative は、テキストと HTML の本文を multipart/alternative のパートとして組み立てます。
+func emailAlternative(subject string, msg Message, now time.Time) (mimeEntity, error) {
+	htmlBody, err := emailHTML(subject, msg, now)
+	if err != nil {
+		return mimeEntity{}, err
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", emailPlainText(subject, msg, now)},
+		{"text/html; charset=UTF-8", htmlBody},
+	} {
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return mimeEntity{}, fmt.Errorf("メールの本文の組み立てに失敗しました: %w", err)
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := io.WriteString(qp, part.content); err != nil {
+			return mimeEntity{}, fmt.Errorf("メールの本文の組み立てに失敗しました: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return mimeEntity{}, fmt.Errorf("メールの本文の組み立てに失敗しました: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return mimeEntity{}, fmt.Errorf("メールの本文の組み立てに失敗しました: %w", err)
+	}
+
+	return mimeEntity{
+		contentType: "multipart/alternative; boundary=" + w.Boundary(),
+		content:     buf.Bytes(),
+	}, nil
+}
+
+// emailMixed は、本文のパートと添付ファイルを multipart/mixed のパートとして組み立てます。
+func emailMixed(body mimeEntity, attachments []Attachment) (mimeEntity, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	pw, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {body.contentType}})
+	if err != nil {
+		return mimeEntity{}, fmt.Errorf("メールの本文の組み立てに失敗しました: %w", err)
+	}
+	if _, err := pw.Write(body.content); err != nil {
+		return mimeEntity{}, fmt.Errorf("メールの本文の組み立てに失敗しました: %w", err)
+	}
+
+	for _, a := range attachments {
+		if err := writeEmailAttachment(w, a); err != nil {
+			return mimeEntity{}, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return mimeEntity{}, fmt.Errorf("メールの本文の組み立てに失敗しました: %w", err)
+	}
+
+	return mimeEntity{
+		contentType: "multipart/mixed; boundary=" + w.Boundary(),
+		content:     buf.Bytes(),
+	}, nil
+}
+
+// writeEmailAttachment は、添付ファイルを base64 でエンコードしたパートとして書き込みます。
+// ファイル名に日本語などが含まれる場合は RFC 2231 形式でエンコードされます。
+func writeEmailAttachment(w *multipart.Writer, a Attachment) error {
+	if a.Content == nil {
+		return fmt.Errorf("添付ファイル %s の内容 (Content) が指定されていません", a.Filename)
+	}
+	content, err := io.ReadAll(a.Content)
+	if err != nil {
+		return fmt.Errorf("添付ファイル %s の読み込みに失敗しました: %w", a.Filename, err)
+	}
+
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(a.Filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	pw, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return fmt.Errorf("添付ファイル %s の組み立てに失敗しました: %w", a.Filename, err)
+	}
+
+	// base64 の行の長さは RFC 2045 に従って 76 文字以内にする
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(pw, encoded[:76]+"\r\n"); err != nil {
+			return fmt.Errorf("添付ファイル %s の組み立てに失敗しました: %w", a.Filename, err)
+		}
+		encoded = encoded[76:]
+	}
+	if _, err := io.WriteString(pw, encoded+"\r\n"); err != nil {
+		return fmt.Errorf("添付ファイル %s の組み立てに失敗しました: %w", a.Filename, err)
+	}
+	return nil
+}
+
+// emailPlainText は、メッセージをテキストパートの本文に変換します。
+func emailPlainText(subject string, msg Message, now time.Time) string {
+	parts := []string{subject}
+	if body := markdownToPlainText(msg.Body); body != "" {
+		parts = append(parts, body)
+	}
+
+	var extras []string
+	for _, f := range msg.Fields {
+		extras = append(extras, fmt.Sprintf("%s: %s", f.Name, f.Value))
+	}
+	for _, l := range msg.Links {
+		extras = append(extras, plainLink(l.URL, l.Text))
+	}
+	if len(extras) > 0 {
+		parts = append(parts, strings.Join(extras, "\n"))
+	}
+
+	footer := fmt.Sprintf("送信時刻: %s", now.Format("2006-01-02 15:04:05"))
+	if meta := emailMeta(msg); meta != "" {
+		footer = meta + "\n" + footer
+	}
+	parts = append(parts, "--\n"+footer)
+
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// emailHTML は、メッセージを HTML パートの本文に変換します。見出しの左端の線の色で重要度を表します。
+func emailHTML(subject string, msg Message, now time.Time) (string, error) {
+	body, err := markdownToEmailHTML(msg.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head><meta charset=\"UTF-8\"></head>\n<body style=\"font-family: sans-serif;\">\n")
+	fmt.Fprintf(&sb, "<h2 style=\"border-left: 4px solid %s; padding-left: 8px;\">%s</h2>\n", emailSeverityColor(msg.Severity), html.EscapeString(subject))
+	sb.WriteString(body)
+
+	if len(msg.Fields) > 0 {
+		sb.WriteString("<table style=\"border-collapse: collapse;\">\n")
+		for _, f := range msg.Fields {
+			fmt.Fprintf(&sb, "<tr><th style=\"text-align: left; padding: 4px 12px 4px 0;\">%s</th><td style=\"padding: 4px 0;\">%s</td></tr>\n",
+				html.EscapeString(f.Name), html.EscapeString(f.Value))
+		}
+		sb.WriteString("</table>\n")
+	}
+
+	if len(msg.Links) > 0 {
+		sb.WriteString("<ul>\n")
+		for _, l := range msg.Links {
+			text := l.Text
+			if text == "" {
+				text = l.URL
+			}
+			fmt.Fprintf(&sb, "<li><a href=\"%s\">%s</a></li>\n", html.EscapeString(l.URL), html.EscapeString(text))
+		}
+		sb.WriteString("</ul>\n")
+	}
+
+	// フッターには送信時刻を含める
+	footer := fmt.Sprintf("送信時刻: %s", now.Format("2006-01-02 15:04:05"))
+	if meta := emailMeta(msg); meta != "" {
+		footer = html.EscapeString(meta) + "<br>" + footer
+	}
+	fmt.Fprintf(&sb, "<hr>\n<p style=\"color: #80868b; font-size: 12px;\">%s</p>\n</body>\n</html>\n", footer)
+	return sb.String(), nil
+}
+
+// emailMeta は、Message の Severity・Tags・Labels をフッターの1行に変換します。
+func emailMeta(msg Message) string {
+	var meta []string
+	if msg.Severity != "" {
+		meta = append(meta, fmt.Sprintf("%s %s", msg.Severity.Emoji(), msg.Severity))
+	}
+	if len(msg.Tags) > 0 {
+		meta = append(meta, strings.Join(msg.Tags, ", "))
+	}
+	for _, k := range sortedLabelKeys(msg.Labels) {
+		meta = append(meta, fmt.Sprintf("%s: %s", k, msg.Labels[k]))
+	}
+	return strings.Join(meta, " | ")
+}
+
+// emailSeverityColor は、重要度に対応する見出しの線の色を返します (Discord の埋め込みの色と同じです)。
+func emailSeverityColor(s Severity) string {
+	return fmt.Sprintf("#%06X", discordSeverityColor(s))
+}
+
+// --- ヘッダーのエンコード ---
+
+// parseAddresses は、アドレスの一覧を解析します。各要素は "name <addr>" 形式またはアドレスのみで指定できます。
+func parseAddresses(list []string) ([]*mail.Address, error) {
+	addrs := make([]*mail.Address, 0, len(list))
+	for _, s := range list {
+		a, err := mail.ParseAddress(s)
+		if err != nil {
+			return nil, fmt.Errorf("メールアドレスが不正です: %q: %w", s, err)
+		}
+		addrs = append(addrs, a)
+	}
+	return addrs, nil
+}
+
+// encodeHeaderValue は、ASCII 以外の文字を含むヘッダーの値を RFC 2047 の encoded-word (UTF-8、B エンコーディング) に変換します。
+// encoded-word が複数になる場合は、1行が長くなりすぎないように encoded-word の間で折り返します。
+func encodeHeaderValue(s string) string {
+	encoded := mime.BEncoding.Encode("UTF-8", s)
+	if encoded == s {
+		return s
+	}
+	return strings.ReplaceAll(encoded, "?= =?", "?=\r\n =?")
+}
+
+// customHeader は、追加するヘッダーの名前を正規化し、値をエンコードします。
+// 改行を含む値 (ヘッダーインジェクション) や、EmailNotifier が生成するヘッダーの上書きはエラーになります。
+func customHeader(name, value string) (string, string, error) {
+	canonical := textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(name))
+	if canonical == "" || strings.ContainsAny(canonical, " :\r\n\t") {
+		return "", "", fmt.Errorf("ヘッダーの名前が不正です: %q", name)
+	}
+	if emailReservedHeaders[canonical] {
+		return "", "", fmt.Errorf("ヘッダー %s は上書きできません", canonical)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return "", "", fmt.Errorf("ヘッダー %s の値に改行を含めることはできません", canonical)
+	}
+	return canonical, encodeHeaderValue(value), nil
+}
+
+// newMessageID は、送信者のアドレスのドメインを使用して一意な Message-ID を生成します。
+func newMessageID(fromAddress string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("Message-ID の生成に失敗しました: %w", err)
+	}
+	domain := "localhost"
+	if _, d, ok := strings.Cut(fromAddress, "@"); ok && d != "" {
+		domain = d
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">", nil
+}
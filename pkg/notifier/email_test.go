@@ -0,0 +1,431 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer は、EHLO・STARTTLS・AUTH (PLAIN / LOGIN)・MAIL・RCPT・DATA に応答する SMTP サーバーです。
+// 受信したメールは、接続ごとに sessions に記録します。
+type fakeSMTPServer struct {
+	addr string
+	// clientTLS: サーバーの自己署名証明書を信頼する、クライアント用の TLS の設定
+	clientTLS *tls.Config
+
+	serverTLS   *tls.Config
+	implicitTLS bool
+	noStartTLS  bool
+
+	mu       sync.Mutex
+	sessions []*fakeSMTPSession
+}
+
+// fakeSMTPSession は、1つの接続で受信した内容です。
+type fakeSMTPSession struct {
+	// tls: MAIL FROM の時点で接続が暗号化されていたか
+	tls      bool
+	authMech string
+	username string
+	password string
+	from     string
+	rcpts    []string
+	data     []byte
+}
+
+// newFakeSMTPServer は、127.0.0.1 で待ち受ける fakeSMTPServer を起動します。
+// implicitTLS が true の場合は接続時から TLS で、noStartTLS が true の場合は STARTTLS を提供せずに応答します。
+func newFakeSMTPServer(t *testing.T, implicitTLS, noStartTLS bool) *fakeSMTPServer {
+	t.Helper()
+	cert, pool := newTestCertificate(t)
+	s := &fakeSMTPServer{
+		clientTLS:   &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"},
+		serverTLS:   &tls.Config{Certificates: []tls.Certificate{cert}},
+		implicitTLS: implicitTLS,
+		noStartTLS:  noStartTLS,
+	}
+
+	var ln net.Listener
+	var err error
+	if implicitTLS {
+		ln, err = tls.Listen("tcp", "127.0.0.1:0", s.serverTLS)
+	} else {
+		ln, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	s.addr = ln.Addr().String()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(t, conn)
+		}
+	}()
+	return s
+}
+
+// serve は、1つの接続の SMTP コマンドに応答します。
+func (s *fakeSMTPServer) serve(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	session := &fakeSMTPSession{}
+	s.mu.Lock()
+	s.sessions = append(s.sessions, session)
+	s.mu.Unlock()
+
+	encrypted := s.implicitTLS
+	tp := textproto.NewConn(conn)
+	reply := func(format string, args ...any) {
+		if err := tp.PrintfLine(format, args...); err != nil {
+			t.Errorf("fake SMTP reply: %v", err)
+		}
+	}
+	readLine := func() string {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return ""
+		}
+		return line
+	}
+
+	reply("220 127.0.0.1 ESMTP fake")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			lines := []string{"127.0.0.1"}
+			if !encrypted && !s.noStartTLS {
+				lines = append(lines, "STARTTLS")
+			}
+			lines = append(lines, "AUTH PLAIN LOGIN", "8BITMIME")
+			for i, l := range lines {
+				sep := "-"
+				if i == len(lines)-1 {
+					sep = " "
+				}
+				reply("250%s%s", sep, l)
+			}
+		case "STARTTLS":
+			reply("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.serverTLS)
+			if err := tlsConn.Handshake(); err != nil {
+				t.Errorf("fake SMTP TLS handshake: %v", err)
+				return
+			}
+			conn, encrypted = tlsConn, true
+			tp = textproto.NewConn(tlsConn)
+		case "AUTH":
+			mech, initial, _ := strings.Cut(arg, " ")
+			session.authMech = strings.ToUpper(mech)
+			switch session.authMech {
+			case "PLAIN":
+				decoded, _ := base64.StdEncoding.DecodeString(initial)
+				parts := strings.Split(string(decoded), "\x00")
+				if len(parts) == 3 {
+					session.username, session.password = parts[1], parts[2]
+				}
+			case "LOGIN":
+				reply("334 %s", base64.StdEncoding.EncodeToString([]byte("Username:")))
+				user, _ := base64.StdEncoding.DecodeString(readLine())
+				reply("334 %s", base64.StdEncoding.EncodeToString([]byte("Password:")))
+				pass, _ := base64.StdEncoding.DecodeString(readLine())
+				session.username, session.password = string(user), string(pass)
+			}
+			reply("235 Authentication successful")
+		case "MAIL":
+			session.tls = encrypted
+			// 8BITMIME に対応したサーバーには "FROM:<addr> BODY=8BITMIME" が送信される
+			addr, _, _ := strings.Cut(strings.TrimPrefix(arg, "FROM:"), " ")
+			session.from = strings.Trim(addr, "<>")
+			reply("250 OK")
+		case "RCPT":
+			session.rcpts = append(session.rcpts, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				t.Errorf("fake SMTP DATA: %v", err)
+				return
+			}
+			session.data = data
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// session は、最初の接続で受信した内容を返します。
+func (s *fakeSMTPServer) session(t *testing.T) *fakeSMTPSession {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.sessions) == 0 {
+		t.Fatal("SMTP サーバーへの接続がありません")
+	}
+	return s.sessions[0]
+}
+
+// newTestCertificate は、127.0.0.1 の自己署名証明書と、それを信頼する証明書プールを生成します。
+func newTestCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+// newTestEmailNotifier は、fakeSMTPServer に接続する EmailNotifier を生成します。
+func newTestEmailNotifier(t *testing.T, server *fakeSMTPServer, username, password string) *EmailNotifier {
+	t.Helper()
+	e, err := NewEmailNotifier(server.addr, username, password, "通知 <notifier@example.com>", []string{"dev@example.com"})
+	if err != nil {
+		t.Fatalf("NewEmailNotifier: %v", err)
+	}
+	e.TLSConfig = server.clientTLS
+	e.Timeout = 5 * time.Second
+	return e
+}
+
+func TestEmailSendStartTLSPlainAuth(t *testing.T) {
+	server := newFakeSMTPServer(t, false, false)
+	e := newTestEmailNotifier(t, server, "notifier", "secret")
+	e.Cc = []string{"lead@example.com"}
+	e.Bcc = []string{"監査 <audit@example.com>"}
+
+	result, err := e.Send(context.Background(), Message{Title: "夜間バッチが失敗しました", Body: "詳細はログを確認してください。"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	session := server.session(t)
+	if !session.tls {
+		t.Error("MAIL FROM was sent before STARTTLS")
+	}
+	if session.authMech != "PLAIN" || session.username != "notifier" || session.password != "secret" {
+		t.Errorf("auth = %s %s/%s, want PLAIN notifier/secret", session.authMech, session.username, session.password)
+	}
+	if session.from != "notifier@example.com" {
+		t.Errorf("MAIL FROM = %q, want notifier@example.com", session.from)
+	}
+	if want := []string{"dev@example.com", "lead@example.com", "audit@example.com"}; !slices.Equal(session.rcpts, want) {
+		t.Errorf("RCPT TO = %q, want %q", session.rcpts, want)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(session.data)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	if _, ok := msg.Header["Bcc"]; ok || strings.Contains(string(session.data), "audit@example.com") {
+		t.Error("Bcc address leaked into the message")
+	}
+	if got := msg.Header.Get("Message-Id"); got != result.ID {
+		t.Errorf("Message-ID = %q, want SendResult.ID %q", got, result.ID)
+	}
+}
+
+func TestEmailSendImplicitTLSLoginAuth(t *testing.T) {
+	server := newFakeSMTPServer(t, true, false)
+	e := newTestEmailNotifier(t, server, "notifier", "secret")
+	e.TLSMode = EmailImplicitTLS
+	e.AuthMethod = EmailAuthLogin
+
+	if _, err := e.Send(context.Background(), Message{Title: "テスト", Body: "本文"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	session := server.session(t)
+	if !session.tls {
+		t.Error("connection was not encrypted")
+	}
+	if session.authMech != "LOGIN" || session.username != "notifier" || session.password != "secret" {
+		t.Errorf("auth = %s %s/%s, want LOGIN notifier/secret", session.authMech, session.username, session.password)
+	}
+	if len(session.data) == 0 {
+		t.Error("no message data was received")
+	}
+}
+
+func TestEmailSendRequiresStartTLS(t *testing.T) {
+	server := newFakeSMTPServer(t, false, true)
+	e := newTestEmailNotifier(t, server, "notifier", "secret")
+
+	if _, err := e.Send(context.Background(), Message{Title: "テスト", Body: "本文"}); err == nil {
+		t.Fatal("Send succeeded without STARTTLS, want an error")
+	}
+	if session := server.session(t); session.authMech != "" || session.from != "" {
+		t.Errorf("credentials or envelope were sent over plain text: %+v", session)
+	}
+}
+
+func TestEmailBuildMessageStructure(t *testing.T) {
+	e, err := NewEmailNotifier("smtp.example.com:587", "", "", "notifier@example.com", []string{"dev@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject := "【障害】本番環境の夜間バッチが失敗しました。至急、ログを確認して再実行してください。"
+	raw, _, err := e.buildMessage(subject, Message{
+		Title:       subject,
+		Body:        "**原因**は調査中です。",
+		Attachments: []Attachment{{Filename: "ログ.txt", Content: strings.NewReader("error: timeout")}},
+	}, time.Now())
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+
+	// 件名は RFC 2047 でエンコードされ、75 文字以内の encoded-word ごとに折り返される
+	rawSubject := msg.Header.Get("Subject")
+	words := strings.Fields(rawSubject)
+	if len(words) < 2 {
+		t.Errorf("Subject = %q, want several folded encoded-words", rawSubject)
+	}
+	for _, w := range words {
+		if !strings.HasPrefix(w, "=?UTF-8?b?") || len(w) > 75 {
+			t.Errorf("encoded-word %q is not a B-encoded word of at most 75 characters", w)
+		}
+	}
+	decoded, err := new(mime.WordDecoder).DecodeHeader(rawSubject)
+	if err != nil || decoded != subject {
+		t.Errorf("decoded Subject = %q (%v), want %q", decoded, err, subject)
+	}
+	header, _, _ := strings.Cut(string(raw), "\r\n\r\n")
+	for _, line := range strings.Split(header, "\r\n") {
+		if len(line) > 998 {
+			t.Errorf("header line is %d characters, want at most 998", len(line))
+		}
+	}
+
+	// multipart/mixed の中に、multipart/alternative (text/plain, text/html) と添付ファイルが入る
+	mixed := readParts(t, msg.Header.Get("Content-Type"), msg.Body, "multipart/mixed")
+	if len(mixed) != 2 {
+		t.Fatalf("multipart/mixed has %d parts, want 2", len(mixed))
+	}
+	alternative := readParts(t, mixed[0].header.Get("Content-Type"), strings.NewReader(mixed[0].body), "multipart/alternative")
+	var types []string
+	for _, p := range alternative {
+		mediaType, _, _ := mime.ParseMediaType(p.header.Get("Content-Type"))
+		types = append(types, mediaType)
+	}
+	if !slices.Equal(types, []string{"text/plain", "text/html"}) {
+		t.Errorf("multipart/alternative parts = %q, want text/plain then text/html", types)
+	}
+	if !strings.Contains(alternative[1].body, "<strong>原因</strong>") {
+		t.Errorf("HTML part does not render Markdown: %q", alternative[1].body)
+	}
+
+	_, params, _ := mime.ParseMediaType(mixed[1].header.Get("Content-Disposition"))
+	if params["filename"] != "ログ.txt" {
+		t.Errorf("attachment filename = %q, want ログ.txt", params["filename"])
+	}
+	if mixed[1].body != "error: timeout" {
+		t.Errorf("attachment content = %q, want %q", mixed[1].body, "error: timeout")
+	}
+}
+
+func TestEmailBuildMessageRejectsNilAttachment(t *testing.T) {
+	e, err := NewEmailNotifier("smtp.example.com:587", "", "", "notifier@example.com", []string{"dev@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = e.buildMessage("テスト", Message{Body: "本文", Attachments: []Attachment{{Filename: "ログ.txt"}}}, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "ログ.txt") {
+		t.Errorf("err = %v, want an error naming the attachment", err)
+	}
+}
+
+// mimePart は、デコード済みのマルチパートのパートです。
+type mimePart struct {
+	header textproto.MIMEHeader
+	body   string
+}
+
+// readParts は、マルチパートの本文を読み込み、media type が want であることを確認してパートを返します。
+// quoted-printable のパートは multipart.Reader が、base64 のパートはここでデコードします。
+func readParts(t *testing.T, contentType string, body io.Reader, want string) []mimePart {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != want {
+		t.Fatalf("Content-Type = %q (%v), want %s", contentType, err, want)
+	}
+
+	var parts []mimePart
+	mr := multipart.NewReader(body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+		var r io.Reader = p
+		if p.Header.Get("Content-Transfer-Encoding") == "base64" {
+			r = base64.NewDecoder(base64.StdEncoding, bufio.NewReader(p))
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		parts = append(parts, mimePart{header: p.Header, body: string(data)})
+	}
+}
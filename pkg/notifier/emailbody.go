@@ -0,0 +1,176 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// emailMarkdown は、メールの HTML パートを生成する GFM 対応の Markdown 変換器です。
+// 本文中の生の HTML は出力されません (goldmark の既定の安全な動作)。
+var emailMarkdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// markdownToEmailHTML は、Markdown テキストをメールの HTML パートの本文に変換します。
+func markdownToEmailHTML(markdown string) (string, error) {
+	var buf bytes.Buffer
+	if err := emailMarkdown.Convert([]byte(markdown), &buf); err != nil {
+		return "", fmt.Errorf("Markdown の HTML への変換に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// markdownToPlainText は、Markdown テキストをメールのテキストパート用の書式なしのテキストに変換します。
+// 強調などの記号は取り除き、リンクは「テキスト (URL)」、コードブロックは4文字のインデント、表は等幅の整形済みテキストになります。
+func markdownToPlainText(markdown string) string {
+	source := []byte(markdown)
+	doc := markdownParser.Parse(text.NewReader(source))
+
+	r := &plainTextRenderer{mrkdwnRenderer{source: source}}
+	return r.children(doc, 0, "\n\n")
+}
+
+// plainTextRenderer は、goldmark の AST を書式なしのテキストに変換するレンダラーです。
+// コードブロックの行の取得などの AST の読み取りは mrkdwnRenderer と共通です。
+type plainTextRenderer struct {
+	mrkdwnRenderer
+}
+
+// block は、ブロック要素をテキストに変換します。depth はリストの入れ子の深さです。
+func (r *plainTextRenderer) block(n ast.Node, depth int) string {
+	switch n := n.(type) {
+	case *ast.Paragraph, *ast.TextBlock:
+		return r.inlines(n)
+	case *ast.Heading:
+		return r.inlines(n)
+	case *ast.List:
+		return r.list(n, depth)
+	case *ast.Blockquote:
+		return prefixLines(r.children(n, depth, "\n"), "> ")
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		return prefixLines(r.lines(n), "    ")
+	case *ast.ThematicBreak:
+		return "──────────"
+	case *ast.HTMLBlock:
+		return strings.TrimRight(r.lines(n), "\n")
+	case *east.Table:
+		// mrkdwn の表 (``` で囲まれたエスケープ済みのテキスト) をインデントした整形済みテキストにする
+		table := strings.TrimSuffix(strings.TrimPrefix(r.mrkdwnRenderer.table(n), "```\n"), "\n```")
+		return prefixLines(html.UnescapeString(table), "    ")
+	default:
+		return r.children(n, depth, "\n\n")
+	}
+}
+
+// children は、子ブロックを変換して sep で連結します。
+func (r *plainTextRenderer) children(n ast.Node, depth int, sep string) string {
+	var parts []string
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if s := r.block(c, depth); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// list は、箇条書き・番号付きリストを、入れ子の深さに応じたインデント付きで変換します。
+func (r *plainTextRenderer) list(n *ast.List, depth int) string {
+	indent := strings.Repeat("    ", depth)
+	number := n.Start
+	if number == 0 {
+		number = 1
+	}
+
+	var lines []string
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		marker := listBullets[depth%len(listBullets)]
+		if n.IsOrdered() {
+			marker = fmt.Sprintf("%d.", number)
+			number++
+		}
+
+		var body []string
+		for c := item.FirstChild(); c != nil; c = c.NextSibling() {
+			if nested, ok := c.(*ast.List); ok {
+				body = append(body, r.list(nested, depth+1))
+				continue
+			}
+			if s := r.block(c, depth+1); s != "" {
+				body = append(body, prefixContinuation(s, indent+"    "))
+			}
+		}
+
+		if len(body) == 0 {
+			lines = append(lines, indent+marker)
+			continue
+		}
+		if _, nested := item.FirstChild().(*ast.List); nested {
+			lines = append(lines, indent+marker)
+			lines = append(lines, body...)
+			continue
+		}
+		lines = append(lines, indent+marker+" "+body[0])
+		lines = append(lines, body[1:]...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// inlines は、インライン要素をテキストに変換します。
+func (r *plainTextRenderer) inlines(n ast.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		r.inline(&sb, c)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// inline は、1つのインライン要素をテキストとして書き込みます。
+func (r *plainTextRenderer) inline(sb *strings.Builder, n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Text:
+		sb.WriteString(r.unescape(n.Segment.Value(r.source)))
+		if n.HardLineBreak() || n.SoftLineBreak() {
+			sb.WriteString("\n")
+		}
+	case *ast.String:
+		sb.WriteString(string(n.Value))
+	case *ast.CodeSpan:
+		sb.WriteString(r.rawText(n))
+	case *ast.Link:
+		sb.WriteString(plainLink(string(n.Destination), r.inlines(n)))
+	case *ast.AutoLink:
+		sb.WriteString(string(n.Label(r.source)))
+	case *ast.Image:
+		sb.WriteString(plainLink(string(n.Destination), r.inlines(n)))
+	case *east.TaskCheckBox:
+		if n.IsChecked {
+			sb.WriteString("☑ ")
+		} else {
+			sb.WriteString("☐ ")
+		}
+	case *ast.RawHTML:
+		for i := 0; i < n.Segments.Len(); i++ {
+			seg := n.Segments.At(i)
+			sb.Write(seg.Value(r.source))
+		}
+	default:
+		// 強調・打ち消し線などは記号を付けずに中身のみを出力する
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			r.inline(sb, c)
+		}
+	}
+}
+
+// plainLink は、URL と表示テキストから「テキスト (URL)」形式のリンクを生成します。
+func plainLink(url, label string) string {
+	if label == "" || label == url {
+		return url
+	}
+	return label + " (" + url + ")"
+}
@@ -0,0 +1,125 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/forPelevin/gomoji"
+)
+
+// EmojiPolicy は、絵文字を扱えない (または扱いたくない) 通知先へ送信する際の、絵文字の変換方法です。
+type EmojiPolicy string
+
+const (
+	// EmojiShortcode: 絵文字を :white_check_mark: のようなショートコードのテキストに置き換える (既定)
+	EmojiShortcode EmojiPolicy = "shortcode"
+	// EmojiASCII: 絵文字を [OK] のような ASCII の表記に置き換える (表記が未定義の絵文字はショートコード)
+	EmojiASCII EmojiPolicy = "ascii"
+	// EmojiStrip: 絵文字を削除する
+	EmojiStrip EmojiPolicy = "strip"
+	// EmojiKeep: 絵文字をそのまま送信する (utf8mb4 に対応したスペース向け)
+	EmojiKeep EmojiPolicy = "keep"
+)
+
+// ParseEmojiPolicy は、文字列から絵文字の変換方法を取得します。空文字列の場合は既定の EmojiShortcode を返します。
+func ParseEmojiPolicy(s string) (EmojiPolicy, error) {
+	switch p := EmojiPolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return EmojiShortcode, nil
+	case EmojiShortcode, EmojiASCII, EmojiStrip, EmojiKeep:
+		return p, nil
+	default:
+		return "", fmt.Errorf("不明な絵文字の変換方法です: %q (shortcode, ascii, strip, keep のいずれかを指定してください)", s)
+	}
+}
+
+// DefaultEmojiASCII は、EmojiASCII で使用する、ステータス表示によく使われる絵文字の ASCII 表記です。
+var DefaultEmojiASCII = map[string]string{
+	"✅":  "[OK]",
+	"✔️": "[OK]",
+	"☑️": "[OK]",
+	"❌":  "[NG]",
+	"✖️": "[NG]",
+	"⚠️": "[WARN]",
+	"🚨":  "[ALERT]",
+	"🔥":  "[FIRE]",
+	"ℹ️": "[INFO]",
+	"❗":  "[!]",
+	"❓":  "[?]",
+	"🚀":  "[DEPLOY]",
+	"🐛":  "[BUG]",
+	"⏳":  "[WAIT]",
+	"🔁":  "[RETRY]",
+	"👍":  "[+1]",
+	"👎":  "[-1]",
+}
+
+// emojiShortcodes は、Slack などで一般的なショートコードが絵文字データの名前 (slug) と異なる絵文字の対応表です。
+var emojiShortcodes = map[string]string{
+	"✅":  "white_check_mark",
+	"✔️": "heavy_check_mark",
+	"❌":  "x",
+	"⚠️": "warning",
+	"🚨":  "rotating_light",
+	"ℹ️": "information_source",
+	"❗":  "exclamation",
+	"❓":  "question",
+	"👍":  "+1",
+	"👎":  "-1",
+	"🔁":  "repeat",
+	"⏳":  "hourglass_flowing_sand",
+}
+
+// withoutVariationSelectors は、絵文字の異体字セレクタ (U+FE0F など) を取り除きます。
+// "⚠" と "⚠️" のように、異体字セレクタの有無だけが異なる絵文字を同じものとして照合するために使用します。
+func withoutVariationSelectors(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.In(r, unicode.Variation_Selector) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// lookupEmoji は、異体字セレクタの有無を区別せずに、絵文字に対応する値を表から取得します。
+func lookupEmoji(table map[string]string, emoji string) (string, bool) {
+	key := withoutVariationSelectors(emoji)
+	for k, v := range table {
+		if withoutVariationSelectors(k) == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// emojiShortcode は、絵文字のショートコード (例: ":white_check_mark:") を返します。
+func emojiShortcode(em gomoji.Emoji) string {
+	if name, ok := lookupEmoji(emojiShortcodes, em.Character); ok {
+		return ":" + name + ":"
+	}
+	return ":" + strings.ReplaceAll(em.Slug, "-", "_") + ":"
+}
+
+// ApplyEmojiPolicy は、テキストに含まれる絵文字を policy に従って変換します。
+// EmojiASCII では、ascii に指定した表記が DefaultEmojiASCII より優先されます。改行や空白はそのまま保持します。
+func ApplyEmojiPolicy(s string, policy EmojiPolicy, ascii map[string]string) string {
+	switch policy {
+	case EmojiKeep:
+		return s
+	case EmojiStrip:
+		return gomoji.RemoveEmojis(s)
+	case EmojiASCII:
+		return gomoji.ReplaceEmojisWithFunc(s, func(em gomoji.Emoji) string {
+			if v, ok := lookupEmoji(ascii, em.Character); ok {
+				return v
+			}
+			if v, ok := lookupEmoji(DefaultEmojiASCII, em.Character); ok {
+				return v
+			}
+			return emojiShortcode(em)
+		})
+	default:
+		return gomoji.ReplaceEmojisWithFunc(s, emojiShortcode)
+	}
+}
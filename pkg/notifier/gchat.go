@@ -0,0 +1,271 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// googleChatReplyOption は、threadKey を指定した場合のスレッドへの返信方法です。
+// 同じキーのスレッドがあればそのスレッドに返信し、なければ新しいスレッドを開始します。
+const googleChatReplyOption = "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD"
+
+// GoogleChatNotifier は Google Chat のスペースの Webhook と連携するためのクライアントです。
+// Notifier インターフェースを実装します。メッセージはカード (Cards v2) として投稿されます。
+type GoogleChatNotifier struct {
+	// WebhookURL: スペースの Webhook URL (https://chat.googleapis.com/v1/spaces/.../messages?key=...&token=...)
+	WebhookURL string
+	// httpClient: 汎用クライアント (リトライロジックを含む)
+	client httpkit.Client
+	// ThreadKey: 同じキーのメッセージを1つのスレッドにまとめるためのキー (空の場合はスレッドを指定しない)
+	ThreadKey string
+}
+
+// NewGoogleChatNotifier は GoogleChatNotifier の新しいインスタンスを作成します。
+func NewGoogleChatNotifier(client httpkit.Client, webhookURL string) (*GoogleChatNotifier, error) {
+	if webhookURL == "" {
+		return nil, errors.New("Google Chat への投稿には Webhook URL の設定が必要です")
+	}
+	return &GoogleChatNotifier{
+		WebhookURL: webhookURL,
+		client:     client,
+	}, nil
+}
+
+// --- Cards v2 のペイロード ---
+
+// googleChatPayload は Google Chat のメッセージです。
+type googleChatPayload struct {
+	Text    string             `json:"text,omitempty"`
+	CardsV2 []googleChatCardV2 `json:"cardsV2"`
+	Thread  *googleChatThread  `json:"thread,omitempty"`
+}
+
+// googleChatThread はメッセージのスレッドです。
+type googleChatThread struct {
+	Name      string `json:"name,omitempty"`
+	ThreadKey string `json:"threadKey,omitempty"`
+}
+
+// googleChatCardV2 は、ID 付きのカードです。
+type googleChatCardV2 struct {
+	CardID string         `json:"cardId"`
+	Card   googleChatCard `json:"card"`
+}
+
+// googleChatCard はカード本体です。
+type googleChatCard struct {
+	Header   *googleChatCardHeader `json:"header,omitempty"`
+	Sections []googleChatSection   `json:"sections"`
+}
+
+// googleChatCardHeader はカードのヘッダーです。
+type googleChatCardHeader struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle,omitempty"`
+}
+
+// googleChatSection は、ウィジェットをまとめたカードのセクションです。
+type googleChatSection struct {
+	Header  string             `json:"header,omitempty"`
+	Widgets []googleChatWidget `json:"widgets"`
+}
+
+// googleChatWidget は、セクションに配置するウィジェットです。いずれか1つのフィールドのみを設定します。
+type googleChatWidget struct {
+	TextParagraph *googleChatTextParagraph `json:"textParagraph,omitempty"`
+	DecoratedText *googleChatDecoratedText `json:"decoratedText,omitempty"`
+	ButtonList    *googleChatButtonList    `json:"buttonList,omitempty"`
+}
+
+// googleChatTextParagraph は、HTML で書式を指定できるテキストのウィジェットです。
+type googleChatTextParagraph struct {
+	Text string `json:"text"`
+}
+
+// googleChatDecoratedText は、ラベル付きのテキストのウィジェットです。
+type googleChatDecoratedText struct {
+	TopLabel string `json:"topLabel,omitempty"`
+	Text     string `json:"text"`
+	WrapText bool   `json:"wrapText"`
+}
+
+// googleChatButtonList はボタンの一覧のウィジェットです。
+type googleChatButtonList struct {
+	Buttons []googleChatButton `json:"buttons"`
+}
+
+// googleChatButton は、URL を開くボタンです。
+type googleChatButton struct {
+	Text    string            `json:"text"`
+	OnClick googleChatOnClick `json:"onClick"`
+}
+
+// googleChatOnClick は、ボタンをクリックしたときの動作です。
+type googleChatOnClick struct {
+	OpenLink googleChatOpenLink `json:"openLink"`
+}
+
+// googleChatOpenLink は、ボタンで開く URL です。
+type googleChatOpenLink struct {
+	URL string `json:"url"`
+}
+
+// googleChatMessageResponse は、投稿したメッセージです。
+type googleChatMessageResponse struct {
+	// Name: メッセージのリソース名 (spaces/{space}/messages/{message})
+	Name   string           `json:"name"`
+	Thread googleChatThread `json:"thread"`
+}
+
+// --- Notifier インターフェース実装 ---
+
+// Send は、Message を Google Chat のカード (Cards v2) に変換して投稿します。
+// Title はカードのヘッダー、Severity はヘッダーのサブタイトル、Body はテキストのセクション (水平線でセクションを区切ります)、
+// Fields は decoratedText、Links はボタン、Tags と Labels は送信時刻とともにフッターとして描画されます。
+// ファイルのアップロードには対応していないため、Attachments は無視されます。
+// ThreadKey が設定されている場合は、同じキーのスレッドに返信します (スレッドがなければ新しいスレッドを開始します)。
+// SendResult の ID にはメッセージのリソース名、Channel にはスレッドのリソース名が設定されます。
+func (g *GoogleChatNotifier) Send(ctx context.Context, msg Message) (*SendResult, error) {
+	var rec truncationRecorder
+	header := msg.Title
+	if header == "" {
+		header = defaultHeader(msg.Body)
+	}
+
+	subtitle := fmt.Sprintf("%s %s", severityOrDefault(msg.Severity).Emoji(), severityOrDefault(msg.Severity))
+	card := buildGoogleChatCard(header, subtitle, msg.Body, googleChatMessageSections(msg), googleChatFooter(msg), &rec)
+	resp, err := g.post(ctx, card)
+	if err != nil {
+		return nil, err
+	}
+	return &SendResult{Backend: "gchat", ID: resp.Name, Channel: resp.Thread.Name, Truncations: rec.truncations}, nil
+}
+
+// SendTextWithHeader は、ヘッダー付きのテキストメッセージをカードに変換して投稿します。
+// headerText はカードのヘッダーとして、message は本文 (Markdown として解釈可能) として描画されます。
+func (g *GoogleChatNotifier) SendTextWithHeader(ctx context.Context, headerText string, message string) error {
+	_, err := g.post(ctx, buildGoogleChatCard(headerText, "", message, nil, "", nil))
+	return err
+}
+
+// SendText は、プレーンテキストメッセージを通知します。（ヘッダーなし）
+// 本文の1行目からデフォルトヘッダーを生成し、SendTextWithHeader にフォールバックします。
+func (g *GoogleChatNotifier) SendText(ctx context.Context, message string) error {
+	return g.SendTextWithHeader(ctx, defaultHeader(message), message)
+}
+
+// post は、カードを Webhook で送信し、投稿したメッセージを返します。
+func (g *GoogleChatNotifier) post(ctx context.Context, card googleChatCard) (*googleChatMessageResponse, error) {
+	payload := googleChatPayload{
+		CardsV2: []googleChatCardV2{{CardID: "notification", Card: card}},
+	}
+
+	endpoint := g.WebhookURL
+	if g.ThreadKey != "" {
+		u, err := url.Parse(g.WebhookURL)
+		if err != nil {
+			return nil, fmt.Errorf("Google Chat の Webhook URL が不正です: %w", err)
+		}
+		query := u.Query()
+		query.Set("messageReplyOption", googleChatReplyOption)
+		u.RawQuery = query.Encode()
+		endpoint = u.String()
+		payload.Thread = &googleChatThread{ThreadKey: g.ThreadKey}
+	}
+
+	respBody, err := g.client.PostJSONAndFetchBytes(endpoint, payload, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Google Chat Webhookメッセージの送信に失敗しました: %w", err)
+	}
+
+	var resp googleChatMessageResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("Google Chat Webhookの送信結果のパースに失敗しました: %w", err)
+	}
+	return &resp, nil
+}
+
+// --- カードの構築 ---
+
+// buildGoogleChatCard は、ヘッダー・本文・extras・フッター (送信時刻) からカードを構築します。
+// 本文は GoogleChatBodyLimit 文字を超える場合に切り詰められ、rec に記録されます (rec は nil でも構いません)。
+func buildGoogleChatCard(headerText, subtitle, message string, extras []googleChatSection, footerText string, rec *truncationRecorder) googleChatCard {
+	message = rec.truncate("body", message, GoogleChatBodyLimit)
+
+	var sections []googleChatSection
+	for _, blocks := range markdownToChatSections(message) {
+		widgets := make([]googleChatWidget, 0, len(blocks))
+		for _, b := range blocks {
+			widgets = append(widgets, googleChatWidget{TextParagraph: &googleChatTextParagraph{Text: b}})
+		}
+		sections = append(sections, googleChatSection{Widgets: widgets})
+	}
+	sections = append(sections, extras...)
+
+	// フッターには送信時刻を含める
+	footer := fmt.Sprintf("送信時刻: %s", time.Now().Format("2006-01-02 15:04:05"))
+	if footerText != "" {
+		footer = html.EscapeString(footerText) + "<br>" + footer
+	}
+	sections = append(sections, googleChatSection{Widgets: []googleChatWidget{{
+		TextParagraph: &googleChatTextParagraph{Text: `<font color="#80868b">` + footer + "</font>"},
+	}}})
+
+	return googleChatCard{
+		Header:   &googleChatCardHeader{Title: headerText, Subtitle: subtitle},
+		Sections: sections,
+	}
+}
+
+// googleChatMessageSections は、Message の Fields と Links をカードのセクションに変換します。
+func googleChatMessageSections(msg Message) []googleChatSection {
+	var widgets []googleChatWidget
+	for _, f := range msg.Fields {
+		widgets = append(widgets, googleChatWidget{DecoratedText: &googleChatDecoratedText{
+			TopLabel: f.Name,
+			Text:     html.EscapeString(f.Value),
+			WrapText: true,
+		}})
+	}
+
+	if len(msg.Links) > 0 {
+		buttons := make([]googleChatButton, 0, len(msg.Links))
+		for _, l := range msg.Links {
+			text := l.Text
+			if text == "" {
+				text = l.URL
+			}
+			buttons = append(buttons, googleChatButton{
+				Text:    text,
+				OnClick: googleChatOnClick{OpenLink: googleChatOpenLink{URL: l.URL}},
+			})
+		}
+		widgets = append(widgets, googleChatWidget{ButtonList: &googleChatButtonList{Buttons: buttons}})
+	}
+
+	if len(widgets) == 0 {
+		return nil
+	}
+	return []googleChatSection{{Widgets: widgets}}
+}
+
+// googleChatFooter は、Message の Tags と Labels をフッターのテキストに変換します。
+// 重要度はヘッダーのサブタイトルに表示するため含めません。
+func googleChatFooter(msg Message) string {
+	var meta []string
+	if len(msg.Tags) > 0 {
+		meta = append(meta, strings.Join(msg.Tags, ", "))
+	}
+	for _, k := range sortedLabelKeys(msg.Labels) {
+		meta = append(meta, fmt.Sprintf("%s: %s", k, msg.Labels[k]))
+	}
+	return strings.Join(meta, " | ")
+}
@@ -0,0 +1,161 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// TestMarkdownToChatSectionsGolden は、testdata/chathtml/*.md の変換結果を同名の *.golden と比較します。
+// golden には、ブロックの HTML を1行ずつ、セクションの区切りを空行として記録します。
+// 変換結果を意図して変更した場合は、go test -run TestMarkdownToChatSectionsGolden -update で更新してください。
+func TestMarkdownToChatSectionsGolden(t *testing.T) {
+	testGolden(t, filepath.Join("testdata", "chathtml"), func(markdown string) string {
+		var sb strings.Builder
+		for i, blocks := range markdownToChatSections(markdown) {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			for _, b := range blocks {
+				sb.WriteString(b + "\n")
+			}
+		}
+		return sb.String()
+	})
+}
+
+// fakeGoogleChat は、Google Chat の Webhook を模したテスト用のサーバーです。
+type fakeGoogleChat struct {
+	query   []string
+	payload googleChatPayload
+}
+
+func newTestGoogleChatNotifier(t *testing.T, fake *fakeGoogleChat) *GoogleChatNotifier {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.query = append(fake.query, r.URL.RawQuery)
+		if err := json.NewDecoder(r.Body).Decode(&fake.payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		thread := "spaces/AAA/threads/new"
+		if fake.payload.Thread != nil {
+			thread = "spaces/AAA/threads/" + fake.payload.Thread.ThreadKey
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"name":   "spaces/AAA/messages/BBB",
+			"thread": map[string]string{"name": thread},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client := httpkit.New(5*time.Second, httpkit.WithMaxRetries(0))
+	gn, err := NewGoogleChatNotifier(*client, server.URL+"/v1/spaces/AAA/messages?key=k&token=t")
+	if err != nil {
+		t.Fatalf("NewGoogleChatNotifier: %v", err)
+	}
+	return gn
+}
+
+func TestGoogleChatSendCard(t *testing.T) {
+	fake := &fakeGoogleChat{}
+	gn := newTestGoogleChatNotifier(t, fake)
+
+	result, err := gn.Send(context.Background(), Message{
+		Title:    "バッチ失敗",
+		Body:     "**夜間バッチ**が失敗しました\n\n---\n\n詳細はログを確認してください",
+		Severity: SeverityError,
+		Fields:   []Field{{Name: "ジョブ", Value: "<nightly>"}},
+		Links:    []Link{{Text: "ログ", URL: "https://example.com/log"}},
+		Tags:     []string{"batch"},
+		Labels:   map[string]string{"env": "prod"},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.ID != "spaces/AAA/messages/BBB" || result.Channel != "spaces/AAA/threads/new" || len(result.Truncations) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+	if fake.query[0] != "key=k&token=t" {
+		t.Errorf("query = %q, want the webhook URL unchanged", fake.query[0])
+	}
+
+	if fake.payload.Thread != nil || len(fake.payload.CardsV2) != 1 || fake.payload.CardsV2[0].CardID != "notification" {
+		t.Fatalf("payload = %+v", fake.payload)
+	}
+	card := fake.payload.CardsV2[0].Card
+	if card.Header == nil || card.Header.Title != "バッチ失敗" || card.Header.Subtitle != "🚨 error" {
+		t.Errorf("header = %+v", card.Header)
+	}
+
+	// 本文の2セクション、Fields と Links、フッター
+	if len(card.Sections) != 4 {
+		t.Fatalf("got %d sections, want 4: %+v", len(card.Sections), card.Sections)
+	}
+	if got := card.Sections[0].Widgets[0].TextParagraph.Text; got != "<b>夜間バッチ</b>が失敗しました" {
+		t.Errorf("first section = %q", got)
+	}
+	if got := card.Sections[1].Widgets[0].TextParagraph.Text; got != "詳細はログを確認してください" {
+		t.Errorf("second section = %q", got)
+	}
+	extras := card.Sections[2].Widgets
+	if len(extras) != 2 {
+		t.Fatalf("extras = %+v", extras)
+	}
+	if d := extras[0].DecoratedText; d == nil || d.TopLabel != "ジョブ" || d.Text != "&lt;nightly&gt;" || !d.WrapText {
+		t.Errorf("decoratedText = %+v", d)
+	}
+	if b := extras[1].ButtonList; b == nil || len(b.Buttons) != 1 || b.Buttons[0].Text != "ログ" || b.Buttons[0].OnClick.OpenLink.URL != "https://example.com/log" {
+		t.Errorf("buttonList = %+v", b)
+	}
+	footer := card.Sections[3].Widgets[0].TextParagraph.Text
+	if !strings.HasPrefix(footer, `<font color="#80868b">batch | env: prod<br>送信時刻: `) {
+		t.Errorf("footer = %q", footer)
+	}
+}
+
+func TestGoogleChatSendThreadKey(t *testing.T) {
+	fake := &fakeGoogleChat{}
+	gn := newTestGoogleChatNotifier(t, fake)
+	gn.ThreadKey = "batch-nightly"
+
+	result, err := gn.Send(context.Background(), Message{Body: "本文"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.Channel != "spaces/AAA/threads/batch-nightly" {
+		t.Errorf("Channel = %q", result.Channel)
+	}
+	if fake.payload.Thread == nil || fake.payload.Thread.ThreadKey != "batch-nightly" {
+		t.Errorf("thread = %+v", fake.payload.Thread)
+	}
+	// 既存のクエリ (key, token) を残したまま messageReplyOption を追加する
+	want := "key=k&messageReplyOption=" + googleChatReplyOption + "&token=t"
+	if fake.query[0] != want {
+		t.Errorf("query = %q, want %q", fake.query[0], want)
+	}
+}
+
+func TestGoogleChatSendTruncatesBody(t *testing.T) {
+	fake := &fakeGoogleChat{}
+	gn := newTestGoogleChatNotifier(t, fake)
+
+	result, err := gn.Send(context.Background(), Message{Title: "長い本文", Body: strings.Repeat("あ", GoogleChatBodyLimit+1)})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	want := Truncation{Field: "body", Length: GoogleChatBodyLimit + 1, Limit: GoogleChatBodyLimit}
+	if len(result.Truncations) != 1 || result.Truncations[0] != want {
+		t.Errorf("truncations = %v, want [%v]", result.Truncations, want)
+	}
+	if n := TextLength(fake.payload.CardsV2[0].Card.Sections[0].Widgets[0].TextParagraph.Text); n != GoogleChatBodyLimit {
+		t.Errorf("body has %d characters, want %d", n, GoogleChatBodyLimit)
+	}
+}
@@ -0,0 +1,12 @@
+package notifier
+
+import "regexp"
+
+// Markdown をパースせずに行単位で変換する通知先 (Teams の TextBlock、Chatwork の記法) が共通で使用する、
+// 1行で完結する Markdown の記法を検出する正規表現
+var (
+	// markdownHeadingRegex: ATX 形式の見出し (例: "## 概要")。1番目のグループは見出しのテキスト
+	markdownHeadingRegex = regexp.MustCompile(`^ {0,3}#{1,6}\s+(.*?)(?:\s+#+)?\s*$`)
+	// markdownRuleRegex: 水平線 (例: "---", "***")
+	markdownRuleRegex = regexp.MustCompile(`^ {0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+)
@@ -0,0 +1,440 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/uniseg"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// mrkdwnSegmentKind は、Markdown から変換されたセグメントの種類です。
+type mrkdwnSegmentKind int
+
+const (
+	// segmentText は mrkdwn テキスト (section ブロックとして描画)
+	segmentText mrkdwnSegmentKind = iota
+	// segmentCode はコードブロック (rich_text の preformatted として描画)
+	segmentCode
+	// segmentDivider は水平線 (divider ブロックとして描画)
+	segmentDivider
+)
+
+// mrkdwnSegment は、Markdown から変換された Slack 向けの表示単位です。
+type mrkdwnSegment struct {
+	kind mrkdwnSegmentKind
+	// text: segmentText では mrkdwn 形式 (エスケープ済み)、segmentCode ではコードの生テキスト
+	text string
+}
+
+// markdownParser は GFM (打ち消し線・表・タスクリスト・自動リンク) に対応した CommonMark パーサーです。
+var markdownParser = goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser()
+
+// MarkdownToMrkdwn は、Markdown テキストを Slack の mrkdwn 形式のテキストに変換します。
+// コードブロックは ``` で囲まれた整形済みテキストに、水平線は罫線に変換されます。
+func MarkdownToMrkdwn(markdown string) string {
+	segments := markdownToSegments(markdown)
+	parts := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		switch seg.kind {
+		case segmentCode:
+			parts = append(parts, "```\n"+escapeMrkdwn(seg.text)+"\n```")
+		case segmentDivider:
+			parts = append(parts, "──────────")
+		default:
+			parts = append(parts, seg.text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// markdownToSegments は、Markdown テキストを AST に変換し、Slack 向けのセグメント列に変換します。
+// テキスト系のブロック (段落・見出し・リスト・引用・表) は、トップレベルのブロックごとに1つのテキストセグメントになります。
+func markdownToSegments(markdown string) []mrkdwnSegment {
+	source := []byte(markdown)
+	doc := markdownParser.Parse(text.NewReader(source))
+
+	r := &mrkdwnRenderer{source: source}
+	for n := doc.FirstChild(); n != nil; n = n.NextSibling() {
+		r.renderTopLevel(n)
+	}
+	return r.segments
+}
+
+// mrkdwnRenderer は、goldmark の AST を mrkdwn に変換するレンダラーです。
+type mrkdwnRenderer struct {
+	source   []byte
+	segments []mrkdwnSegment
+}
+
+// renderTopLevel は、ドキュメント直下のブロックをセグメントに変換します。
+func (r *mrkdwnRenderer) renderTopLevel(n ast.Node) {
+	switch n := n.(type) {
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		r.segments = append(r.segments, mrkdwnSegment{kind: segmentCode, text: r.lines(n)})
+	case *ast.ThematicBreak:
+		r.segments = append(r.segments, mrkdwnSegment{kind: segmentDivider})
+	default:
+		if block := r.block(n, 0); block != "" {
+			r.segments = append(r.segments, mrkdwnSegment{kind: segmentText, text: block})
+		}
+	}
+}
+
+// block は、ブロック要素を mrkdwn テキストに変換します。depth はリストの入れ子の深さです。
+func (r *mrkdwnRenderer) block(n ast.Node, depth int) string {
+	switch n := n.(type) {
+	case *ast.Paragraph, *ast.TextBlock:
+		return r.inlines(n, false)
+	case *ast.Heading:
+		// mrkdwn には見出しがないため、太字で表現する
+		if title := r.inlines(n, true); title != "" {
+			return "*" + title + "*"
+		}
+		return ""
+	case *ast.List:
+		return r.list(n, depth)
+	case *ast.Blockquote:
+		return prefixLines(r.children(n, depth, "\n"), "> ")
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		// リストや引用の内側のコードブロックは、mrkdwn のコードブロックとして描画する
+		return "```\n" + escapeMrkdwn(r.lines(n)) + "\n```"
+	case *ast.ThematicBreak:
+		return "──────────"
+	case *ast.HTMLBlock:
+		return escapeMrkdwn(strings.TrimRight(r.lines(n), "\n"))
+	case *east.Table:
+		return r.table(n)
+	default:
+		return r.children(n, depth, "\n\n")
+	}
+}
+
+// children は、子ブロックを変換して sep で連結します。
+func (r *mrkdwnRenderer) children(n ast.Node, depth int, sep string) string {
+	var parts []string
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if s := r.block(c, depth); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// listBullets は、入れ子の深さごとの箇条書き記号です。
+var listBullets = []string{"•", "◦", "▪"}
+
+// list は、箇条書き・番号付きリストを、入れ子の深さに応じたインデント付きで変換します。
+func (r *mrkdwnRenderer) list(n *ast.List, depth int) string {
+	indent := strings.Repeat("    ", depth)
+	number := n.Start
+	if number == 0 {
+		number = 1
+	}
+
+	var lines []string
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		marker := listBullets[depth%len(listBullets)]
+		if n.IsOrdered() {
+			marker = fmt.Sprintf("%d.", number)
+			number++
+		}
+
+		var body []string
+		for c := item.FirstChild(); c != nil; c = c.NextSibling() {
+			if nested, ok := c.(*ast.List); ok {
+				body = append(body, r.list(nested, depth+1))
+				continue
+			}
+			if s := r.block(c, depth+1); s != "" {
+				// 項目内の2行目以降は、マーカーの位置に揃えてインデントする
+				body = append(body, prefixContinuation(s, indent+"    "))
+			}
+		}
+
+		if len(body) == 0 {
+			lines = append(lines, indent+marker)
+			continue
+		}
+		if _, nested := item.FirstChild().(*ast.List); nested {
+			lines = append(lines, indent+marker)
+			lines = append(lines, body...)
+			continue
+		}
+		lines = append(lines, indent+marker+" "+body[0])
+		lines = append(lines, body[1:]...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// table は、表を等幅のコードブロックとして整形します (mrkdwn には表がないため)。
+func (r *mrkdwnRenderer) table(n *east.Table) string {
+	var rows [][]string
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		var cells []string
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			cells = append(cells, r.plain(cell))
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	// 列幅は表示幅 (全角文字は2) で計算する
+	var widths []int
+	for _, cells := range rows {
+		for i, c := range cells {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], uniseg.StringWidth(c))
+		}
+	}
+
+	formatRow := func(cells []string) string {
+		padded := make([]string, len(widths))
+		for i := range widths {
+			c := ""
+			if i < len(cells) {
+				c = cells[i]
+			}
+			padded[i] = c + strings.Repeat(" ", widths[i]-uniseg.StringWidth(c))
+		}
+		return strings.TrimRight(strings.Join(padded, " | "), " ")
+	}
+
+	lines := []string{formatRow(rows[0])}
+	separators := make([]string, len(widths))
+	for i, w := range widths {
+		separators[i] = strings.Repeat("-", max(w, 1))
+	}
+	lines = append(lines, strings.Join(separators, "-+-"))
+	for _, cells := range rows[1:] {
+		lines = append(lines, formatRow(cells))
+	}
+
+	return "```\n" + escapeMrkdwn(strings.Join(lines, "\n")) + "\n```"
+}
+
+// inlines は、インライン要素を mrkdwn テキストに変換します。
+// plainEmphasis が true の場合、強調記号を出力しません (見出しを太字で囲む場合の入れ子防止)。
+func (r *mrkdwnRenderer) inlines(n ast.Node, plainEmphasis bool) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		r.inline(&sb, c, plainEmphasis)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// inline は、1つのインライン要素を mrkdwn テキストとして書き込みます。
+func (r *mrkdwnRenderer) inline(sb *strings.Builder, n ast.Node, plainEmphasis bool) {
+	switch n := n.(type) {
+	case *ast.Text:
+		sb.WriteString(escapeMrkdwn(r.unescape(n.Segment.Value(r.source))))
+		if n.HardLineBreak() || n.SoftLineBreak() {
+			sb.WriteString("\n")
+		}
+	case *ast.String:
+		sb.WriteString(escapeMrkdwn(string(n.Value)))
+	case *ast.CodeSpan:
+		sb.WriteString("`" + escapeMrkdwn(r.rawText(n)) + "`")
+	case *ast.Emphasis:
+		marker := "_"
+		if n.Level >= 2 {
+			marker = "*"
+		}
+		if plainEmphasis {
+			marker = ""
+		}
+		sb.WriteString(marker + r.inlines(n, plainEmphasis) + marker)
+	case *east.Strikethrough:
+		sb.WriteString("~" + r.inlines(n, plainEmphasis) + "~")
+	case *ast.Link:
+		sb.WriteString(mrkdwnLink(string(n.Destination), r.inlines(n, true)))
+	case *ast.AutoLink:
+		url := string(n.URL(r.source))
+		if n.AutoLinkType == ast.AutoLinkEmail && !strings.HasPrefix(url, "mailto:") {
+			url = "mailto:" + url
+		}
+		sb.WriteString(mrkdwnLink(url, escapeMrkdwn(string(n.Label(r.source)))))
+	case *ast.Image:
+		sb.WriteString(mrkdwnLink(string(n.Destination), r.inlines(n, true)))
+	case *east.TaskCheckBox:
+		if n.IsChecked {
+			sb.WriteString("☑ ")
+		} else {
+			sb.WriteString("☐ ")
+		}
+	case *ast.RawHTML:
+		for i := 0; i < n.Segments.Len(); i++ {
+			seg := n.Segments.At(i)
+			sb.WriteString(escapeMrkdwn(string(seg.Value(r.source))))
+		}
+	default:
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			r.inline(sb, c, plainEmphasis)
+		}
+	}
+}
+
+// plain は、インライン要素を書式なしのテキストとして取り出します (表のセルなど)。
+func (r *mrkdwnRenderer) plain(n ast.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			sb.WriteString(r.unescape(c.Segment.Value(r.source)))
+		case *ast.String:
+			sb.WriteString(string(c.Value))
+		case *ast.CodeSpan:
+			sb.WriteString(r.rawText(c))
+		case *ast.RawHTML:
+			for i := 0; i < c.Segments.Len(); i++ {
+				seg := c.Segments.At(i)
+				sb.Write(seg.Value(r.source))
+			}
+		default:
+			sb.WriteString(r.plain(c))
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// rawText は、コードスパンの内容をエスケープ処理なしで取り出します。
+func (r *mrkdwnRenderer) rawText(n ast.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		switch c := c.(type) {
+		case *ast.Text:
+			sb.Write(c.Segment.Value(r.source))
+		case *ast.String:
+			sb.Write(c.Value)
+		}
+	}
+	return sb.String()
+}
+
+// lines は、コードブロックなどの行の内容を連結して返します (末尾の改行は除去)。
+func (r *mrkdwnRenderer) lines(n ast.Node) string {
+	var sb strings.Builder
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		seg := lines.At(i)
+		sb.Write(seg.Value(r.source))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// unescape は、Markdown のバックスラッシュエスケープと文字参照を解決します。
+func (r *mrkdwnRenderer) unescape(value []byte) string {
+	return string(util.UnescapePunctuations(util.ResolveNumericReferences(util.ResolveEntityNames(value))))
+}
+
+// mrkdwnLink は、URL と表示テキストから mrkdwn のリンク記法を生成します。
+func mrkdwnLink(url, label string) string {
+	if label == "" || label == escapeMrkdwn(url) {
+		return "<" + url + ">"
+	}
+	// 表示テキスト中の "|" はリンク記法の区切りと衝突するため置き換える
+	return "<" + url + "|" + strings.ReplaceAll(label, "|", "│") + ">"
+}
+
+// mrkdwnEscaper は、mrkdwn で制御文字として扱われる &, <, > をエスケープします。
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// escapeMrkdwn は、テキストを mrkdwn 用にエスケープします。
+func escapeMrkdwn(s string) string {
+	return mrkdwnEscaper.Replace(s)
+}
+
+// prefixLines は、各行の先頭に prefix を付与します。
+func prefixLines(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+// prefixContinuation は、2行目以降の各行の先頭に prefix を付与します。
+func prefixContinuation(s, prefix string) string {
+	first, rest, ok := strings.Cut(s, "\n")
+	if !ok {
+		return s
+	}
+	return first + "\n" + prefixLines(rest, prefix)
+}
+
+// codeFence は mrkdwn のコードブロックの区切りです。
+const codeFence = "```"
+
+// splitLines は、テキストを limit 文字以下のチャンクに行単位で分割します。
+// limit を超える1行は書記素クラスタの境界で分割します。fenceAware が true の場合、mrkdwn のコードブロックの途中で分割するときは
+// フェンスを閉じ、次のチャンクの先頭で開き直すため、各チャンクのコードブロックは完結したものになります。
+func splitLines(text string, limit int, fenceAware bool) []string {
+	// フェンスを閉じて開き直すための余白 ("\n```" と "```\n")
+	reserve := 0
+	if fenceAware {
+		reserve = 2 * (len(codeFence) + 1)
+	}
+	lineLimit := max(limit-reserve, 1)
+
+	var chunks []string
+	var current []string
+	currentLength := 0
+	inFence := false
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		if inFence {
+			current = append(current, codeFence)
+		}
+		chunks = append(chunks, strings.Join(current, "\n"))
+		current = nil
+		currentLength = 0
+		if inFence {
+			current = []string{codeFence}
+			currentLength = len(codeFence)
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		for _, piece := range splitGraphemes(line, lineLimit) {
+			n := TextLength(piece)
+			closing := 0
+			if inFence {
+				closing = len(codeFence) + 1
+			}
+			if len(current) > 0 && currentLength+1+n+closing > limit {
+				flush()
+			}
+			if len(current) > 0 {
+				currentLength++
+			}
+			current = append(current, piece)
+			currentLength += n
+		}
+
+		if fenceAware && isFenceLine(line) {
+			inFence = !inFence
+		}
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, strings.Join(current, "\n"))
+	}
+	return chunks
+}
+
+// isFenceLine は、行がコードブロックの開始・終了 (リストや引用の記号に続くものを含む) かを判定します。
+func isFenceLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == codeFence || strings.HasSuffix(trimmed, " "+codeFence)
+}
@@ -0,0 +1,55 @@
+package notifier
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "testdata の *.golden を現在の出力で更新する")
+
+// TestMarkdownToMrkdwnGolden は、testdata/mrkdwn/*.md の変換結果を同名の *.golden と比較します。
+// 変換結果を意図して変更した場合は、go test -run TestMarkdownToMrkdwnGolden -update で更新してください。
+func TestMarkdownToMrkdwnGolden(t *testing.T) {
+	testGolden(t, filepath.Join("testdata", "mrkdwn"), MarkdownToMrkdwn)
+}
+
+// testGolden は、dir/*.md を convert で変換した結果を、同名の *.golden と比較します。
+// -update を指定した場合は、*.golden を現在の変換結果で更新します。
+func testGolden(t *testing.T, dir string, convert func(markdown string) string) {
+	t.Helper()
+	inputs, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatalf("%s に入力ファイルがありません", dir)
+	}
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".md")
+		t.Run(name, func(t *testing.T) {
+			markdown, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := convert(string(markdown))
+
+			golden := strings.TrimSuffix(input, ".md") + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("%s の変換結果 =\n%s\nwant:\n%s", input, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MultiPolicy は MultiNotifier の成否判定ポリシーです。
+type MultiPolicy int
+
+const (
+	// PolicyBestEffort は、すべての通知先に送信し、1件でも成功すれば成功とみなします。
+	PolicyBestEffort MultiPolicy = iota
+	// PolicyFailFast は、最初の失敗で残りの送信をキャンセルし、エラーを返します。
+	PolicyFailFast
+	// PolicyRequireN は、すべての通知先に送信し、RequiredSuccesses 件以上の成功を要求します。
+	PolicyRequireN
+)
+
+// NamedNotifier は MultiNotifier に登録する名前付きの通知先です。
+type NamedNotifier struct {
+	Name     string
+	Notifier Notifier
+}
+
+// TargetResult は通知先ごとの送信結果です。
+type TargetResult struct {
+	Name   string
+	Result *SendResult
+	Err    error
+}
+
+// MultiResult は MultiNotifier による送信結果を、登録順に保持します。
+type MultiResult struct {
+	Results []TargetResult
+}
+
+// sendResult は、通知先ごとの結果を、Notifier.Send の結果としてまとめます。
+// 各通知先の切り詰めた項目は、通知先名を付けて Truncations に集めます。
+func (r *MultiResult) sendResult(backend string) *SendResult {
+	result := &SendResult{Backend: backend, Targets: r.Results}
+	for _, tr := range r.Results {
+		if tr.Result == nil {
+			continue
+		}
+		for _, t := range tr.Result.Truncations {
+			t.Field = tr.Name + "/" + t.Field
+			result.Truncations = append(result.Truncations, t)
+		}
+	}
+	return result
+}
+
+// Successes は成功した通知先の数を返します。
+func (r *MultiResult) Successes() int {
+	n := 0
+	for _, tr := range r.Results {
+		if tr.Err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Err は失敗した通知先のエラーを errors.Join で結合して返します。失敗がなければ nil を返します。
+func (r *MultiResult) Err() error {
+	var errs []error
+	for _, tr := range r.Results {
+		if tr.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", tr.Name, tr.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MultiNotifier は、1つのメッセージを複数の通知先へ並行して送信するコンポジットです。
+// Notifier インターフェースを実装するため、単一の通知先と同様に扱えます。
+type MultiNotifier struct {
+	targets []NamedNotifier
+	// Policy: 成否判定ポリシー
+	Policy MultiPolicy
+	// RequiredSuccesses: PolicyRequireN で要求する成功数 (0 以下の場合はすべての通知先)。通知先の数を超える場合はエラーになります
+	RequiredSuccesses int
+	// MaxConcurrency: 同時に送信する通知先の上限 (0 以下の場合は通知先の数)
+	MaxConcurrency int
+}
+
+// NewMultiNotifier は MultiNotifier の新しいインスタンスを作成します。
+// Notifier が nil の通知先が含まれる場合は、送信時のパニックを防ぐためエラーを返します。
+func NewMultiNotifier(policy MultiPolicy, targets ...NamedNotifier) (*MultiNotifier, error) {
+	for i, target := range targets {
+		if target.Notifier == nil {
+			return nil, fmt.Errorf("MultiNotifier: 通知先 %q (%d 番目) の Notifier が nil です", target.Name, i+1)
+		}
+	}
+	return &MultiNotifier{
+		targets: targets,
+		Policy:  policy,
+	}, nil
+}
+
+// Send は、すべての通知先へ送信し、ポリシーに従って成否を判定します。
+// SendResult の Targets に通知先ごとの結果が、Truncations に各通知先で切り詰めた項目が設定されます。
+func (m *MultiNotifier) Send(ctx context.Context, msg Message) (*SendResult, error) {
+	result, err := m.SendAll(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	return result.sendResult("multi"), nil
+}
+
+// SendAll は、すべての通知先へ並行して送信し、通知先ごとの結果を返します。
+// ポリシーを満たさない場合、失敗した通知先のエラーを結合したエラーを返します。
+// ポリシーを満たした場合でも、個別の失敗は MultiResult.Err で確認できます。
+func (m *MultiNotifier) SendAll(ctx context.Context, msg Message) (*MultiResult, error) {
+	if len(m.targets) == 0 {
+		return nil, errors.New("MultiNotifier: 通知先が登録されていません")
+	}
+	// 満たせない要求数は、送信する前に設定の誤りとして扱う
+	if m.Policy == PolicyRequireN && m.RequiredSuccesses > len(m.targets) {
+		return nil, fmt.Errorf("MultiNotifier: 要求する成功数 (%d) が通知先の数 (%d) を超えています", m.RequiredSuccesses, len(m.targets))
+	}
+
+	// 添付ファイルの io.Reader は一度しか読めないため、通知先ごとに複製できるようバッファリングする
+	attachments, err := bufferAttachments(msg.Attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := m.MaxConcurrency
+	if workers <= 0 || workers > len(m.targets) {
+		workers = len(m.targets)
+	}
+
+	result := &MultiResult{Results: make([]TargetResult, len(m.targets))}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				target := m.targets[i]
+				tr := TargetResult{Name: target.Name}
+
+				if err := ctx.Err(); err != nil {
+					// キャンセル済み (fail-fast または呼び出し元のキャンセル) の場合は送信しない
+					tr.Err = err
+				} else {
+					targetMsg := msg
+					targetMsg.Attachments = attachments.clone()
+					tr.Result, tr.Err = target.Notifier.Send(ctx, targetMsg)
+				}
+
+				if tr.Err != nil && m.Policy == PolicyFailFast {
+					cancel()
+				}
+				result.Results[i] = tr
+			}
+		}()
+	}
+
+	for i := range m.targets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return result, m.evaluate(result)
+}
+
+// evaluate は、ポリシーに従って送信結果の成否を判定します。
+func (m *MultiNotifier) evaluate(result *MultiResult) error {
+	joined := result.Err()
+	if joined == nil {
+		return nil
+	}
+
+	successes := result.Successes()
+	switch m.Policy {
+	case PolicyFailFast:
+		return fmt.Errorf("MultiNotifier: 送信に失敗した通知先があります: %w", joined)
+	case PolicyRequireN:
+		required := m.RequiredSuccesses
+		if required <= 0 {
+			required = len(m.targets)
+		}
+		if successes < required {
+			return fmt.Errorf("MultiNotifier: 成功数が要求数に達しませんでした (%d/%d): %w", successes, required, joined)
+		}
+	default:
+		if successes == 0 {
+			return fmt.Errorf("MultiNotifier: すべての通知先への送信に失敗しました: %w", joined)
+		}
+	}
+
+	return nil
+}
+
+// bufferedAttachments は、メモリ上に読み込んだ添付ファイルの一覧です。
+type bufferedAttachments []bufferedAttachment
+
+type bufferedAttachment struct {
+	filename    string
+	contentType string
+	data        []byte
+}
+
+// bufferAttachments は、添付ファイルの内容をメモリに読み込みます。
+func bufferAttachments(attachments []Attachment) (bufferedAttachments, error) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	buffered := make(bufferedAttachments, 0, len(attachments))
+	for _, a := range attachments {
+		var data []byte
+		if a.Content != nil {
+			var err error
+			data, err = io.ReadAll(a.Content)
+			if err != nil {
+				return nil, fmt.Errorf("添付ファイル %s の読み込みに失敗しました: %w", a.Filename, err)
+			}
+		}
+		buffered = append(buffered, bufferedAttachment{filename: a.Filename, contentType: a.ContentType, data: data})
+	}
+	return buffered, nil
+}
+
+// clone は、通知先ごとに独立して読み込める Attachment のスライスを返します。
+func (b bufferedAttachments) clone() []Attachment {
+	if len(b) == 0 {
+		return nil
+	}
+
+	attachments := make([]Attachment, 0, len(b))
+	for _, a := range b {
+		attachments = append(attachments, Attachment{
+			Filename:    a.filename,
+			ContentType: a.contentType,
+			Content:     bytes.NewReader(a.data),
+		})
+	}
+	return attachments
+}
@@ -0,0 +1,284 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// notifierFunc は、関数を Notifier として扱うテスト用の型です。
+type notifierFunc func(ctx context.Context, msg Message) (*SendResult, error)
+
+func (f notifierFunc) Send(ctx context.Context, msg Message) (*SendResult, error) {
+	return f(ctx, msg)
+}
+
+// succeed は、常に成功する Notifier を返します。
+func succeed(backend string) Notifier {
+	return notifierFunc(func(context.Context, Message) (*SendResult, error) {
+		return &SendResult{Backend: backend}, nil
+	})
+}
+
+// fail は、常に err を返す Notifier を返します。
+func fail(err error) Notifier {
+	return notifierFunc(func(context.Context, Message) (*SendResult, error) {
+		return nil, err
+	})
+}
+
+func newTestMultiNotifier(t *testing.T, policy MultiPolicy, targets ...NamedNotifier) *MultiNotifier {
+	t.Helper()
+	m, err := NewMultiNotifier(policy, targets...)
+	if err != nil {
+		t.Fatalf("NewMultiNotifier: %v", err)
+	}
+	return m
+}
+
+func TestNewMultiNotifierRejectsNilTarget(t *testing.T) {
+	_, err := NewMultiNotifier(PolicyBestEffort,
+		NamedNotifier{Name: "slack", Notifier: succeed("slack")},
+		NamedNotifier{Name: "backlog"},
+	)
+	if err == nil || !strings.Contains(err.Error(), `"backlog"`) {
+		t.Fatalf("err = %v, want an error naming the nil target", err)
+	}
+}
+
+func TestMultiNotifierPolicies(t *testing.T) {
+	errSlack := errors.New("slack down")
+	errTeams := errors.New("teams down")
+
+	tests := []struct {
+		name     string
+		policy   MultiPolicy
+		required int
+		targets  []NamedNotifier
+		wantErr  bool
+	}{
+		{
+			name:   "best effort: 1件でも成功すれば成功",
+			policy: PolicyBestEffort,
+			targets: []NamedNotifier{
+				{Name: "slack", Notifier: fail(errSlack)},
+				{Name: "discord", Notifier: succeed("discord")},
+				{Name: "teams", Notifier: fail(errTeams)},
+			},
+		},
+		{
+			name:   "best effort: すべて失敗",
+			policy: PolicyBestEffort,
+			targets: []NamedNotifier{
+				{Name: "slack", Notifier: fail(errSlack)},
+				{Name: "teams", Notifier: fail(errTeams)},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "require N: 成功数が足りない",
+			policy:   PolicyRequireN,
+			required: 2,
+			targets: []NamedNotifier{
+				{Name: "slack", Notifier: fail(errSlack)},
+				{Name: "discord", Notifier: succeed("discord")},
+				{Name: "teams", Notifier: fail(errTeams)},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "require N: 成功数を満たす",
+			policy:   PolicyRequireN,
+			required: 1,
+			targets: []NamedNotifier{
+				{Name: "slack", Notifier: fail(errSlack)},
+				{Name: "discord", Notifier: succeed("discord")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMultiNotifier(t, tt.policy, tt.targets...)
+			m.RequiredSuccesses = tt.required
+
+			result, err := m.SendAll(context.Background(), Message{Title: "障害"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(result.Results) != len(tt.targets) {
+				t.Fatalf("got %d results, want %d", len(result.Results), len(tt.targets))
+			}
+			for i, tr := range result.Results {
+				if tr.Name != tt.targets[i].Name {
+					t.Errorf("results[%d].Name = %q, want %q (registration order)", i, tr.Name, tt.targets[i].Name)
+				}
+			}
+
+			// 個別の失敗は、ポリシーを満たした場合でも MultiResult.Err に結合される
+			joined := result.Err()
+			for _, target := range tt.targets {
+				_, sendErr := target.Notifier.Send(context.Background(), Message{})
+				if sendErr == nil {
+					continue
+				}
+				if !errors.Is(joined, sendErr) {
+					t.Errorf("MultiResult.Err() = %v, want it to wrap %v", joined, sendErr)
+				}
+				if !strings.Contains(joined.Error(), target.Name+": ") {
+					t.Errorf("MultiResult.Err() = %v, want it to name %s", joined, target.Name)
+				}
+				if err != nil && !errors.Is(err, sendErr) {
+					t.Errorf("SendAll error %v does not wrap %v", err, sendErr)
+				}
+			}
+		})
+	}
+}
+
+func TestMultiNotifierFailFastCancelsRemaining(t *testing.T) {
+	errSlack := errors.New("slack down")
+
+	t.Run("未送信の通知先は送信しない", func(t *testing.T) {
+		var called atomic.Bool
+		m := newTestMultiNotifier(t, PolicyFailFast,
+			NamedNotifier{Name: "slack", Notifier: fail(errSlack)},
+			NamedNotifier{Name: "teams", Notifier: notifierFunc(func(context.Context, Message) (*SendResult, error) {
+				called.Store(true)
+				return &SendResult{Backend: "teams"}, nil
+			})},
+		)
+		m.MaxConcurrency = 1
+
+		result, err := m.SendAll(context.Background(), Message{})
+		if !errors.Is(err, errSlack) {
+			t.Fatalf("err = %v, want it to wrap %v", err, errSlack)
+		}
+		if called.Load() {
+			t.Error("teams was sent after slack failed")
+		}
+		if !errors.Is(result.Results[1].Err, context.Canceled) {
+			t.Errorf("teams err = %v, want context.Canceled", result.Results[1].Err)
+		}
+	})
+
+	t.Run("送信中の通知先をキャンセルする", func(t *testing.T) {
+		started := make(chan struct{})
+		m := newTestMultiNotifier(t, PolicyFailFast,
+			NamedNotifier{Name: "teams", Notifier: notifierFunc(func(ctx context.Context, _ Message) (*SendResult, error) {
+				close(started)
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(5 * time.Second):
+					return &SendResult{Backend: "teams"}, nil
+				}
+			})},
+			NamedNotifier{Name: "slack", Notifier: notifierFunc(func(context.Context, Message) (*SendResult, error) {
+				<-started
+				return nil, errSlack
+			})},
+		)
+
+		result, err := m.SendAll(context.Background(), Message{})
+		if !errors.Is(err, errSlack) {
+			t.Fatalf("err = %v, want it to wrap %v", err, errSlack)
+		}
+		if !errors.Is(result.Results[0].Err, context.Canceled) {
+			t.Errorf("teams err = %v, want context.Canceled", result.Results[0].Err)
+		}
+	})
+}
+
+func TestMultiNotifierClonesAttachments(t *testing.T) {
+	const content = "2024-01-01 ERROR batch failed\n"
+
+	var mu sync.Mutex
+	received := map[string]string{}
+	reader := func(name string) Notifier {
+		return notifierFunc(func(_ context.Context, msg Message) (*SendResult, error) {
+			if len(msg.Attachments) != 1 {
+				return nil, errors.New("attachment is missing")
+			}
+			a := msg.Attachments[0]
+			data, err := io.ReadAll(a.Content)
+			if err != nil {
+				return nil, err
+			}
+			mu.Lock()
+			received[name] = a.Filename + ":" + a.ContentType + ":" + string(data)
+			mu.Unlock()
+			return &SendResult{Backend: name}, nil
+		})
+	}
+
+	names := []string{"slack", "backlog", "email"}
+	var targets []NamedNotifier
+	for _, name := range names {
+		targets = append(targets, NamedNotifier{Name: name, Notifier: reader(name)})
+	}
+	m := newTestMultiNotifier(t, PolicyRequireN, targets...)
+
+	msg := Message{Attachments: []Attachment{{Filename: "batch.log", ContentType: "text/plain", Content: strings.NewReader(content)}}}
+	if _, err := m.SendAll(context.Background(), msg); err != nil {
+		t.Fatalf("SendAll: %v", err)
+	}
+	for _, name := range names {
+		if want := "batch.log:text/plain:" + content; received[name] != want {
+			t.Errorf("%s received %q, want %q", name, received[name], want)
+		}
+	}
+}
+
+func TestMultiNotifierSendResult(t *testing.T) {
+	errTeams := errors.New("teams down")
+	m := newTestMultiNotifier(t, PolicyBestEffort,
+		NamedNotifier{Name: "slack", Notifier: notifierFunc(func(context.Context, Message) (*SendResult, error) {
+			return &SendResult{Backend: "slack", ID: "1", Truncations: []Truncation{{Field: "body", Length: 3001, Limit: 3000}}}, nil
+		})},
+		NamedNotifier{Name: "teams", Notifier: fail(errTeams)},
+		NamedNotifier{Name: "discord", Notifier: notifierFunc(func(context.Context, Message) (*SendResult, error) {
+			return &SendResult{Backend: "discord", Truncations: []Truncation{{Field: "title", Length: 257, Limit: 256}}}, nil
+		})},
+	)
+
+	result, err := m.Send(context.Background(), Message{Title: "障害"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.Backend != "multi" || len(result.Targets) != 3 {
+		t.Fatalf("result = %+v", result)
+	}
+	if result.Targets[0].Result.ID != "1" || !errors.Is(result.Targets[1].Err, errTeams) {
+		t.Errorf("targets = %+v", result.Targets)
+	}
+	want := []Truncation{
+		{Field: "slack/body", Length: 3001, Limit: 3000},
+		{Field: "discord/title", Length: 257, Limit: 256},
+	}
+	if len(result.Truncations) != len(want) || result.Truncations[0] != want[0] || result.Truncations[1] != want[1] {
+		t.Errorf("truncations = %v, want %v", result.Truncations, want)
+	}
+}
+
+func TestMultiNotifierRejectsUnreachableRequirement(t *testing.T) {
+	var called atomic.Bool
+	m := newTestMultiNotifier(t, PolicyRequireN,
+		NamedNotifier{Name: "slack", Notifier: notifierFunc(func(context.Context, Message) (*SendResult, error) {
+			called.Store(true)
+			return &SendResult{Backend: "slack"}, nil
+		})},
+	)
+	m.RequiredSuccesses = 2
+
+	if _, err := m.SendAll(context.Background(), Message{}); err == nil {
+		t.Fatal("SendAll succeeded with RequiredSuccesses above the number of targets")
+	}
+	if called.Load() {
+		t.Error("slack was sent despite the configuration error")
+	}
+}
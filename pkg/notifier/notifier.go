@@ -0,0 +1,146 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Notifier は、すべての通知先（Slack, Backlog など）が実装する共通インターフェースです。
+// 呼び出し側はこのインターフェースに依存することで、型スイッチなしに通知先を差し替えられます。
+type Notifier interface {
+	// Send は、チャネル非依存の Message を各通知先の形式に変換して送信します。
+	Send(ctx context.Context, msg Message) (*SendResult, error)
+}
+
+// Severity はメッセージの重要度を表します。
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeveritySuccess  Severity = "success"
+	SeverityWarning  Severity = "warning"
+	SeverityError    Severity = "error"
+	SeverityCritical Severity = "critical"
+)
+
+// ParseSeverity は文字列を Severity に変換します。空文字列は SeverityInfo として扱います。
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(strings.ToLower(strings.TrimSpace(s))); sev {
+	case "":
+		return SeverityInfo, nil
+	case SeverityInfo, SeveritySuccess, SeverityWarning, SeverityError, SeverityCritical:
+		return sev, nil
+	default:
+		return "", fmt.Errorf("不明な重要度です: %q (info, success, warning, error, critical のいずれかを指定してください)", s)
+	}
+}
+
+// Rank は重要度の大小を比較するための順位を返します。
+// SeveritySuccess と未設定および不明な値は、SeverityInfo と同じ順位です。
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityWarning:
+		return 1
+	case SeverityError:
+		return 2
+	case SeverityCritical:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Emoji は重要度を表す絵文字を返します。
+func (s Severity) Emoji() string {
+	switch s {
+	case SeveritySuccess:
+		return "✅"
+	case SeverityWarning:
+		return "⚠️"
+	case SeverityError:
+		return "🚨"
+	case SeverityCritical:
+		return "🔥"
+	default:
+		return "ℹ️"
+	}
+}
+
+// Message は、通知先に依存しない共通のメッセージモデルです。
+type Message struct {
+	// Title: メッセージのタイトル（Slack のヘッダー、Backlog の課題サマリーなど）
+	Title string
+	// Body: 本文（Markdown として解釈可能）
+	Body     string
+	Severity Severity
+	// Fields: 名前と値の組で表現される付加情報
+	Fields []Field
+	Links  []Link
+	Tags   []string
+	// Labels: 送信元やサービス名などのラベル (例: "source": "ci", "service": "api")
+	Labels map[string]string
+	// Attachments: 添付ファイル（対応していない通知先では無視されます）
+	Attachments []Attachment
+}
+
+// sortedLabelKeys は、描画順を安定させるためにラベルのキーをソートして返します。
+func sortedLabelKeys(labels map[string]string) []string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Field はメッセージに付与する名前と値の組です。
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Link はメッセージに付与するリンクです。
+type Link struct {
+	Text string
+	URL  string
+}
+
+// Attachment はメッセージに添付するファイルです。
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     io.Reader
+}
+
+// SendResult は Send の結果として、通知先で作成されたリソースの情報を保持します。
+type SendResult struct {
+	// Backend: 送信先の種別 (例: "slack", "backlog", "teams", "discord")
+	Backend string
+	// ID: 通知先で作成されたリソースの識別子（取得できない場合は空）
+	ID string
+	// URL: 通知先で作成されたリソースの URL（取得できない場合は空）
+	URL string
+	// Channel: 投稿先のチャンネル (Slack のボットトークンモードなど、取得できる場合のみ)
+	Channel string
+	// Truncations: 通知先の上限を超えたために切り詰めた (または省略した) 項目。
+	// MultiNotifier / Router の場合は、各通知先の項目を「通知先名/項目名」としてまとめたもの
+	Truncations []Truncation
+	// Targets: MultiNotifier / Router で送信した場合の、通知先ごとの結果 (登録順)
+	Targets []TargetResult
+}
+
+// コンパイル時にインターフェースの実装を保証します。
+var (
+	_ Notifier = (*SlackNotifier)(nil)
+	_ Notifier = (*BacklogNotifier)(nil)
+	_ Notifier = (*TeamsNotifier)(nil)
+	_ Notifier = (*DiscordNotifier)(nil)
+	_ Notifier = (*GoogleChatNotifier)(nil)
+	_ Notifier = (*ChatworkNotifier)(nil)
+	_ Notifier = (*EmailNotifier)(nil)
+	_ Notifier = (*MultiNotifier)(nil)
+	_ Notifier = (*Router)(nil)
+)
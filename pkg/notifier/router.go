@@ -0,0 +1,164 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// TargetResolver は、通知先名から Notifier を取得する関数です。
+// Router はマッチした通知先についてのみ呼び出すため、使用しない通知先の認証情報は不要です。
+type TargetResolver func(name string) (Notifier, error)
+
+// RouteMatcher はルートの適用条件です。設定された条件はすべて満たす必要があります (AND)。
+// 何も設定されていない場合は、すべてのメッセージにマッチします。
+type RouteMatcher struct {
+	// Severities: いずれかの重要度に一致すること
+	Severities []Severity
+	// MinSeverity: この重要度以上であること
+	MinSeverity Severity
+	// Tags: すべてのタグを含むこと
+	Tags []string
+	// Title: タイトルが正規表現にマッチすること
+	Title *regexp.Regexp
+	// Labels: すべてのラベルの値が一致すること
+	Labels map[string]string
+}
+
+// Matches は、メッセージが条件を満たすかを判定します。
+func (m RouteMatcher) Matches(msg Message) bool {
+	severity := severityOrDefault(msg.Severity)
+	if len(m.Severities) > 0 && !slices.Contains(m.Severities, severity) {
+		return false
+	}
+	if m.MinSeverity != "" && severity.Rank() < m.MinSeverity.Rank() {
+		return false
+	}
+	for _, tag := range m.Tags {
+		if !slices.Contains(msg.Tags, tag) {
+			return false
+		}
+	}
+	if m.Title != nil && !m.Title.MatchString(msg.Title) {
+		return false
+	}
+	for k, v := range m.Labels {
+		if msg.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Route は、条件にマッチしたメッセージの送信先を定義するルールです。
+type Route struct {
+	Name    string
+	Match   RouteMatcher
+	Targets []string
+	// Continue: true の場合、マッチした後も後続のルートを評価します (Alertmanager の continue と同じ)
+	Continue bool
+}
+
+// RouteDecision はルーティングの評価結果です。
+type RouteDecision struct {
+	// Routes: マッチしたルート名 (評価順)
+	Routes []string
+	// Targets: 送信先の通知先名 (重複なし、評価順)
+	Targets []string
+	// Default: どのルートにもマッチせず、DefaultTargets を使用した場合は true
+	Default bool
+}
+
+// Router は、順序付きのルールに従ってメッセージの送信先を決定し、配信する Notifier です。
+type Router struct {
+	routes  []Route
+	resolve TargetResolver
+	// DefaultTargets: どのルートにもマッチしない場合の送信先
+	DefaultTargets []string
+	// Policy / RequiredSuccesses / MaxConcurrency: 配信に使用する MultiNotifier の設定
+	Policy            MultiPolicy
+	RequiredSuccesses int
+	MaxConcurrency    int
+}
+
+// NewRouter は Router の新しいインスタンスを作成します。
+// resolve は、ルーティングの評価のみを行う (Route を呼び出す) 場合は nil でも構いません。
+func NewRouter(resolve TargetResolver, routes ...Route) *Router {
+	return &Router{
+		routes:  routes,
+		resolve: resolve,
+	}
+}
+
+// Route は、ルールを先頭から順に評価し、メッセージの送信先を決定します。
+// マッチしたルートの Continue が false の場合、そこで評価を終了します。
+func (r *Router) Route(msg Message) RouteDecision {
+	var decision RouteDecision
+	for _, route := range r.routes {
+		if !route.Match.Matches(msg) {
+			continue
+		}
+		decision.Routes = append(decision.Routes, route.Name)
+		decision.Targets = appendUnique(decision.Targets, route.Targets...)
+		if !route.Continue {
+			break
+		}
+	}
+
+	if len(decision.Routes) == 0 {
+		decision.Targets = appendUnique(nil, r.DefaultTargets...)
+		decision.Default = true
+	}
+	return decision
+}
+
+// Send は、ルーティングの評価結果に従ってメッセージを配信します。
+// SendResult には、MultiNotifier.Send と同様に通知先ごとの結果と切り詰めた項目が設定されます。
+func (r *Router) Send(ctx context.Context, msg Message) (*SendResult, error) {
+	result, err := r.SendAll(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	return result.sendResult("router"), nil
+}
+
+// SendAll は、ルーティングの評価結果に従ってメッセージを配信し、通知先ごとの結果を返します。
+func (r *Router) SendAll(ctx context.Context, msg Message) (*MultiResult, error) {
+	if r.resolve == nil {
+		return nil, errors.New("Router: 通知先の解決関数 (TargetResolver) が設定されていません")
+	}
+
+	decision := r.Route(msg)
+	if len(decision.Targets) == 0 {
+		return nil, errors.New("Router: メッセージにマッチするルートがなく、DefaultTargets も設定されていません")
+	}
+
+	targets := make([]NamedNotifier, 0, len(decision.Targets))
+	for _, name := range decision.Targets {
+		n, err := r.resolve(name)
+		if err != nil {
+			return nil, fmt.Errorf("Router: 通知先 %s の解決に失敗しました: %w", name, err)
+		}
+		targets = append(targets, NamedNotifier{Name: name, Notifier: n})
+	}
+
+	multi, err := NewMultiNotifier(r.Policy, targets...)
+	if err != nil {
+		return nil, err
+	}
+	multi.RequiredSuccesses = r.RequiredSuccesses
+	multi.MaxConcurrency = r.MaxConcurrency
+	return multi.SendAll(ctx, msg)
+}
+
+// appendUnique は、重複を除いて要素を追加します。
+func appendUnique(dst []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(dst, v) {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,167 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestRouteMatcherMatches(t *testing.T) {
+	msg := Message{
+		Title:    "[prod] nightly batch failed",
+		Severity: SeverityError,
+		Tags:     []string{"batch", "nightly"},
+		Labels:   map[string]string{"env": "prod", "team": "infra"},
+	}
+
+	tests := []struct {
+		name  string
+		match RouteMatcher
+		msg   Message
+		want  bool
+	}{
+		{"条件なしはすべてにマッチ", RouteMatcher{}, msg, true},
+		{"重要度の一覧に含まれる", RouteMatcher{Severities: []Severity{SeverityWarning, SeverityError}}, msg, true},
+		{"重要度の一覧に含まれない", RouteMatcher{Severities: []Severity{SeverityCritical}}, msg, false},
+		{"未設定の重要度は info とみなす", RouteMatcher{Severities: []Severity{SeverityInfo}}, Message{}, true},
+		{"MinSeverity と同じ", RouteMatcher{MinSeverity: SeverityError}, msg, true},
+		{"MinSeverity より高い", RouteMatcher{MinSeverity: SeverityWarning}, msg, true},
+		{"MinSeverity より低い", RouteMatcher{MinSeverity: SeverityCritical}, msg, false},
+		{"success は info と同じ順位", RouteMatcher{MinSeverity: SeverityWarning}, Message{Severity: SeveritySuccess}, false},
+		{"すべてのタグを含む", RouteMatcher{Tags: []string{"nightly", "batch"}}, msg, true},
+		{"タグが1つ足りない", RouteMatcher{Tags: []string{"batch", "db"}}, msg, false},
+		{"タイトルが正規表現にマッチ", RouteMatcher{Title: regexp.MustCompile(`^\[prod\]`)}, msg, true},
+		{"タイトルが正規表現にマッチしない", RouteMatcher{Title: regexp.MustCompile(`^\[stg\]`)}, msg, false},
+		{"すべてのラベルが一致", RouteMatcher{Labels: map[string]string{"env": "prod", "team": "infra"}}, msg, true},
+		{"ラベルの値が異なる", RouteMatcher{Labels: map[string]string{"env": "stg"}}, msg, false},
+		{"ラベルがない", RouteMatcher{Labels: map[string]string{"region": "tokyo"}}, msg, false},
+		{
+			name: "すべての条件を満たす (AND)",
+			match: RouteMatcher{
+				MinSeverity: SeverityWarning,
+				Tags:        []string{"batch"},
+				Title:       regexp.MustCompile(`failed`),
+				Labels:      map[string]string{"env": "prod"},
+			},
+			msg:  msg,
+			want: true,
+		},
+		{
+			name: "1つでも満たさない条件があればマッチしない",
+			match: RouteMatcher{
+				MinSeverity: SeverityWarning,
+				Tags:        []string{"batch"},
+				Title:       regexp.MustCompile(`failed`),
+				Labels:      map[string]string{"env": "stg"},
+			},
+			msg:  msg,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.match.Matches(tt.msg); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouterRoute(t *testing.T) {
+	routes := []Route{
+		{Name: "critical", Match: RouteMatcher{MinSeverity: SeverityCritical}, Targets: []string{"pagerduty", "slack"}, Continue: true},
+		{Name: "prod", Match: RouteMatcher{Labels: map[string]string{"env": "prod"}}, Targets: []string{"slack", "backlog"}},
+		{Name: "errors", Match: RouteMatcher{MinSeverity: SeverityError}, Targets: []string{"email"}},
+	}
+
+	tests := []struct {
+		name string
+		msg  Message
+		want RouteDecision
+	}{
+		{
+			name: "Continue のルートは後続も評価し、通知先の重複を除く",
+			msg:  Message{Severity: SeverityCritical, Labels: map[string]string{"env": "prod"}},
+			want: RouteDecision{Routes: []string{"critical", "prod"}, Targets: []string{"pagerduty", "slack", "backlog"}},
+		},
+		{
+			name: "Continue でないルートにマッチしたら評価を終了する",
+			msg:  Message{Severity: SeverityError, Labels: map[string]string{"env": "prod"}},
+			want: RouteDecision{Routes: []string{"prod"}, Targets: []string{"slack", "backlog"}},
+		},
+		{
+			name: "Continue のルートのみにマッチ",
+			msg:  Message{Severity: SeverityCritical},
+			want: RouteDecision{Routes: []string{"critical", "errors"}, Targets: []string{"pagerduty", "slack", "email"}},
+		},
+		{
+			name: "どのルートにもマッチしない場合は DefaultTargets",
+			msg:  Message{Severity: SeverityWarning, Labels: map[string]string{"env": "stg"}},
+			want: RouteDecision{Targets: []string{"chatwork"}, Default: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRouter(nil, routes...)
+			r.DefaultTargets = []string{"chatwork", "chatwork"}
+
+			got := r.Route(tt.msg)
+			if !slices.Equal(got.Routes, tt.want.Routes) || !slices.Equal(got.Targets, tt.want.Targets) || got.Default != tt.want.Default {
+				t.Errorf("Route() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouterSendAll(t *testing.T) {
+	var mu sync.Mutex
+	var resolved, sent []string
+	resolve := func(name string) (Notifier, error) {
+		mu.Lock()
+		resolved = append(resolved, name)
+		mu.Unlock()
+		if name == "broken" {
+			return nil, errors.New("認証情報がありません")
+		}
+		return notifierFunc(func(context.Context, Message) (*SendResult, error) {
+			mu.Lock()
+			sent = append(sent, name)
+			mu.Unlock()
+			return &SendResult{Backend: name}, nil
+		}), nil
+	}
+
+	r := NewRouter(resolve,
+		Route{Name: "critical", Match: RouteMatcher{MinSeverity: SeverityCritical}, Targets: []string{"slack", "email"}},
+		Route{Name: "broken", Match: RouteMatcher{Tags: []string{"broken"}}, Targets: []string{"broken"}},
+	)
+	r.DefaultTargets = []string{"discord"}
+
+	result, err := r.SendAll(context.Background(), Message{Severity: SeverityCritical})
+	if err != nil {
+		t.Fatalf("SendAll: %v", err)
+	}
+	// マッチした通知先だけを解決する
+	if !slices.Equal(resolved, []string{"slack", "email"}) {
+		t.Errorf("resolved = %v, want [slack email]", resolved)
+	}
+	slices.Sort(sent)
+	if !slices.Equal(sent, []string{"email", "slack"}) {
+		t.Errorf("sent = %v, want [email slack]", sent)
+	}
+	if len(result.Results) != 2 || result.Results[0].Name != "slack" || result.Results[1].Name != "email" {
+		t.Errorf("results = %+v", result.Results)
+	}
+
+	if _, err := r.SendAll(context.Background(), Message{Tags: []string{"broken"}}); err == nil {
+		t.Error("SendAll succeeded with a target that failed to resolve")
+	}
+
+	empty := NewRouter(resolve, Route{Name: "critical", Match: RouteMatcher{MinSeverity: SeverityCritical}, Targets: []string{"slack"}})
+	if _, err := empty.SendAll(context.Background(), Message{}); err == nil {
+		t.Error("SendAll succeeded without a matching route or DefaultTargets")
+	}
+}
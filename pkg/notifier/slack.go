@@ -1,10 +1,12 @@
 package notifier
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"log"
-	"regexp"
+	"io"
+	"net/http"
 	"strings"
 	"time"
 
@@ -12,16 +14,30 @@ import (
 	"github.com/slack-go/slack"
 )
 
-// SlackNotifier は Slack Webhook API と連携するためのクライアントです。
-// Notifier インターフェースを満たします。
+// SlackNotifier は Slack Webhook API および Web API (ボットトークン) と連携するためのクライアントです。
+// Notifier インターフェースを実装します。
+// NewSlackNotifier で生成した場合は Incoming Webhook、NewSlackBotNotifier で生成した場合は chat.postMessage で投稿します。
 type SlackNotifier struct {
-	// WebhookURL: 必須の通知先URL
+	// WebhookURL: Webhook モードの通知先URL
 	WebhookURL string
-	// httpClient: 汎用クライアント (リトライロジックを含む)
+	// client: 汎用クライアント (リトライロジックを含む)。ボットトークンモードの Web API の通信にも使用します
 	client    httpkit.Client
 	Username  string
 	IconEmoji string
 	Channel   string
+	// ThreadTS: ボットトークンモードで、このタイムスタンプのスレッドに返信として投稿します
+	ThreadTS string
+	// MaxMessages: 長文を分割して送信する際の最大メッセージ数 (0 の場合は 10)。超えた部分は省略されます
+	MaxMessages int
+
+	// api: ボットトークンモードの Web API クライアント (Webhook モードでは nil)
+	api *slack.Client
+}
+
+// SlackMessageRef は、ボットトークンモードで投稿したメッセージを指し示す参照です。
+type SlackMessageRef struct {
+	Channel string
+	TS      string
 }
 
 // NewSlackNotifier は SlackNotifier の新しいインスタンスを作成します。
@@ -35,76 +51,501 @@ func NewSlackNotifier(client httpkit.Client, webhookURL, username, iconEmoji, ch
 	}
 }
 
+// NewSlackBotNotifier は、ボットトークン (xoxb-) を使用して Web API で投稿する SlackNotifier を作成します。
+// channel は投稿先のチャンネル (ID または #name) で、必須です。
+// options には、テスト用の API URL (slack.OptionAPIURL) などを指定できます。
+func NewSlackBotNotifier(client httpkit.Client, botToken, channel string, options ...slack.Option) (*SlackNotifier, error) {
+	if botToken == "" || channel == "" {
+		return nil, errors.New("Slack ボットトークンモードには SLACK_BOT_TOKEN と投稿先チャンネルの設定が必要です")
+	}
+
+	if client == (httpkit.Client{}) {
+		return nil, errors.New("Slack ボットトークンモードには httpkit.New で初期化したクライアントが必要です")
+	}
+
+	s := &SlackNotifier{
+		client:  client,
+		Channel: channel,
+	}
+	// HTTP 通信は共有クライアントのリトライ処理 (DoRequest) を経由させる
+	opts := append([]slack.Option{slack.OptionHTTPClient(slackAPIDoer{client: &s.client})}, options...)
+	s.api = slack.New(botToken, opts...)
+
+	return s, nil
+}
+
+// slackAPIDoer は、Web API クライアント (slack-go) の HTTP 通信を httpkit.Client の DoRequest に中継するアダプターです。
+// 5xx とネットワークエラーは RetryConfig に従って再試行し、4xx (レート制限の 429 を含む) は
+// ステータスコードとヘッダーを保ったレスポンスとして slack-go に返します。
+type slackAPIDoer struct {
+	client *httpkit.Client
+}
+
+func (d slackAPIDoer) Do(req *http.Request) (*http.Response, error) {
+	// 再試行のたびに同じボディを送信できるよう、先に読み込んでおく
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Slack API のリクエストボディの読み込みに失敗しました: %w", err)
+		}
+	}
+
+	attempt := &slackAPIAttempt{doer: d.client, body: body}
+	retrying := httpkit.New(0, httpkit.WithHTTPClient(attempt))
+	retrying.RetryConfig = d.client.RetryConfig
+
+	respBody, err := retrying.DoRequest(req)
+	if err != nil {
+		var httpErr *httpkit.NonRetryableHTTPError
+		if !errors.As(err, &httpErr) {
+			return nil, err
+		}
+		respBody = httpErr.Body
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", attempt.status, http.StatusText(attempt.status)),
+		StatusCode:    attempt.status,
+		Header:        attempt.header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// slackAPIAttempt は、DoRequest の試行ごとにリクエストボディを巻き戻し、直近のレスポンスのステータスコードとヘッダーを記録する Doer です。
+type slackAPIAttempt struct {
+	doer   httpkit.Doer
+	body   []byte
+	status int
+	header http.Header
+}
+
+func (a *slackAPIAttempt) Do(req *http.Request) (*http.Response, error) {
+	if a.body != nil {
+		req.Body = io.NopCloser(bytes.NewReader(a.body))
+	}
+	resp, err := a.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	a.status, a.header = resp.StatusCode, resp.Header
+	return resp, nil
+}
+
+// IsBotMode は、ボットトークン (Web API) モードで動作しているかを返します。
+func (s *SlackNotifier) IsBotMode() bool {
+	return s.api != nil
+}
+
 // --- Notifier インターフェース実装 ---
 
+// Send は、Message を Block Kit 形式に変換して投稿します。
+// Fields はセクションのフィールド、Links はボタン、Severity と Tags はコンテキストとして描画されます。
+// ファイルのアップロードには対応していないため、Attachments は無視されます。
+// ボットトークンモードでは、SendResult の Channel と ID に投稿先チャンネルとメッセージの ts が設定されます。
+// Slack の上限を超えたために切り詰めた項目は、SendResult の Truncations に記録されます。
+func (s *SlackNotifier) Send(ctx context.Context, msg Message) (*SendResult, error) {
+	var rec truncationRecorder
+	ref, err := s.postMessage(ctx, msg, s.ThreadTS, &rec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SendResult{Backend: "slack", ID: ref.TS, Channel: ref.Channel, Truncations: rec.truncations}, nil
+}
+
+// PostMessage は、Message を Block Kit 形式に変換して投稿し、投稿したメッセージの参照を返します。
+// Webhook モードでは ts を取得できないため、空の参照を返します。
+func (s *SlackNotifier) PostMessage(ctx context.Context, msg Message) (*SlackMessageRef, error) {
+	return s.postMessage(ctx, msg, s.ThreadTS, nil)
+}
+
+// Reply は、parent のスレッドに Message を返信として投稿します。ボットトークンモードでのみ使用できます。
+func (s *SlackNotifier) Reply(ctx context.Context, parent SlackMessageRef, msg Message) (*SlackMessageRef, error) {
+	if !s.IsBotMode() {
+		return nil, errors.New("SlackNotifier: スレッドへの返信にはボットトークンモードが必要です")
+	}
+	if parent.TS == "" {
+		return nil, errors.New("SlackNotifier: 返信先のメッセージの ts が空です")
+	}
+
+	bot := *s
+	if parent.Channel != "" {
+		bot.Channel = parent.Channel
+	}
+	return bot.postMessage(ctx, msg, parent.TS, nil)
+}
+
+// UpdateMessage は、投稿済みのメッセージ (ref) を Message の内容で置き換えます (chat.update)。
+// ボットトークンモードでのみ使用できます。ref.Channel が空の場合は Channel を使用します。
+func (s *SlackNotifier) UpdateMessage(ctx context.Context, ref SlackMessageRef, msg Message) (*SlackMessageRef, error) {
+	channel, err := s.refChannel(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// chat.update は1つのメッセージのみを置き換えるため、収まらない部分は省略する
+	header, pages := s.renderSlackMessage(msg, 1, nil)
+	blocks := pages[0]
+	updatedChannel, ts, _, err := s.api.UpdateMessageContext(ctx, channel, ref.TS,
+		slack.MsgOptionText(header, false),
+		slack.MsgOptionBlocks(blocks...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("Slack chat.update の呼び出しに失敗しました (ts: %s): %w", ref.TS, err)
+	}
+
+	return &SlackMessageRef{Channel: updatedChannel, TS: ts}, nil
+}
+
+// DeleteMessage は、投稿済みのメッセージ (ref) を削除します (chat.delete)。
+// ボットトークンモードでのみ使用できます。ref.Channel が空の場合は Channel を使用します。
+func (s *SlackNotifier) DeleteMessage(ctx context.Context, ref SlackMessageRef) error {
+	channel, err := s.refChannel(ref)
+	if err != nil {
+		return err
+	}
+
+	if _, _, err := s.api.DeleteMessageContext(ctx, channel, ref.TS); err != nil {
+		return fmt.Errorf("Slack chat.delete の呼び出しに失敗しました (ts: %s): %w", ref.TS, err)
+	}
+	return nil
+}
+
+// refChannel は、投稿済みメッセージの操作に必要な前提条件を確認し、対象のチャンネルを返します。
+func (s *SlackNotifier) refChannel(ref SlackMessageRef) (string, error) {
+	if !s.IsBotMode() {
+		return "", errors.New("SlackNotifier: 投稿済みメッセージの更新・削除にはボットトークンモードが必要です")
+	}
+	if ref.TS == "" {
+		return "", errors.New("SlackNotifier: 対象のメッセージの ts が空です")
+	}
+
+	channel := ref.Channel
+	if channel == "" {
+		channel = s.Channel
+	}
+	if channel == "" {
+		return "", errors.New("SlackNotifier: 対象のメッセージのチャンネルが指定されていません")
+	}
+	return channel, nil
+}
+
+// postMessage は、Message をブロックに変換し、threadTS のスレッド (空の場合はチャンネル) に投稿します。
+// 切り詰めた項目は rec に記録されます (rec は nil でも構いません)。
+func (s *SlackNotifier) postMessage(ctx context.Context, msg Message, threadTS string, rec *truncationRecorder) (*SlackMessageRef, error) {
+	header, pages := s.renderSlackMessage(msg, s.maxMessages(), rec)
+	return s.postPages(ctx, header, pages, threadTS)
+}
+
+// renderSlackMessage は、Message をヘッダーテキストと、メッセージごとの Block Kit のブロック列に変換します。
+// Title が空の場合は、本文の1行目からヘッダーを生成します。
+func (s *SlackNotifier) renderSlackMessage(msg Message, maxMessages int, rec *truncationRecorder) (string, [][]slack.Block) {
+	header := msg.Title
+	if header == "" {
+		header = defaultHeader(msg.Body)
+	}
+	return header, buildSlackPages(header, msg.Body, slackMessageExtras(msg, rec), !s.IsBotMode(), maxMessages, rec)
+}
+
+// maxMessages は、長文を分割して送信する際の最大メッセージ数を返します。
+func (s *SlackNotifier) maxMessages() int {
+	if s.MaxMessages > 0 {
+		return s.MaxMessages
+	}
+	return defaultSlackMaxMessages
+}
+
 // SendTextWithHeader は、ヘッダー付きのテキストメッセージを解析し、SlackのBlock Kit形式で投稿します。
 // headerText は、Slackメッセージのヘッダーとして表示されるテキストです。
 // message は、抽出された本文全体（Markdownとして解釈可能）を想定します。
 func (s *SlackNotifier) SendTextWithHeader(ctx context.Context, headerText string, message string) error {
-	// --- 1. Block Kitの構築ロジック（流用元のロジックを汎用化） ---
-
-	// 外部から指定されたheaderTextを使用してヘッダーブロックを作成
-	blocks := []slack.Block{
-		slack.NewHeaderBlock(
-			slack.NewTextBlockObject("plain_text", headerText, true, false),
-		),
-		slack.NewDividerBlock(),
-	}
-
-	// 流用元と同様の整形と文字数制限の定数
-	const maxSectionLength = 2900
-	const maxBlocks = 50
-	const truncationSuffix = "\n\n... (メッセージが長すぎるため省略されました)"
-
-	// Markdown整形用の正規表現（流用元からそのまま採用）
-	boldRegex := regexp.MustCompile(`\*\*(.*?)\*\*`)     // **text** -> *text*
-	headerRegex := regexp.MustCompile(`(?m)^##\s*(.*)$`) // ## Title -> *Title*
-	listItemRegex := regexp.MustCompile(`(?m)^\s*-\s+`)  // - item -> • item
-
-	// 抽出テキストをセクションで分割 (Web抽出後のテキストは通常、全体を一つのセクションとして扱います)
-	reviewSections := []string{message}
-
-	for _, sectionText := range reviewSections {
-		if len(blocks) >= maxBlocks-2 {
-			log.Println("WARNING: Notification message is too long, truncating message.")
-			blocks = append(blocks, slack.NewSectionBlock(
-				slack.NewTextBlockObject("mrkdwn", truncationSuffix, false, false), nil, nil))
-			break
+	pages := buildSlackPages(headerText, message, nil, !s.IsBotMode(), s.maxMessages(), nil)
+	_, err := s.postPages(ctx, headerText, pages, s.ThreadTS)
+	return err
+}
+
+// Slack の Block Kit の制限と、長文を分割して送信する際の既定値
+const (
+	// slackMaxBlocks: 1メッセージあたりの最大ブロック数
+	slackMaxBlocks = 50
+	// slackMaxExtraBlocks: extras の最大ブロック数 (ヘッダー・Divider・本文1ブロック・フッターの分を除いたもの)
+	slackMaxExtraBlocks = slackMaxBlocks - 4
+	// defaultSlackMaxMessages: 分割して送信する最大メッセージ数の既定値
+	defaultSlackMaxMessages = 10
+	truncationSuffix        = "... (メッセージが長すぎるため省略されました)"
+)
+
+// buildSlackPages は、ヘッダーと本文から Block Kit のブロック列を構築し、送信するメッセージごとに分割します。
+// 本文は段落・見出し・コードブロックの境界で section ブロックに分割され、1メッセージのブロック数の上限を超える場合は
+// 複数のメッセージ (ページ) に分割されます。numbered が true の場合は各ページに "(1/3)" のような番号付きのヘッダーを、
+// false の場合は2ページ目以降に続きであることを示すコンテキストを付与します。
+// extras とフッター (送信時刻) は最後のページに挿入されます。maxMessages を超えるページは省略されます。
+// extras は slackMaxExtraBlocks までとし、超える分は省略します。
+// ヘッダーの切り詰めやページ・extras の省略は rec に記録されます (rec は nil でも構いません)。
+func buildSlackPages(headerText, message string, extras []slack.Block, numbered bool, maxMessages int, rec *truncationRecorder) [][]slack.Block {
+	body := slackBodyBlocks(message)
+
+	// extras で最後のページが埋まらないよう、本文を少なくとも1ブロック入れられる数までとする
+	if len(extras) > slackMaxExtraBlocks {
+		rec.record("extras", len(extras), slackMaxExtraBlocks)
+		extras = extras[:slackMaxExtraBlocks]
+	}
+
+	// 各ページには、ヘッダー・Divider (または続きのコンテキスト)・extras・フッターの分の空きを確保する
+	capacity := slackMaxBlocks - 3 - len(extras)
+	var chunks [][]slack.Block
+	for len(body) > 0 {
+		n := min(capacity, len(body))
+		chunks = append(chunks, body[:n])
+		body = body[n:]
+	}
+	if len(chunks) == 0 {
+		chunks = [][]slack.Block{nil}
+	}
+
+	if maxMessages > 0 && len(chunks) > maxMessages {
+		rec.record("message", len(chunks), maxMessages)
+		chunks = chunks[:maxMessages]
+		last := chunks[len(chunks)-1]
+		last[len(last)-1] = slack.NewSectionBlock(
+			slack.NewTextBlockObject("mrkdwn", truncationSuffix, false, false), nil, nil)
+	}
+
+	// ヘッダーは、ページ番号 " (1/3)" を付与しても上限に収まるように切り詰める
+	headerLimit := SlackHeaderLimit
+	if len(chunks) > 1 {
+		headerLimit -= TextLength(fmt.Sprintf(" (%d/%d)", len(chunks), len(chunks)))
+	}
+	headerText = rec.truncate("header", headerText, headerLimit)
+
+	pages := make([][]slack.Block, 0, len(chunks))
+	for i, chunk := range chunks {
+		var page []slack.Block
+		switch {
+		case i == 0 || numbered:
+			pageHeader := headerText
+			if len(chunks) > 1 {
+				pageHeader = fmt.Sprintf("%s (%d/%d)", headerText, i+1, len(chunks))
+			}
+			page = append(page, slack.NewHeaderBlock(
+				slack.NewTextBlockObject("plain_text", pageHeader, true, false),
+			))
+			if len(chunk) > 0 {
+				page = append(page, slack.NewDividerBlock())
+			}
+		default:
+			page = append(page, slack.NewContextBlock(
+				fmt.Sprintf("notification-part-%d", i+1),
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("続き (%d/%d)", i+1, len(chunks)), false, false),
+			))
 		}
-		if strings.TrimSpace(sectionText) == "" {
-			continue
+		page = append(page, chunk...)
+
+		if i == len(chunks)-1 {
+			page = append(page, extras...)
+			// フッターには送信時刻を含める
+			page = append(page, slack.NewContextBlock(
+				"notification-context",
+				slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("送信時刻: %s",
+					time.Now().Format("2006-01-02 15:04:05")), false, false),
+			))
 		}
+		pages = append(pages, page)
+	}
+	return pages
+}
 
-		// Markdown整形処理
-		processedText := sectionText
-		processedText = boldRegex.ReplaceAllString(processedText, "*$1*")
-		processedText = headerRegex.ReplaceAllString(processedText, "*$1*")
-		processedText = listItemRegex.ReplaceAllString(processedText, "• ")
+// slackBodyBlocks は、Markdown の本文を Block Kit のブロック列に変換します。
+// 連続するテキストは section ブロックの文字数の上限まで1つのブロックにまとめ、
+// 上限を超えるテキストやコードブロックは行単位で分割します (コードブロックは分割後もそれぞれ完結したブロックになります)。
+func slackBodyBlocks(message string) []slack.Block {
+	var blocks []slack.Block
 
-		// 文字数制限の適用
-		if len(processedText) > maxSectionLength {
-			log.Printf("WARNING: The notification message is too long (%d chars), truncating.", len(processedText))
-			processedText = processedText[:maxSectionLength-len(truncationSuffix)] + truncationSuffix
+	var section []string
+	sectionLength := 0
+	flushSection := func() {
+		if len(section) == 0 {
+			return
 		}
-
 		blocks = append(blocks, slack.NewSectionBlock(
-			slack.NewTextBlockObject("mrkdwn", processedText, false, false), nil, nil),
-			slack.NewDividerBlock(),
-		)
+			slack.NewTextBlockObject("mrkdwn", strings.Join(section, "\n\n"), false, false), nil, nil))
+		section = nil
+		sectionLength = 0
 	}
 
-	if len(blocks) > 0 {
-		blocks = blocks[:len(blocks)-1] // 最後の余分なDividerを削除
+	for _, seg := range markdownToSegments(message) {
+		switch seg.kind {
+		case segmentDivider:
+			flushSection()
+			blocks = append(blocks, slack.NewDividerBlock())
+		case segmentCode:
+			// コードブロックは rich_text の preformatted として描画 (mrkdwn のエスケープは不要)
+			flushSection()
+			for _, chunk := range splitLines(seg.text, SlackSectionLimit, false) {
+				preformatted := &slack.RichTextPreformatted{
+					RichTextSection: *slack.NewRichTextSection(slack.NewRichTextSectionTextElement(chunk, nil)),
+				}
+				preformatted.Type = slack.RTEPreformatted
+				blocks = append(blocks, slack.NewRichTextBlock("", preformatted))
+			}
+		default:
+			for _, chunk := range splitLines(seg.text, SlackSectionLimit, true) {
+				n := TextLength(chunk)
+				if len(section) > 0 && sectionLength+len("\n\n")+n > SlackSectionLimit {
+					flushSection()
+				}
+				if len(section) > 0 {
+					sectionLength += len("\n\n")
+				}
+				section = append(section, chunk)
+				sectionLength += n
+			}
+		}
 	}
+	flushSection()
 
-	// フッターには送信時刻を含める
-	footerBlock := slack.NewContextBlock(
-		"notification-context",
-		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("送信時刻: %s",
-			time.Now().Format("2006-01-02 15:04:05")), false, false),
-	)
-	blocks = append(blocks, footerBlock)
+	return blocks
+}
+
+// slackMessageExtras は、Message の Fields / Links / Severity / Tags を Block Kit のブロックに変換します。
+// Fields はリンクとコンテキストのブロックと合わせて slackMaxExtraBlocks に収まる数まで、Links はアクションブロックの上限までとし、
+// 省略した場合は rec に記録します。
+func slackMessageExtras(msg Message, rec *truncationRecorder) []slack.Block {
+	// セクションブロックのフィールド数の上限
+	const maxSectionFields = 10
+	// アクションブロックの要素数の上限
+	const maxActionElements = 25
+	// Fields のセクションブロックの上限 (リンクとコンテキストのブロックの分を除く)
+	const maxFields = (slackMaxExtraBlocks - 2) * maxSectionFields
+
+	var extras []slack.Block
+
+	msgFields := msg.Fields
+	if len(msgFields) > maxFields {
+		rec.record("field", len(msgFields), maxFields)
+		msgFields = msgFields[:maxFields]
+	}
+	for i := 0; i < len(msgFields); i += maxSectionFields {
+		end := min(i+maxSectionFields, len(msgFields))
+		fields := make([]*slack.TextBlockObject, 0, end-i)
+		for _, f := range msgFields[i:end] {
+			text := rec.truncate("field", fmt.Sprintf("*%s*\n%s", f.Name, f.Value), SlackFieldLimit)
+			fields = append(fields, slack.NewTextBlockObject("mrkdwn", text, false, false))
+		}
+		extras = append(extras, slack.NewSectionBlock(nil, fields, nil))
+	}
+
+	if len(msg.Links) > 0 {
+		links := msg.Links
+		if len(links) > maxActionElements {
+			rec.record("button", len(links), maxActionElements)
+			links = links[:maxActionElements]
+		}
+		var elements []slack.BlockElement
+		for i, l := range links {
+			text := l.Text
+			if text == "" {
+				text = l.URL
+			}
+			text = rec.truncate("button", text, SlackButtonTextLimit)
+			button := slack.NewButtonBlockElement(fmt.Sprintf("link-%d", i), l.URL,
+				slack.NewTextBlockObject("plain_text", text, true, false))
+			elements = append(elements, button.WithURL(l.URL))
+		}
+		extras = append(extras, slack.NewActionBlock("notification-links", elements...))
+	}
+
+	meta := []string{fmt.Sprintf("%s %s", msg.Severity.Emoji(), severityOrDefault(msg.Severity))}
+	if len(msg.Tags) > 0 {
+		tags := make([]string, 0, len(msg.Tags))
+		for _, t := range msg.Tags {
+			tags = append(tags, "`"+t+"`")
+		}
+		meta = append(meta, strings.Join(tags, " "))
+	}
+	for _, k := range sortedLabelKeys(msg.Labels) {
+		meta = append(meta, fmt.Sprintf("%s: %s", k, msg.Labels[k]))
+	}
+	extras = append(extras, slack.NewContextBlock(
+		"notification-meta",
+		slack.NewTextBlockObject("mrkdwn", strings.Join(meta, " | "), false, false),
+	))
+
+	return extras
+}
+
+// severityOrDefault は、未設定の重要度を SeverityInfo として扱います。
+func severityOrDefault(s Severity) Severity {
+	if s == "" {
+		return SeverityInfo
+	}
+	return s
+}
+
+// postPages は、分割されたメッセージを順に送信し、最初のメッセージの参照を返します。
+// 2通目以降は、ボットトークンモードでは最初のメッセージ (threadTS が指定されている場合はそのスレッド) への返信として、
+// Webhook モードでは番号付きの別メッセージとして送信されます。
+func (s *SlackNotifier) postPages(ctx context.Context, headerText string, pages [][]slack.Block, threadTS string) (*SlackMessageRef, error) {
+	first, err := s.post(ctx, headerText, pages[0], threadTS)
+	if err != nil {
+		return nil, err
+	}
+
+	replyTS := threadTS
+	if replyTS == "" {
+		replyTS = first.TS
+	}
+	for i, page := range pages[1:] {
+		partHeader := fmt.Sprintf("%s (%d/%d)", headerText, i+2, len(pages))
+		if _, err := s.post(ctx, partHeader, page, replyTS); err != nil {
+			return first, fmt.Errorf("分割したメッセージ (%d/%d) の送信に失敗しました: %w", i+2, len(pages), err)
+		}
+	}
+	return first, nil
+}
+
+// post は、動作モードに応じて構築済みのブロックを Web API または Webhook で送信します。
+func (s *SlackNotifier) post(ctx context.Context, headerText string, blocks []slack.Block, threadTS string) (*SlackMessageRef, error) {
+	if s.IsBotMode() {
+		return s.postAPI(ctx, headerText, blocks, threadTS)
+	}
+	if err := s.postWebhook(ctx, headerText, blocks); err != nil {
+		return nil, err
+	}
+	return &SlackMessageRef{}, nil
+}
+
+// postAPI は、構築済みのブロックを chat.postMessage で送信し、投稿先チャンネルと ts を返します。
+func (s *SlackNotifier) postAPI(ctx context.Context, headerText string, blocks []slack.Block, threadTS string) (*SlackMessageRef, error) {
+	opts := []slack.MsgOption{
+		// プレーンテキストの代替 (通知のプレビュー等) としてヘッダーを使用
+		slack.MsgOptionText(headerText, false),
+		slack.MsgOptionBlocks(blocks...),
+	}
+	if s.Username != "" {
+		opts = append(opts, slack.MsgOptionUsername(s.Username))
+	}
+	if s.IconEmoji != "" {
+		opts = append(opts, slack.MsgOptionIconEmoji(s.IconEmoji))
+	}
+	if threadTS != "" {
+		opts = append(opts, slack.MsgOptionTS(threadTS))
+	}
 
+	channel, ts, err := s.api.PostMessageContext(ctx, s.Channel, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("Slack chat.postMessage の呼び出しに失敗しました: %w", err)
+	}
+
+	return &SlackMessageRef{Channel: channel, TS: ts}, nil
+}
+
+// postWebhook は、構築済みのブロックを Webhook メッセージとして送信します。
+func (s *SlackNotifier) postWebhook(ctx context.Context, headerText string, blocks []slack.Block) error {
 	// --- 2. Webhookメッセージの作成とペイロード準備 ---
 	msg := slack.WebhookMessage{
 		// プレーンテキストの代替としてヘッダーを使用し、必要に応じてユーザー名とアイコンを上書き
@@ -147,23 +588,29 @@ func (s *SlackNotifier) SendTextWithHeader(ctx context.Context, headerText strin
 }
 
 // SendText は、プレーンテキストメッセージを通知します。（ヘッダーなし）
-// Notifier インターフェースを満たすため、SendTextWithHeader にデフォルトヘッダーを付けてフォールバックします。
+// 本文の1行目からデフォルトヘッダーを生成し、SendTextWithHeader にフォールバックします。
 func (s *SlackNotifier) SendText(ctx context.Context, message string) error {
+	return s.SendTextWithHeader(ctx, defaultHeader(message), message)
+}
+
+// defaultHeader は、本文の1行目からヘッダーを生成します。
+func defaultHeader(message string) string {
 	header := "📢 通知メッセージ" // デフォルトヘッダー
 	if len(message) > 0 {
 		firstLine := strings.SplitN(message, "\n", 2)[0]
 		if firstLine != "" { // firstLineが空でなければ、それを使用
-			if len(firstLine) > 50 { // ヘッダーが長くなりすぎないように調整
-				firstLine = firstLine[:50] + "..."
-			}
+			// ヘッダーが長くなりすぎないように、文字の途中で切らずに調整
+			firstLine, _ = TruncateText(firstLine, 50, "...")
 			header = fmt.Sprintf("📢 %s", firstLine)
 		}
 	}
-	return s.SendTextWithHeader(ctx, header, message)
+	return header
 }
 
 // SendIssue は Slack では課題登録機能が標準ではないため、SendTextWithHeaderにフォールバックします。
 // 課題の概要をヘッダーとして使用し、課題の詳細をメッセージ本文として送信します。
+//
+// Deprecated: 通知先に依存しない Send を使用してください。
 func (s *SlackNotifier) SendIssue(ctx context.Context, summary, description string, projectID, issueTypeID, priorityID int) error {
 	// summary をヘッダーとして使用し、description を本文として渡す
 	header := fmt.Sprintf("【課題】%s", summary)
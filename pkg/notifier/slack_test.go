@@ -0,0 +1,337 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+	"github.com/slack-go/slack"
+)
+
+// fakeSlackAPI は、chat.postMessage / chat.update / chat.delete を模擬する Slack Web API です。
+type fakeSlackAPI struct {
+	mu sync.Mutex
+	// calls: 呼び出された API メソッドと受け取ったフォーム
+	calls []fakeSlackCall
+}
+
+// fakeSlackCall は、fakeSlackAPI への1回の呼び出しです。
+type fakeSlackCall struct {
+	method string
+	form   url.Values
+}
+
+func (f *fakeSlackAPI) handler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		method := strings.TrimPrefix(r.URL.Path, "/api/")
+
+		f.mu.Lock()
+		f.calls = append(f.calls, fakeSlackCall{method: method, form: r.PostForm})
+		n := len(f.calls)
+		f.mu.Unlock()
+
+		resp := map[string]any{"ok": true, "channel": "C0123"}
+		switch method {
+		case "chat.postMessage":
+			resp["ts"] = fmt.Sprintf("1700000000.%06d", n)
+		case "chat.update", "chat.delete":
+			resp["ts"] = r.PostForm.Get("ts")
+		default:
+			resp = map[string]any{"ok": false, "error": "unknown_method"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// newTestSlackBotNotifier は、fakeSlackAPI に接続するボットトークンモードの SlackNotifier を生成します。
+func newTestSlackBotNotifier(t *testing.T) (*SlackNotifier, *fakeSlackAPI) {
+	t.Helper()
+	fake := &fakeSlackAPI{}
+	server := httptest.NewServer(fake.handler(t))
+	t.Cleanup(server.Close)
+
+	client := httpkit.New(5*time.Second, httpkit.WithMaxRetries(0))
+	s, err := NewSlackBotNotifier(*client, "xoxb-test", "#alerts", slack.OptionAPIURL(server.URL+"/api/"))
+	if err != nil {
+		t.Fatalf("NewSlackBotNotifier: %v", err)
+	}
+	return s, fake
+}
+
+func TestSlackBotRetriesServerErrors(t *testing.T) {
+	var texts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		texts = append(texts, r.PostForm.Get("text"))
+		if len(texts) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true,"channel":"C0123","ts":"1700000000.000001"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := httpkit.New(5*time.Second, httpkit.WithMaxRetries(2), httpkit.WithInitialInterval(time.Millisecond), httpkit.WithMaxInterval(time.Millisecond))
+	s, err := NewSlackBotNotifier(*client, "xoxb-test", "#alerts", slack.OptionAPIURL(server.URL+"/api/"))
+	if err != nil {
+		t.Fatalf("NewSlackBotNotifier: %v", err)
+	}
+
+	result, err := s.Send(context.Background(), Message{Title: "デプロイ完了", Body: "本文"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.ID != "1700000000.000001" {
+		t.Errorf("ID = %q", result.ID)
+	}
+	// 再試行でも同じボディを送信する
+	if !slices.Equal(texts, []string{"デプロイ完了", "デプロイ完了"}) {
+		t.Errorf("texts = %q, want the same request twice", texts)
+	}
+}
+
+func TestSlackBotRateLimited(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(server.Close)
+
+	client := httpkit.New(5*time.Second, httpkit.WithMaxRetries(2), httpkit.WithInitialInterval(time.Millisecond), httpkit.WithMaxInterval(time.Millisecond))
+	s, err := NewSlackBotNotifier(*client, "xoxb-test", "#alerts", slack.OptionAPIURL(server.URL+"/api/"))
+	if err != nil {
+		t.Fatalf("NewSlackBotNotifier: %v", err)
+	}
+
+	_, err = s.Send(context.Background(), Message{Body: "本文"})
+	var rateLimited *slack.RateLimitedError
+	if !errors.As(err, &rateLimited) || rateLimited.RetryAfter != 30*time.Second {
+		t.Errorf("err = %v, want a *slack.RateLimitedError with Retry-After 30s", err)
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls, want 4xx responses not to be retried", calls)
+	}
+}
+
+func TestNewSlackBotNotifierRejectsZeroClient(t *testing.T) {
+	if _, err := NewSlackBotNotifier(httpkit.Client{}, "xoxb-test", "#alerts"); err == nil {
+		t.Error("NewSlackBotNotifier accepted a zero-value client")
+	}
+}
+
+// blockTypes は、フォームの blocks パラメーターからブロックの種別の一覧を取得します。
+func blockTypes(t *testing.T, form url.Values) []string {
+	t.Helper()
+	var blocks []struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(form.Get("blocks")), &blocks); err != nil {
+		t.Fatalf("blocks = %q: %v", form.Get("blocks"), err)
+	}
+	types := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		types = append(types, b.Type)
+	}
+	return types
+}
+
+func TestSlackBotSend(t *testing.T) {
+	s, fake := newTestSlackBotNotifier(t)
+
+	result, err := s.Send(context.Background(), Message{
+		Title: "デプロイ完了",
+		Body:  "本番環境へのデプロイが完了しました。",
+		Links: []Link{{Text: "詳細", URL: "https://example.com/deploy/1"}},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.Channel != "C0123" || result.ID != "1700000000.000001" {
+		t.Errorf("SendResult channel/ts = %q/%q, want C0123/1700000000.000001", result.Channel, result.ID)
+	}
+
+	if len(fake.calls) != 1 || fake.calls[0].method != "chat.postMessage" {
+		t.Fatalf("calls = %+v, want a single chat.postMessage", fake.calls)
+	}
+	form := fake.calls[0].form
+	if form.Get("channel") != "#alerts" || form.Get("text") != "デプロイ完了" || form.Has("thread_ts") {
+		t.Errorf("form channel/text/thread_ts = %q/%q/%q, want #alerts/デプロイ完了/none", form.Get("channel"), form.Get("text"), form.Get("thread_ts"))
+	}
+	want := []string{"header", "divider", "section", "actions", "context", "context"}
+	if got := blockTypes(t, form); !slices.Equal(got, want) {
+		t.Errorf("block types = %q, want %q", got, want)
+	}
+}
+
+func TestSlackBotSendThreadAndPages(t *testing.T) {
+	s, fake := newTestSlackBotNotifier(t)
+	s.ThreadTS = "1690000000.000100"
+
+	// 上限のブロック数を超える本文は、スレッドへの続きのメッセージに分割される
+	body := strings.Repeat("```\ncode\n```\n\n", slackMaxBlocks)
+	if _, err := s.Send(context.Background(), Message{Title: "長いログ", Body: body}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if len(fake.calls) != 2 {
+		t.Fatalf("got %d calls, want 2", len(fake.calls))
+	}
+	for i, call := range fake.calls {
+		if got := call.form.Get("thread_ts"); got != s.ThreadTS {
+			t.Errorf("call %d thread_ts = %q, want %q", i+1, got, s.ThreadTS)
+		}
+	}
+}
+
+func TestSlackBotReplyWithoutThread(t *testing.T) {
+	s, fake := newTestSlackBotNotifier(t)
+
+	ref, err := s.PostMessage(context.Background(), Message{Title: "親メッセージ"})
+	if err != nil {
+		t.Fatalf("PostMessage: %v", err)
+	}
+	if _, err := s.Reply(context.Background(), *ref, Message{Title: "返信"}); err != nil {
+		t.Fatalf("Reply: %v", err)
+	}
+
+	reply := fake.calls[1].form
+	if reply.Get("thread_ts") != ref.TS || reply.Get("channel") != ref.Channel {
+		t.Errorf("reply thread_ts/channel = %q/%q, want %q/%q", reply.Get("thread_ts"), reply.Get("channel"), ref.TS, ref.Channel)
+	}
+}
+
+func TestSlackBotUpdateAndDeleteMessage(t *testing.T) {
+	s, fake := newTestSlackBotNotifier(t)
+	ref := SlackMessageRef{Channel: "C0123", TS: "1700000000.000001"}
+
+	updated, err := s.UpdateMessage(context.Background(), ref, Message{Title: "デプロイ完了 (更新)"})
+	if err != nil {
+		t.Fatalf("UpdateMessage: %v", err)
+	}
+	if *updated != ref {
+		t.Errorf("UpdateMessage = %+v, want %+v", *updated, ref)
+	}
+	if err := s.DeleteMessage(context.Background(), SlackMessageRef{TS: ref.TS}); err != nil {
+		t.Fatalf("DeleteMessage: %v", err)
+	}
+
+	if len(fake.calls) != 2 {
+		t.Fatalf("got %d calls, want 2", len(fake.calls))
+	}
+	update, del := fake.calls[0], fake.calls[1]
+	if update.method != "chat.update" || update.form.Get("ts") != ref.TS || update.form.Get("text") != "デプロイ完了 (更新)" {
+		t.Errorf("update call = %s %v", update.method, update.form)
+	}
+	if got := blockTypes(t, update.form); got[0] != "header" {
+		t.Errorf("update block types = %q, want a header first", got)
+	}
+	// ref.Channel が空の場合は、Channel に削除を要求する
+	if del.method != "chat.delete" || del.form.Get("ts") != ref.TS || del.form.Get("channel") != "#alerts" {
+		t.Errorf("delete call = %s %v", del.method, del.form)
+	}
+}
+
+func TestSlackWebhookModeRejectsMessageOperations(t *testing.T) {
+	s := NewSlackNotifier(*httpkit.New(time.Second), "https://hooks.slack.com/services/T/B/X", "", "", "")
+	ref := SlackMessageRef{Channel: "C0123", TS: "1700000000.000001"}
+
+	if _, err := s.UpdateMessage(context.Background(), ref, Message{Title: "更新"}); err == nil {
+		t.Error("UpdateMessage succeeded in webhook mode, want an error")
+	}
+	if err := s.DeleteMessage(context.Background(), ref); err == nil {
+		t.Error("DeleteMessage succeeded in webhook mode, want an error")
+	}
+	if _, err := s.Reply(context.Background(), ref, Message{Title: "返信"}); err == nil {
+		t.Error("Reply succeeded in webhook mode, want an error")
+	}
+}
+
+func TestBuildSlackPagesCapsExtras(t *testing.T) {
+	msg := Message{Body: "本文", Severity: SeverityError}
+	for i := range 500 {
+		msg.Fields = append(msg.Fields, Field{Name: fmt.Sprintf("項目%d", i), Value: "値"})
+	}
+	for i := range 30 {
+		msg.Links = append(msg.Links, Link{Text: fmt.Sprintf("リンク%d", i), URL: "https://example.com"})
+	}
+
+	var rec truncationRecorder
+	pages := buildSlackPages("見出し", msg.Body, slackMessageExtras(msg, &rec), true, defaultSlackMaxMessages, &rec)
+
+	for i, page := range pages {
+		if len(page) > slackMaxBlocks {
+			t.Errorf("page %d has %d blocks, want at most %d", i+1, len(page), slackMaxBlocks)
+		}
+	}
+	want := []Truncation{
+		{Field: "field", Length: 500, Limit: 440},
+		{Field: "button", Length: 30, Limit: 25},
+	}
+	if !slices.Equal(rec.truncations, want) {
+		t.Errorf("truncations = %v, want %v", rec.truncations, want)
+	}
+}
+
+func TestBuildSlackPagesTrimsExtras(t *testing.T) {
+	extras := make([]slack.Block, 60)
+	for i := range extras {
+		extras[i] = slack.NewDividerBlock()
+	}
+
+	var rec truncationRecorder
+	pages := buildSlackPages("見出し", strings.Repeat("段落\n\n", 3), extras, true, defaultSlackMaxMessages, &rec)
+
+	last := pages[len(pages)-1]
+	if len(last) > slackMaxBlocks {
+		t.Errorf("last page has %d blocks, want at most %d", len(last), slackMaxBlocks)
+	}
+	want := []Truncation{{Field: "extras", Length: 60, Limit: slackMaxExtraBlocks}}
+	if !slices.Equal(rec.truncations, want) {
+		t.Errorf("truncations = %v, want %v", rec.truncations, want)
+	}
+}
+
+func TestSlackMessageExtrasMeta(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  Message
+		want string
+	}{
+		{"重要度なし", Message{}, "ℹ️ info"},
+		{"重要度とタグとラベル", Message{Severity: SeverityError, Tags: []string{"batch"}, Labels: map[string]string{"env": "prod"}}, "🚨 error | `batch` | env: prod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extras := slackMessageExtras(tt.msg, nil)
+			if len(extras) != 1 {
+				t.Fatalf("got %d blocks, want only the meta context", len(extras))
+			}
+			context, ok := extras[0].(*slack.ContextBlock)
+			if !ok {
+				t.Fatalf("block = %T, want *slack.ContextBlock", extras[0])
+			}
+			if got := context.ContextElements.Elements[0].(*slack.TextBlockObject).Text; got != tt.want {
+				t.Errorf("meta = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
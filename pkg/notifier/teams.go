@@ -0,0 +1,355 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// TeamsNotifier は Microsoft Teams の Incoming Webhook および Workflows (Power Automate) の Webhook URL と連携するためのクライアントです。
+// Notifier インターフェースを実装します。メッセージは Adaptive Card として投稿されます。
+type TeamsNotifier struct {
+	// WebhookURL: Incoming Webhook または Workflows の「Teams webhook 要求を受信したとき」トリガーの URL
+	WebhookURL string
+	// httpClient: 汎用クライアント (リトライロジックを含む)
+	client httpkit.Client
+}
+
+// NewTeamsNotifier は TeamsNotifier の新しいインスタンスを作成します。
+func NewTeamsNotifier(client httpkit.Client, webhookURL string) (*TeamsNotifier, error) {
+	if webhookURL == "" {
+		return nil, errors.New("Teams への投稿には Webhook URL の設定が必要です")
+	}
+	return &TeamsNotifier{
+		WebhookURL: webhookURL,
+		client:     client,
+	}, nil
+}
+
+// TeamsErrorResponse は、Workflows の Webhook がリクエストを受け付けなかった場合に返すエラーのレスポンスです。
+type TeamsErrorResponse struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// TeamsError は Teams の Webhook から返されるエラーを表すカスタムエラーです。
+type TeamsError struct {
+	StatusCode int
+	// Code: Workflows のエラーコード (例: "TriggerInputSchemaMismatch")。Incoming Webhook の場合は空
+	Code    string
+	Message string
+}
+
+func (e *TeamsError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("Teams webhook error (status %d): %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("Teams webhook error (status %d, code %s): %s", e.StatusCode, e.Code, e.Message)
+}
+
+// --- Adaptive Card のペイロード ---
+
+// Adaptive Card のスキーマと、Teams が対応するバージョン
+const (
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.5"
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+)
+
+// teamsPayload は、Adaptive Card を添付した Teams の Webhook メッセージです。
+type teamsPayload struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+// teamsAttachment は、Webhook メッセージに添付するカードです。
+type teamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	ContentURL  *string      `json:"contentUrl"`
+	Content     adaptiveCard `json:"content"`
+}
+
+// adaptiveCard は Adaptive Card 本体です。
+type adaptiveCard struct {
+	Schema  string          `json:"$schema"`
+	Type    string          `json:"type"`
+	Version string          `json:"version"`
+	Body    []any           `json:"body"`
+	MSTeams adaptiveMSTeams `json:"msteams"`
+}
+
+// adaptiveMSTeams は、Teams 固有のカードの表示設定です。
+type adaptiveMSTeams struct {
+	// Width: "Full" の場合、カードをチャットの幅いっぱいに表示します
+	Width string `json:"width,omitempty"`
+}
+
+// adaptiveTextBlock は Adaptive Card の TextBlock 要素です。
+type adaptiveTextBlock struct {
+	Type      string `json:"type"`
+	Text      string `json:"text"`
+	Wrap      bool   `json:"wrap"`
+	Style     string `json:"style,omitempty"`
+	Size      string `json:"size,omitempty"`
+	Weight    string `json:"weight,omitempty"`
+	Color     string `json:"color,omitempty"`
+	FontType  string `json:"fontType,omitempty"`
+	IsSubtle  bool   `json:"isSubtle,omitempty"`
+	Separator bool   `json:"separator,omitempty"`
+	Spacing   string `json:"spacing,omitempty"`
+}
+
+// adaptiveFactSet は Adaptive Card の FactSet 要素です。
+type adaptiveFactSet struct {
+	Type      string         `json:"type"`
+	Facts     []adaptiveFact `json:"facts"`
+	Separator bool           `json:"separator,omitempty"`
+}
+
+// adaptiveFact は FactSet の名前と値の組です。
+type adaptiveFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// adaptiveActionSet は Adaptive Card の ActionSet 要素です。
+type adaptiveActionSet struct {
+	Type    string           `json:"type"`
+	Actions []adaptiveAction `json:"actions"`
+}
+
+// adaptiveAction は、URL を開くアクション (Action.OpenUrl) です。
+type adaptiveAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// --- Notifier インターフェース実装 ---
+
+// Send は、Message を Adaptive Card に変換して投稿します。
+// Title は見出し、Body は TextBlock、Fields は FactSet、Links は ActionSet のボタン、Severity と Tags はフッターとして描画されます。
+// ファイルのアップロードには対応していないため、Attachments は無視されます。
+// Webhook は投稿したメッセージの ID を返さないため、SendResult の ID と URL は常に空です。
+// Teams の上限を超えたために切り詰めた項目は、SendResult の Truncations に記録されます。
+func (t *TeamsNotifier) Send(ctx context.Context, msg Message) (*SendResult, error) {
+	var rec truncationRecorder
+	header := msg.Title
+	if header == "" {
+		header = defaultHeader(msg.Body)
+	}
+
+	card := buildTeamsCard(header, msg.Body, teamsMessageExtras(msg), severityOrDefault(msg.Severity), &rec)
+	if err := t.post(ctx, card); err != nil {
+		return nil, err
+	}
+	return &SendResult{Backend: "teams", Truncations: rec.truncations}, nil
+}
+
+// SendTextWithHeader は、ヘッダー付きのテキストメッセージを Adaptive Card に変換して投稿します。
+// headerText はカードの見出しとして、message は本文 (Markdown として解釈可能) として描画されます。
+func (t *TeamsNotifier) SendTextWithHeader(ctx context.Context, headerText string, message string) error {
+	return t.post(ctx, buildTeamsCard(headerText, message, nil, SeverityInfo, nil))
+}
+
+// SendText は、プレーンテキストメッセージを通知します。（ヘッダーなし）
+// 本文の1行目からデフォルトヘッダーを生成し、SendTextWithHeader にフォールバックします。
+func (t *TeamsNotifier) SendText(ctx context.Context, message string) error {
+	return t.SendTextWithHeader(ctx, defaultHeader(message), message)
+}
+
+// post は、Adaptive Card を Webhook メッセージとして送信します。
+func (t *TeamsNotifier) post(ctx context.Context, card adaptiveCard) error {
+	payload := teamsPayload{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: adaptiveCardContentType,
+			Content:     card,
+		}},
+	}
+
+	// 成功時は 2xx (Incoming Webhook は 200 OK とボディ "1"、Workflows は 202 Accepted と空のボディ) が返るため、
+	// 成否はステータスコードで判定し、ボディの内容は参照しない
+	if _, err := t.client.PostJSONAndFetchBytes(t.WebhookURL, payload, ctx); err != nil {
+		return fmt.Errorf("Teams Webhookメッセージの送信に失敗しました: %w", t.handleAPIError(err))
+	}
+	return nil
+}
+
+// handleAPIError は、httpkit から返されたエラーを TeamsError に変換します。
+// Workflows はエラーを JSON ({"error": {"code", "message"}}) で、Incoming Webhook はプレーンテキストで返します。
+func (t *TeamsNotifier) handleAPIError(err error) error {
+	var nonRetryable *httpkit.NonRetryableHTTPError
+	if !errors.As(err, &nonRetryable) {
+		// 5xx またはネットワークエラー
+		return err
+	}
+
+	var errorResp TeamsErrorResponse
+	if json.Unmarshal(nonRetryable.Body, &errorResp) == nil && errorResp.Error.Message != "" {
+		return &TeamsError{StatusCode: nonRetryable.StatusCode, Code: errorResp.Error.Code, Message: errorResp.Error.Message}
+	}
+	return &TeamsError{StatusCode: nonRetryable.StatusCode, Message: strings.TrimSpace(string(nonRetryable.Body))}
+}
+
+// --- Adaptive Card の構築 ---
+
+// buildTeamsCard は、見出し・本文・extras・フッター (送信時刻) から Adaptive Card を構築します。
+// 見出しは TeamsHeaderLimit 文字、本文は TeamsBodyLimit 文字を超える場合に切り詰められ、rec に記録されます (rec は nil でも構いません)。
+func buildTeamsCard(headerText, message string, extras []any, severity Severity, rec *truncationRecorder) adaptiveCard {
+	body := []any{adaptiveTextBlock{
+		Type:   "TextBlock",
+		Text:   rec.truncate("header", headerText, TeamsHeaderLimit),
+		Wrap:   true,
+		Style:  "heading",
+		Size:   "Large",
+		Weight: "Bolder",
+		Color:  teamsSeverityColor(severity),
+	}}
+
+	if TextLength(message) > TeamsBodyLimit {
+		rec.record("body", TextLength(message), TeamsBodyLimit)
+		message, _ = TruncateText(message, TeamsBodyLimit, "\n\n"+truncationSuffix)
+	}
+	body = append(body, teamsBodyElements(message)...)
+	body = append(body, extras...)
+
+	// フッターには送信時刻を含める
+	body = append(body, adaptiveTextBlock{
+		Type:     "TextBlock",
+		Text:     fmt.Sprintf("送信時刻: %s", time.Now().Format("2006-01-02 15:04:05")),
+		Wrap:     true,
+		Size:     "Small",
+		IsSubtle: true,
+		Spacing:  "Medium",
+	})
+
+	return adaptiveCard{
+		Schema:  adaptiveCardSchema,
+		Type:    "AdaptiveCard",
+		Version: adaptiveCardVersion,
+		Body:    body,
+		MSTeams: adaptiveMSTeams{Width: "Full"},
+	}
+}
+
+// teamsSeverityColor は、重要度に対応する見出しの色 (Adaptive Card の color) を返します。
+func teamsSeverityColor(s Severity) string {
+	switch s {
+	case SeveritySuccess:
+		return "Good"
+	case SeverityWarning:
+		return "Warning"
+	case SeverityError, SeverityCritical:
+		return "Attention"
+	default:
+		return ""
+	}
+}
+
+// teamsBodyElements は、Markdown の本文を Adaptive Card の TextBlock の列に変換します。
+// TextBlock は Markdown の一部 (強調・リスト・リンク) のみに対応するため、コードブロックは等幅フォントの TextBlock に、
+// 水平線は区切り線に、見出しは太字の TextBlock に変換します。それ以外のテキストは、そのまま TextBlock の Markdown として描画されます。
+func teamsBodyElements(message string) []any {
+	var elements []any
+	var lines []string
+	inFence := false
+	separator := false
+
+	flush := func() {
+		text := strings.Trim(strings.Join(lines, "\n"), "\n")
+		lines = nil
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		block := adaptiveTextBlock{Type: "TextBlock", Text: text, Wrap: true, Separator: separator}
+		if inFence {
+			block.FontType = "Monospace"
+		}
+		elements = append(elements, block)
+		separator = false
+	}
+
+	for _, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), codeFence) {
+			// コードブロックの開始・終了で、それまでのテキストを1つの TextBlock にする
+			flush()
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			lines = append(lines, line)
+			continue
+		}
+
+		if markdownRuleRegex.MatchString(line) {
+			flush()
+			separator = true
+			continue
+		}
+		if m := markdownHeadingRegex.FindStringSubmatch(line); m != nil {
+			flush()
+			elements = append(elements, adaptiveTextBlock{Type: "TextBlock", Text: m[1], Wrap: true, Weight: "Bolder", Separator: separator})
+			separator = false
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+
+	return elements
+}
+
+// teamsMessageExtras は、Message の Fields / Links / Severity / Tags を Adaptive Card の要素に変換します。
+func teamsMessageExtras(msg Message) []any {
+	var extras []any
+
+	if len(msg.Fields) > 0 {
+		facts := make([]adaptiveFact, 0, len(msg.Fields))
+		for _, f := range msg.Fields {
+			facts = append(facts, adaptiveFact{Title: f.Name, Value: f.Value})
+		}
+		extras = append(extras, adaptiveFactSet{Type: "FactSet", Facts: facts, Separator: true})
+	}
+
+	if len(msg.Links) > 0 {
+		actions := make([]adaptiveAction, 0, len(msg.Links))
+		for _, l := range msg.Links {
+			text := l.Text
+			if text == "" {
+				text = l.URL
+			}
+			actions = append(actions, adaptiveAction{Type: "Action.OpenUrl", Title: text, URL: l.URL})
+		}
+		extras = append(extras, adaptiveActionSet{Type: "ActionSet", Actions: actions})
+	}
+
+	var meta []string
+	if msg.Severity != "" {
+		meta = append(meta, fmt.Sprintf("%s %s", msg.Severity.Emoji(), msg.Severity))
+	}
+	if len(msg.Tags) > 0 {
+		meta = append(meta, strings.Join(msg.Tags, ", "))
+	}
+	for _, k := range sortedLabelKeys(msg.Labels) {
+		meta = append(meta, fmt.Sprintf("%s: %s", k, msg.Labels[k]))
+	}
+	if len(meta) > 0 {
+		extras = append(extras, adaptiveTextBlock{
+			Type:     "TextBlock",
+			Text:     strings.Join(meta, " | "),
+			Wrap:     true,
+			Size:     "Small",
+			IsSubtle: true,
+		})
+	}
+
+	return extras
+}
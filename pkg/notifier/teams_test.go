@@ -0,0 +1,216 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// newTestTeamsNotifier は、httptest のサーバーを Webhook URL とする TeamsNotifier を生成します。
+func newTestTeamsNotifier(t *testing.T, handler http.Handler) *TeamsNotifier {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := httpkit.New(5*time.Second, httpkit.WithMaxRetries(0))
+	tn, err := NewTeamsNotifier(*client, server.URL+"/workflows/1/triggers/manual/paths/invoke")
+	if err != nil {
+		t.Fatalf("NewTeamsNotifier: %v", err)
+	}
+	return tn
+}
+
+// teamsCardElements は、送信された Webhook メッセージから Adaptive Card の body の要素を取り出します。
+func teamsCardElements(t *testing.T, raw []byte) []map[string]any {
+	t.Helper()
+	var payload struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Schema  string           `json:"$schema"`
+				Type    string           `json:"type"`
+				Version string           `json:"version"`
+				Body    []map[string]any `json:"body"`
+				MSTeams map[string]any   `json:"msteams"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Type != "message" || len(payload.Attachments) != 1 {
+		t.Fatalf("payload = %s", raw)
+	}
+	a := payload.Attachments[0]
+	if a.ContentType != adaptiveCardContentType || a.Content.Type != "AdaptiveCard" || a.Content.Version != adaptiveCardVersion ||
+		a.Content.Schema != adaptiveCardSchema || a.Content.MSTeams["width"] != "Full" {
+		t.Errorf("attachment = %+v", a)
+	}
+	return a.Content.Body
+}
+
+func TestTeamsSendCard(t *testing.T) {
+	var raw []byte
+	tn := newTestTeamsNotifier(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	result, err := tn.Send(context.Background(), Message{
+		Title:    "バッチ失敗",
+		Body:     "## 概要\n夜間バッチが失敗しました\n---\n```\nexit status 1\n```",
+		Severity: SeverityError,
+		Fields:   []Field{{Name: "ジョブ", Value: "nightly"}},
+		Links:    []Link{{Text: "ログ", URL: "https://example.com/log"}, {URL: "https://example.com/run"}},
+		Tags:     []string{"batch"},
+		Labels:   map[string]string{"env": "prod"},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.Backend != "teams" || len(result.Truncations) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+
+	body := teamsCardElements(t, raw)
+	want := []map[string]any{
+		{"type": "TextBlock", "text": "バッチ失敗", "style": "heading", "weight": "Bolder", "color": "Attention"},
+		{"type": "TextBlock", "text": "概要", "weight": "Bolder"},
+		{"type": "TextBlock", "text": "夜間バッチが失敗しました"},
+		{"type": "TextBlock", "text": "exit status 1", "fontType": "Monospace", "separator": true},
+		{"type": "FactSet", "separator": true},
+		{"type": "ActionSet"},
+		{"type": "TextBlock", "text": "🚨 error | batch | env: prod", "isSubtle": true},
+		{"type": "TextBlock", "isSubtle": true, "spacing": "Medium"},
+	}
+	if len(body) != len(want) {
+		t.Fatalf("got %d elements, want %d: %v", len(body), len(want), body)
+	}
+	for i, w := range want {
+		for k, v := range w {
+			if body[i][k] != v {
+				t.Errorf("body[%d].%s = %v, want %v", i, k, body[i][k], v)
+			}
+		}
+	}
+
+	facts := body[4]["facts"].([]any)
+	if fact := facts[0].(map[string]any); len(facts) != 1 || fact["title"] != "ジョブ" || fact["value"] != "nightly" {
+		t.Errorf("facts = %v", facts)
+	}
+	actions := body[5]["actions"].([]any)
+	if len(actions) != 2 {
+		t.Fatalf("actions = %v", actions)
+	}
+	for i, want := range []map[string]any{
+		{"type": "Action.OpenUrl", "title": "ログ", "url": "https://example.com/log"},
+		{"type": "Action.OpenUrl", "title": "https://example.com/run", "url": "https://example.com/run"},
+	} {
+		action := actions[i].(map[string]any)
+		for k, v := range want {
+			if action[k] != v {
+				t.Errorf("actions[%d].%s = %v, want %v", i, k, action[k], v)
+			}
+		}
+	}
+	if footer := body[len(body)-1]["text"].(string); !strings.HasPrefix(footer, "送信時刻: ") {
+		t.Errorf("footer = %q", footer)
+	}
+}
+
+func TestTeamsSendTruncates(t *testing.T) {
+	var raw []byte
+	tn := newTestTeamsNotifier(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ = io.ReadAll(r.Body)
+		w.Write([]byte("1"))
+	}))
+
+	header := strings.Repeat("見", TeamsHeaderLimit+1)
+	result, err := tn.Send(context.Background(), Message{Title: header, Body: strings.Repeat("本", TeamsBodyLimit+1)})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	want := []Truncation{
+		{Field: "header", Length: TeamsHeaderLimit + 1, Limit: TeamsHeaderLimit},
+		{Field: "body", Length: TeamsBodyLimit + 1, Limit: TeamsBodyLimit},
+	}
+	if len(result.Truncations) != len(want) || result.Truncations[0] != want[0] || result.Truncations[1] != want[1] {
+		t.Errorf("truncations = %v, want %v", result.Truncations, want)
+	}
+
+	body := teamsCardElements(t, raw)
+	if n := TextLength(body[0]["text"].(string)); n != TeamsHeaderLimit {
+		t.Errorf("heading has %d characters, want %d", n, TeamsHeaderLimit)
+	}
+	if n := TextLength(body[1]["text"].(string)); n > TeamsBodyLimit {
+		t.Errorf("body has %d characters, want at most %d", n, TeamsBodyLimit)
+	}
+}
+
+func TestTeamsSendErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		want        TeamsError
+		wantSuccess bool
+	}{
+		{
+			name:   "Workflows のエラー",
+			status: http.StatusBadRequest,
+			body:   `{"error":{"code":"TriggerInputSchemaMismatch","message":"The input body for trigger 'manual' of type 'Request' did not match its schema definition."}}`,
+			want:   TeamsError{StatusCode: 400, Code: "TriggerInputSchemaMismatch", Message: "The input body for trigger 'manual' of type 'Request' did not match its schema definition."},
+		},
+		{
+			name:   "Incoming Webhook のエラー",
+			status: http.StatusBadRequest,
+			body:   "Summary or Text is required.\n",
+			want:   TeamsError{StatusCode: 400, Message: "Summary or Text is required."},
+		},
+		{
+			name:   "レート制限",
+			status: http.StatusTooManyRequests,
+			body:   "Microsoft Teams endpoint returned HTTP error 429",
+			want:   TeamsError{StatusCode: 429, Message: "Microsoft Teams endpoint returned HTTP error 429"},
+		},
+		{
+			// 成否はステータスコードで判定し、2xx のボディの内容は参照しない
+			name:        "2xx のボディに failed を含む",
+			status:      http.StatusOK,
+			body:        `{"status":"failed"}`,
+			wantSuccess: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tn := newTestTeamsNotifier(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+
+			_, err := tn.Send(context.Background(), Message{Body: "本文"})
+			if tt.wantSuccess {
+				if err != nil {
+					t.Fatalf("Send: %v", err)
+				}
+				return
+			}
+			var teamsErr *TeamsError
+			if !errors.As(err, &teamsErr) {
+				t.Fatalf("err = %v, want a *TeamsError", err)
+			}
+			if *teamsErr != tt.want {
+				t.Errorf("error = %+v, want %+v", *teamsErr, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// 通知先ごとのテキストの長さの上限 (文字数)。
+// Slack や Backlog はバイト数ではなく文字数で制限するため、上限はすべて文字 (rune) 単位です。
+const (
+	// SlackHeaderLimit: Slack の header ブロックのテキストの上限
+	SlackHeaderLimit = 150
+	// SlackSectionLimit: Slack の section ブロックのテキストの上限
+	SlackSectionLimit = 3000
+	// SlackFieldLimit: Slack の section ブロックのフィールド1つあたりのテキストの上限
+	SlackFieldLimit = 2000
+	// SlackButtonTextLimit: Slack のボタンのテキストの上限
+	SlackButtonTextLimit = 75
+	// BacklogSummaryLimit: Backlog の課題のサマリー (件名) の上限
+	BacklogSummaryLimit = 255
+	// TeamsHeaderLimit: Teams の Adaptive Card の見出しの上限 (見出しが折り返して本文を押し出さないよう、Discord のタイトルと同じ値)
+	TeamsHeaderLimit = 256
+	// TeamsBodyLimit: Teams の Adaptive Card の本文の上限 (メッセージ全体の 28KB の上限に収まるよう、日本語の本文を想定した値)
+	TeamsBodyLimit = 8000
+	// GoogleChatBodyLimit: Google Chat のカードの本文の上限 (メッセージ全体の 32KB の上限に収まるよう、日本語の本文を想定した値)
+	GoogleChatBodyLimit = 8000
+	// DiscordTitleLimit: Discord の埋め込み (embed) のタイトルの上限
+	DiscordTitleLimit = 256
+	// DiscordDescriptionLimit: Discord の埋め込みの説明 (本文) の上限
+	DiscordDescriptionLimit = 4096
+	// DiscordFieldNameLimit: Discord の埋め込みのフィールド名の上限
+	DiscordFieldNameLimit = 256
+	// DiscordFieldValueLimit: Discord の埋め込みのフィールドの値の上限
+	DiscordFieldValueLimit = 1024
+	// DiscordFooterLimit: Discord の埋め込みのフッターの上限
+	DiscordFooterLimit = 2048
+	// DiscordUsernameLimit: Discord の Webhook で上書きするユーザー名の上限
+	DiscordUsernameLimit = 80
+	// DiscordEmbedTotalLimit: Discord の1メッセージのすべての埋め込みのテキストの合計の上限
+	DiscordEmbedTotalLimit = 6000
+	// DiscordMaxFields: Discord の埋め込み1つあたりのフィールド数の上限
+	DiscordMaxFields = 25
+	// DiscordMaxEmbeds: Discord の1メッセージあたりの埋め込みの数の上限
+	DiscordMaxEmbeds = 10
+)
+
+// ellipsis は、切り詰めたテキストの末尾に付与する記号です。
+const ellipsis = "…"
+
+// Truncation は、通知先の上限を超えたためにテキストを切り詰めた (または省略した) 記録です。
+type Truncation struct {
+	// Field: 切り詰めた項目 (例: "header", "summary", "message")
+	Field string
+	// Length: 切り詰める前の長さ (文字数。"message" や "field" などを省略した場合は個数)
+	Length int
+	// Limit: 適用した上限
+	Limit int
+}
+
+// String は、切り詰めの内容を人が読める形式で返します。
+func (t Truncation) String() string {
+	return fmt.Sprintf("%s: %d → %d", t.Field, t.Length, t.Limit)
+}
+
+// TextLength は、通知先の上限と比較するためのテキストの長さ (文字数) を返します。
+func TextLength(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// TruncateText は、テキストが limit 文字を超える場合に、末尾に suffix を付与して limit 文字以内に切り詰めます。
+// 結合文字や絵文字の修飾子などで構成される書記素クラスタの途中では切らないため、結果は常に有効な UTF-8 です。
+// 2番目の戻り値は、切り詰めが行われたかを示します。
+func TruncateText(s string, limit int, suffix string) (string, bool) {
+	if limit <= 0 {
+		return "", s != ""
+	}
+	if TextLength(s) <= limit {
+		return s, false
+	}
+
+	budget := limit - TextLength(suffix)
+	if budget < 0 {
+		// suffix すら収まらない場合は、suffix なしで切り詰める
+		budget = limit
+		suffix = ""
+	}
+	return truncateGraphemes(s, budget) + suffix, true
+}
+
+// truncateGraphemes は、書記素クラスタの境界で budget 文字以内になるようにテキストを切り詰めます。
+func truncateGraphemes(s string, budget int) string {
+	var sb strings.Builder
+	length := 0
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		cluster := g.Str()
+		n := TextLength(cluster)
+		if length+n > budget {
+			break
+		}
+		sb.WriteString(cluster)
+		length += n
+	}
+	return sb.String()
+}
+
+// splitGraphemes は、書記素クラスタの境界で、テキストを limit 文字以下の断片に分割します。
+// 1つの書記素クラスタ (ZWJ で連結された絵文字など) だけで limit を超える場合に限り、クラスタを文字単位で分割します。
+func splitGraphemes(s string, limit int) []string {
+	if TextLength(s) <= limit || limit <= 0 {
+		return []string{s}
+	}
+
+	var pieces []string
+	var sb strings.Builder
+	length := 0
+	flush := func() {
+		if length > 0 {
+			pieces = append(pieces, sb.String())
+			sb.Reset()
+			length = 0
+		}
+	}
+
+	g := uniseg.NewGraphemes(s)
+	for g.Next() {
+		cluster := g.Str()
+		n := TextLength(cluster)
+		if length+n > limit {
+			flush()
+		}
+		if n > limit {
+			runes := []rune(cluster)
+			for len(runes) > limit {
+				pieces = append(pieces, string(runes[:limit]))
+				runes = runes[limit:]
+			}
+			cluster, n = string(runes), len(runes)
+		}
+		sb.WriteString(cluster)
+		length += n
+	}
+	flush()
+	return pieces
+}
+
+// truncationRecorder は、送信1回分の切り詰めを記録しながらテキストを切り詰めます。
+type truncationRecorder struct {
+	truncations []Truncation
+}
+
+// truncate は、テキストを limit 文字以内に切り詰め、切り詰めた場合は field として記録します。
+func (r *truncationRecorder) truncate(field, s string, limit int) string {
+	truncated, ok := TruncateText(s, limit, ellipsis)
+	if ok {
+		r.record(field, TextLength(s), limit)
+	}
+	return truncated
+}
+
+// record は、切り詰め (または省略) を記録します。
+func (r *truncationRecorder) record(field string, length, limit int) {
+	if r == nil {
+		return
+	}
+	r.truncations = append(r.truncations, Truncation{Field: field, Length: length, Limit: limit})
+}
@@ -0,0 +1,136 @@
+package notifier
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// テストで使用する、複数の文字からなる書記素クラスタ
+const (
+	// testFamily: ZWJ で連結された家族の絵文字 (5 文字)
+	testFamily = "👨‍👩‍👧"
+	// testEAcute: e と結合用アクセント記号 (2 文字)
+	testEAcute = "é"
+	// testFlag: 国旗の絵文字 (地域指示記号 2 文字)
+	testFlag = "🇯🇵"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name          string
+		s             string
+		limit         int
+		suffix        string
+		want          string
+		wantTruncated bool
+	}{
+		{"上限未満", "abc", 5, ellipsis, "abc", false},
+		{"CJK がちょうど上限", "あいうえお", 5, ellipsis, "あいうえお", false},
+		{"CJK が上限を1文字超える", "あいうえおか", 5, ellipsis, "あいうえ…", true},
+		{"ZWJ の絵文字の途中では切らない", "ab" + testFamily + "cd", 6, ellipsis, "ab…", true},
+		{"ZWJ の絵文字がちょうど収まる", "a" + testFamily + "cd", 7, ellipsis, "a" + testFamily + "…", true},
+		{"結合文字の途中では切らない", "caf" + testEAcute + "s", 5, ellipsis, "caf…", true},
+		{"国旗の途中では切らない", "日本" + testFlag + "東京", 4, ellipsis, "日本…", true},
+		{"suffix なし", "あいうえお", 3, "", "あいう", true},
+		{"suffix が上限を超える場合は suffix を付けない", "abcdef", 2, "...", "ab", true},
+		{"上限が 0", "abc", 0, ellipsis, "", true},
+		{"空文字列", "", 0, ellipsis, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := TruncateText(tt.s, tt.limit, tt.suffix)
+			if got != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("TruncateText(%q, %d, %q) = %q, %v; want %q, %v", tt.s, tt.limit, tt.suffix, got, truncated, tt.want, tt.wantTruncated)
+			}
+			if TextLength(got) > max(tt.limit, 0) {
+				t.Errorf("result %q has %d characters, want at most %d", got, TextLength(got), tt.limit)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("result %q is not valid UTF-8", got)
+			}
+		})
+	}
+}
+
+func TestSplitGraphemes(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		limit int
+		want  []string
+	}{
+		{"上限以内は分割しない", "あいう", 3, []string{"あいう"}},
+		{"CJK", "あいうえおかき", 3, []string{"あいう", "えおか", "き"}},
+		{"ZWJ の絵文字は分割しない", "ab" + testFamily + "c", 6, []string{"ab", testFamily + "c"}},
+		{"結合文字は分割しない", "ab" + testEAcute + "cd", 3, []string{"ab", testEAcute + "c", "d"}},
+		{"上限を超えるクラスタは文字単位で分割する", testFamily + "a", 3, []string{"👨‍👩", "‍👧a"}},
+		{"空文字列", "", 3, []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitGraphemes(tt.s, tt.limit)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitGraphemes(%q, %d) = %q, want %q", tt.s, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSplitGraphemesLimit は、さまざまな上限で、断片が上限を超えず、連結すると元のテキストに戻ることを確認します。
+func TestSplitGraphemesLimit(t *testing.T) {
+	s := strings.Repeat("日本語のテキスト "+testFamily+" caf"+testEAcute+" "+testFlag+" ", 5)
+	for limit := 1; limit <= 20; limit++ {
+		pieces := splitGraphemes(s, limit)
+		for i, p := range pieces {
+			if n := TextLength(p); n > limit || n == 0 {
+				t.Errorf("limit %d: piece %d %q has %d characters", limit, i, p, n)
+			}
+		}
+		if joined := strings.Join(pieces, ""); joined != s {
+			t.Errorf("limit %d: joined pieces differ from the input", limit)
+		}
+		// 上限が最長のクラスタ (5 文字) 以上であれば、クラスタの途中では分割しない
+		if limit >= TextLength(testFamily) {
+			clusters := 0
+			for _, p := range pieces {
+				clusters += uniseg.GraphemeClusterCount(p)
+			}
+			if want := uniseg.GraphemeClusterCount(s); clusters != want {
+				t.Errorf("limit %d: pieces have %d grapheme clusters, want %d", limit, clusters, want)
+			}
+		}
+	}
+}
+
+func TestTruncationRecorder(t *testing.T) {
+	var rec truncationRecorder
+	if got := rec.truncate("header", "短い見出し", 10); got != "短い見出し" {
+		t.Errorf("truncate = %q, want the text unchanged", got)
+	}
+	if got := rec.truncate("summary", "ab"+testFamily+"cd", 6); got != "ab…" {
+		t.Errorf("truncate = %q, want %q", got, "ab…")
+	}
+	rec.record("message", 12, 10)
+
+	want := []Truncation{
+		{Field: "summary", Length: 9, Limit: 6},
+		{Field: "message", Length: 12, Limit: 10},
+	}
+	if !slices.Equal(rec.truncations, want) {
+		t.Errorf("truncations = %v, want %v", rec.truncations, want)
+	}
+	if got := want[0].String(); got != "summary: 9 → 6" {
+		t.Errorf("Truncation.String() = %q", got)
+	}
+
+	// nil の recorder でも切り詰めは行われる
+	var none *truncationRecorder
+	if got := none.truncate("summary", "あいうえお", 3); got != "あい…" {
+		t.Errorf("nil recorder truncate = %q, want %q", got, "あい…")
+	}
+	none.record("message", 2, 1)
+}